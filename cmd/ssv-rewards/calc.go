@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/csv"
@@ -14,12 +15,11 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/networkconfig"
 	"github.com/gocarina/gocsv"
-	"github.com/volatiletech/sqlboiler/v4/boil"
-	"github.com/volatiletech/sqlboiler/v4/queries"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 
-	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/calcdb"
+	"github.com/bloxapp/ssv-rewards/pkg/database"
 	"github.com/bloxapp/ssv-rewards/pkg/precise"
 	"github.com/bloxapp/ssv-rewards/pkg/rewards"
 )
@@ -31,10 +31,13 @@ var tierCalculationCutoff = rewards.NewPeriod(2025, 11)
 
 type CalcCmd struct {
 	Dir                 string `default:"./rewards" help:"Path to save the rewards to,"`
-	PerformanceProvider string `default:"beaconcha" help:"Performance provider to use." enum:"beaconcha,e2m"`
+	PerformanceProvider string `default:"beaconcha" help:"Performance provider to use." enum:"beaconcha,e2m,beaconnode,file"`
+	SQLite              string `name:"sqlite" help:"Path of a SQLite file to calculate rewards from, instead of PostgreSQL. Created with 'snapshot import --sqlite'." type:"existingfile"`
+	RequireResolvedGaps bool   `env:"REQUIRE_RESOLVED_GAPS" help:"Refuse to calculate rounds with unresolved performance gaps (see status)."`
 
-	plan *rewards.Plan
-	db   *sql.DB
+	plan  *rewards.Plan
+	store calcdb.Store
+	db    *sql.DB
 }
 
 func (c *CalcCmd) Run(
@@ -43,18 +46,39 @@ func (c *CalcCmd) Run(
 	network networkconfig.NetworkConfig,
 	plan *rewards.Plan,
 ) error {
-	c.db = db
 	ctx := context.Background()
 
-	// Create or replace stored procedures.
-	rewardsSQL, err := os.ReadFile("rewards.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read rewards.sql: %w", err)
-	}
-	if _, err := db.ExecContext(ctx, string(rewardsSQL)); err != nil {
-		return fmt.Errorf("failed to execute rewards.sql: %w", err)
+	if c.SQLite != "" {
+		store, err := calcdb.OpenSQLite(c.SQLite)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		state, err := store.State(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get state from %s: %w", c.SQLite, err)
+		}
+		if state.NetworkName != network.Name {
+			return fmt.Errorf("%s holds data of network %s, want %s", c.SQLite, state.NetworkName, network.Name)
+		}
+		c.store = store
+		logger.Info("Calculating rewards from SQLite", zap.String("file", c.SQLite))
+	} else {
+		// Verify the network has been synced.
+		schema := database.NetworkSchema(network.Name)
+		exists, err := database.SchemaExists(ctx, db, schema)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("network %s has not been synced (schema %q not found)", network.Name, schema)
+		}
+		if err := database.CheckVersion(ctx, db); err != nil {
+			return err
+		}
+		c.store = calcdb.NewPostgresStore(db)
+		c.db = db
 	}
-	logger.Info("Applied stored procedures")
 
 	// Parse the rewards plan.
 	data, err := os.ReadFile("rewards.yaml")
@@ -138,35 +162,84 @@ func (c *CalcCmd) Run(
 
 func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error {
 	// 1. Validate state
-	state, err := models.States().One(ctx, c.db)
+	state, err := c.store.State(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get state: %w", err)
 	}
 	if state.EarliestValidatorPerformance.IsZero() || state.LatestValidatorPerformance.IsZero() {
 		return fmt.Errorf("validator performance data is not available")
 	}
-	if state.EarliestValidatorPerformance.Time.After(state.LatestValidatorPerformance.Time) {
+	if state.EarliestValidatorPerformance.After(state.LatestValidatorPerformance) {
 		return fmt.Errorf("invalid state: earliest validator performance is after latest validator performance")
 	}
-	if state.EarliestValidatorPerformance.Time.After(c.plan.Rounds[0].Period.FirstDay()) {
+	if state.EarliestValidatorPerformance.After(c.plan.Rounds[0].Period.FirstDay()) {
 		return fmt.Errorf("validator performance data is not available for the first round")
 	}
 
 	// 2. Filter complete rounds
-	var completeRounds []rewards.Round
+	var (
+		completeRounds []rewards.Round
+
+		// unpriced are the complete rounds skipped for lack of an SSV/ETH price, after
+		// which no round can be calculated without leaving them out of the cumulative
+		// rewards.
+		unpriced []rewards.Period
+	)
 	for _, round := range c.plan.Rounds {
 		if round.NetworkFee == nil {
 			round.NetworkFee = precise.NewETH(nil)
 		}
-		if round.ETHAPR.Float().Cmp(big.NewFloat(0)) == 1 &&
-			round.SSVETH.Float().Cmp(big.NewFloat(0)) == 1 &&
-			round.Period.LastDay().Before(state.LatestValidatorPerformance.Time.AddDate(0, 0, 1)) {
+		// Budget rounds don't depend on the ETH APR and SSV/ETH price,
+		// unless the ETH tree is limited separately by an inflation cap.
+		hasRates := round.ETHAPR != nil && round.ETHAPR.Float().Cmp(big.NewFloat(0)) == 1 &&
+			round.SSVETH != nil && round.SSVETH.Float().Cmp(big.NewFloat(0)) == 1
+		budgetOnly := round.Budget != nil &&
+			(!round.SeparateETHTreeLimit() || round.ETHTree.Budget != nil)
+		if !hasRates && !budgetOnly {
+			continue
+		}
+		complete := round.Period.LastDay().Before(state.LatestValidatorPerformance.AddDate(0, 0, 1))
+		// ETH-denominated ETH trees need an SSV/ETH price to convert rewards at.
+		if c.plan.StakingUpgrade != nil {
+			mechanics, err := c.plan.Mechanics.At(round.Period)
+			if err != nil {
+				return fmt.Errorf("failed to get mechanics for period %s: %w", round.Period, err)
+			}
+			ssvETH := round.ETHTreeSSVETH()
+			if mechanics.ETHTreeDenomination() == rewards.DenominationETH &&
+				(ssvETH == nil || ssvETH.Float().Sign() <= 0) {
+				if complete {
+					logger.Warn("Skipping complete round without an SSV/ETH price for its ETH-denominated ETH tree, cumulative rewards don't include it",
+						zap.String("period", round.Period.String()),
+					)
+					unpriced = append(unpriced, round.Period)
+				}
+				continue
+			}
+		}
+		if complete {
+			if len(unpriced) > 0 {
+				return fmt.Errorf(
+					"round %s follows round %s, which has complete performance data but no ssv_eth price for its ETH-denominated ETH tree",
+					round.Period, unpriced[0],
+				)
+			}
 			completeRounds = append(completeRounds, round)
 		}
 	}
 	if len(completeRounds) == 0 {
 		return fmt.Errorf("no rounds with available performance data")
 	}
+	for _, round := range completeRounds {
+		if err := c.checkQuarantinedDays(ctx, logger, round); err != nil {
+			return err
+		}
+		if c.RequireResolvedGaps {
+			if err := c.checkPerformanceGaps(ctx, logger, round); err != nil {
+				return err
+			}
+		}
+	}
 
 	// 3. Rewards by round
 	var (
@@ -184,12 +257,24 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 		ethTotalByValidator = map[string]*ValidatorParticipation{}
 		ethTotalByOwner     = map[string]*OwnerParticipation{}
 		ethTotalByRecipient = map[string]*RecipientParticipation{}
+
+		// ethDenomination is the token the ETH tree has been paid out in so far.
+		ethDenomination rewards.Denomination
 	)
 
 	legacyCalculationCutoff := c.plan.LegacyCalculationCutoff
 	hasMigrationSupport := c.plan.StakingUpgrade != nil
 
+	// Unspent budget or inflation cap carried over from the previous round,
+	// separately for the ETH tree when it has its own limit.
+	rollover := big.NewInt(0)
+	ethRollover := big.NewInt(0)
+
 	for _, round := range completeRounds {
+		rolloverIn, ethRolloverIn := rollover, ethRollover
+		round = round.WithRollover(rolloverIn).WithETHTreeRollover(ethRolloverIn)
+		rollover, ethRollover = big.NewInt(0), big.NewInt(0)
+
 		mechanics, err := c.plan.Mechanics.At(round.Period)
 		if err != nil {
 			return fmt.Errorf("failed to get mechanics for period %s: %w", round.Period, err)
@@ -237,91 +322,16 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 
 		// Add network fee address entries if configured
 		if mechanics.NetworkFeeAddress != (rewards.ExecutionAddress{}) {
-			totalFees := big.NewInt(0)
-			totalActiveDays := 0
-			totalRegisteredDays := 0
-
-			for _, p := range recipientParticipations {
-				if p.feeDeduction != nil {
-					totalFees.Add(totalFees, p.feeDeduction)
-					totalActiveDays += p.ActiveDays
-					totalRegisteredDays += p.RegisteredDays
-				}
-			}
-
-			if totalFees.Sign() > 0 {
-				networkFeeAddr := mechanics.NetworkFeeAddress.String()
-
-				ownerFeeEntry := &OwnerParticipation{
-					OwnerAddress:                    networkFeeAddr,
-					RecipientAddress:                networkFeeAddr,
-					Validators:                      0,
-					ActiveDays:                      totalActiveDays,
-					RegisteredDays:                  totalRegisteredDays,
-					TotalActiveEffectiveBalance:     0,
-					TotalRegisteredEffectiveBalance: 0,
-					feeDeduction:                    big.NewInt(0),
-					reward:                          totalFees,
-				}
-				ownerFeeEntry.Normalize()
-				ownerParticipations = append(ownerParticipations, ownerFeeEntry)
-
-				recipientFeeEntry := &RecipientParticipation{
-					RecipientAddress:                networkFeeAddr,
-					Validators:                      0,
-					ActiveDays:                      totalActiveDays,
-					RegisteredDays:                  totalRegisteredDays,
-					TotalActiveEffectiveBalance:     0,
-					TotalRegisteredEffectiveBalance: 0,
-					feeDeduction:                    big.NewInt(0),
-					reward:                          totalFees,
-				}
-				recipientFeeEntry.Normalize()
-				recipientParticipations = append(recipientParticipations, recipientFeeEntry)
-
-				byOwner = append(byOwner, &OwnerParticipationRound{
-					Round:              round.Period,
-					OwnerParticipation: ownerFeeEntry,
-				})
-
-				byRecipient = append(byRecipient, &RecipientParticipationRound{
-					Round:                  round.Period,
-					RecipientParticipation: recipientFeeEntry,
-				})
-
-				if existing, ok := totalByOwner[networkFeeAddr]; ok {
-					existing.ActiveDays += totalActiveDays
-					existing.reward = new(big.Int).Add(existing.reward, totalFees)
-				} else {
-					totalByOwner[networkFeeAddr] = &OwnerParticipation{
-						OwnerAddress:                    networkFeeAddr,
-						RecipientAddress:                networkFeeAddr,
-						Validators:                      0,
-						ActiveDays:                      totalActiveDays,
-						RegisteredDays:                  totalRegisteredDays,
-						TotalActiveEffectiveBalance:     0,
-						TotalRegisteredEffectiveBalance: 0,
-						feeDeduction:                    big.NewInt(0),
-						reward:                          new(big.Int).Set(totalFees),
-					}
-				}
-
-				if existing, ok := totalByRecipient[networkFeeAddr]; ok {
-					existing.ActiveDays += totalActiveDays
-					existing.reward = new(big.Int).Add(existing.reward, totalFees)
-				} else {
-					totalByRecipient[networkFeeAddr] = &RecipientParticipation{
-						RecipientAddress:                networkFeeAddr,
-						Validators:                      0,
-						ActiveDays:                      totalActiveDays,
-						RegisteredDays:                  totalRegisteredDays,
-						TotalActiveEffectiveBalance:     0,
-						TotalRegisteredEffectiveBalance: 0,
-						feeDeduction:                    big.NewInt(0),
-						reward:                          new(big.Int).Set(totalFees),
-					}
-				}
-			}
+			ownerParticipations, recipientParticipations = appendNetworkFeeEntries(
+				mechanics.NetworkFeeAddress,
+				round.Period,
+				ownerParticipations,
+				recipientParticipations,
+				&byOwner,
+				&byRecipient,
+				totalByOwner,
+				totalByRecipient,
+			)
 		}
 
 		// Export CSVs
@@ -358,8 +368,16 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 			return fmt.Errorf("failed to close cumulative.json: %w", err)
 		}
 
-		// ETH tree: accumulate, normalize, and export (no network fee).
+		// ETH tree: accumulate, normalize, and export.
 		if ethResults != nil && len(ethResults.validatorParticipations) > 0 {
+			if ethDenomination != "" && ethDenomination != ethResults.denomination {
+				return fmt.Errorf(
+					"ETH tree denomination changed from %s to %s in round %s: cumulative rewards cannot mix denominations",
+					ethDenomination, ethResults.denomination, round.Period,
+				)
+			}
+			ethDenomination = ethResults.denomination
+
 			ethVPs := ethResults.validatorParticipations
 			ethOPs := ethResults.ownerParticipations
 			ethRPs := ethResults.recipientParticipations
@@ -378,13 +396,27 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 				p.Normalize()
 			}
 
-			if err := exportCSV(ethVPs, filepath.Join(roundDir, "by-validator-eth.csv")); err != nil {
+			// ETH-tree network fees (if any) go to the ETH tree's fee address.
+			if feeAddress := mechanics.ETHTreeNetworkFeeAddress(); feeAddress != (rewards.ExecutionAddress{}) {
+				ethOPs, ethRPs = appendNetworkFeeEntries(
+					feeAddress,
+					round.Period,
+					ethOPs,
+					ethRPs,
+					&ethByOwner,
+					&ethByRecipient,
+					ethTotalByOwner,
+					ethTotalByRecipient,
+				)
+			}
+
+			if err := exportETHTreeCSV(ethVPs, ethDenomination, filepath.Join(roundDir, "by-validator-eth.csv")); err != nil {
 				return fmt.Errorf("export ETH validator rewards: %w", err)
 			}
-			if err := exportCSV(ethOPs, filepath.Join(roundDir, "by-owner-eth.csv")); err != nil {
+			if err := exportETHTreeCSV(ethOPs, ethDenomination, filepath.Join(roundDir, "by-owner-eth.csv")); err != nil {
 				return fmt.Errorf("export ETH owner rewards: %w", err)
 			}
-			if err := exportCSV(ethRPs, filepath.Join(roundDir, "by-recipient-eth.csv")); err != nil {
+			if err := exportETHTreeCSV(ethRPs, ethDenomination, filepath.Join(roundDir, "by-recipient-eth.csv")); err != nil {
 				return fmt.Errorf("export ETH recipient rewards: %w", err)
 			}
 		}
@@ -392,29 +424,28 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 		// Write cumulative ETH rewards for every round that has accumulated
 		// totals, even when the current round has no new ETH participations.
 		if len(ethTotalByRecipient) > 0 {
-			ethTotalRewards := map[string]string{}
-			for _, p := range ethTotalByRecipient {
-				ethTotalRewards["0x"+p.RecipientAddress] = p.reward.String()
+			ethTotalRewards := cumulativeETHTree{
+				Denomination: ethDenomination,
+				Rewards:      map[string]string{},
 			}
-			ef, err := os.Create(filepath.Join(roundDir, "cumulative-eth.json"))
-			if err != nil {
-				return fmt.Errorf("create cumulative-eth.json: %w", err)
-			}
-			eEnc := json.NewEncoder(ef)
-			eEnc.SetIndent("", "  ")
-			if err := eEnc.Encode(ethTotalRewards); err != nil {
-				ef.Close()
-				return fmt.Errorf("encode ETH total rewards: %w", err)
+			for _, p := range ethTotalByRecipient {
+				ethTotalRewards.Rewards["0x"+p.RecipientAddress] = p.reward.String()
 			}
-			if err := ef.Close(); err != nil {
-				return fmt.Errorf("close cumulative-eth.json: %w", err)
+			if err := exportJSON(ethTotalRewards, filepath.Join(roundDir, "cumulative-eth.json")); err != nil {
+				return fmt.Errorf("export ETH total rewards: %w", err)
 			}
 		}
 
 		var dailyReward, monthlyReward, annualReward *big.Int
-		if round.Period.Before(legacyCalculationCutoff) {
+		switch {
+		case round.Budget != nil:
+			// Budget rounds have no tier, so report the daily reward derived from the budget.
+			dailyReward = results.dailyReward
+			monthlyReward = new(big.Int).Mul(dailyReward, big.NewInt(int64(round.Period.Days())))
+			annualReward = new(big.Int).Mul(dailyReward, big.NewInt(365))
+		case round.Period.Before(legacyCalculationCutoff):
 			dailyReward, monthlyReward, annualReward, err = c.plan.ValidatorRewardsLegacy(round.Period, results.tier)
-		} else {
+		default:
 			dailyReward, monthlyReward, annualReward, err = c.plan.ValidatorRewards(round.Period, results.tier)
 		}
 		if err != nil {
@@ -424,38 +455,76 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 		logFields := []zap.Field{
 			zap.String("period", round.Period.String()),
 			zap.String("total_effective_balance", results.totalEffectiveBalance.Display()),
-			zap.String("tier", results.tier.MaxEffectiveBalance.Display()),
+		}
+		if results.tier != nil {
+			logFields = append(logFields, zap.String("tier", results.tier.MaxEffectiveBalance.Display()))
+		}
+		logFields = append(logFields,
 			zap.String("network_fee", round.NetworkFee.Display()),
 			zap.String("daily_reward", precise.NewETH(nil).SetWei(dailyReward).Display()),
 			zap.String("monthly_reward", precise.NewETH(nil).SetWei(monthlyReward).Display()),
 			zap.String("annual_reward", precise.NewETH(nil).SetWei(annualReward).Display()),
+		)
+
+		if round.Budget != nil {
+			logFields = append(logFields, zap.String("budget", round.Budget.Display()))
+		}
+
+		// Carry the unspent part of the budget or inflation cap over to the next round.
+		separateLimits := ethResults != nil && round.SeparateETHTreeLimit()
+		if round.Rollover {
+			spent := new(big.Int).Set(results.baseRewards.Wei())
+			if ethResults != nil && !separateLimits {
+				spent.Add(spent, ethResults.baseRewards.Wei())
+			}
+			if unspent := new(big.Int).Sub(round.Limit().Wei(), spent); unspent.Sign() > 0 {
+				rollover = unspent
+			}
+			if separateLimits {
+				ethUnspent := new(big.Int).Sub(round.ETHTree.Limit().Wei(), ethResults.baseRewards.Wei())
+				if ethUnspent.Sign() > 0 {
+					ethRollover = ethUnspent
+				}
+			}
+		}
+		if rolloverIn.Sign() > 0 || ethRolloverIn.Sign() > 0 || round.Rollover {
+			logFields = append(logFields,
+				zap.String("rollover_in", precise.NewETH(nil).SetWei(rolloverIn).Display()),
+				zap.String("rollover_out", precise.NewETH(nil).SetWei(rollover).Display()),
+			)
+			if separateLimits {
+				logFields = append(logFields,
+					zap.String("eth_rollover_in", precise.NewETH(nil).SetWei(ethRolloverIn).Display()),
+					zap.String("eth_rollover_out", precise.NewETH(nil).SetWei(ethRollover).Display()),
+				)
+			}
 		}
 
 		if round.InflationCap != nil {
-			// For two-tree rounds, compute combined original/final for scaling ratio.
+			// For two-tree rounds sharing the cap, compute combined original/final for scaling ratio.
 			combinedOriginal := new(big.Int).Set(results.originalRewards.Wei())
 			combinedFinal := new(big.Int).Set(results.finalRewards.Wei())
-			if ethResults != nil && len(ethResults.validatorParticipations) > 0 {
+			if ethResults != nil && len(ethResults.validatorParticipations) > 0 && !separateLimits {
 				combinedOriginal.Add(combinedOriginal, ethResults.originalRewards.Wei())
 				combinedFinal.Add(combinedFinal, ethResults.finalRewards.Wei())
 			}
 
-			scalingRatio := 1.0
-			if combinedOriginal.Sign() > 0 {
-				scalingRatioFloat := new(big.Float).Quo(
-					new(big.Float).SetInt(combinedFinal),
-					new(big.Float).SetInt(combinedOriginal),
-				)
-				scalingRatio, _ = scalingRatioFloat.Float64()
-			}
-
 			logFields = append(logFields,
 				zap.String("inflation_cap", round.InflationCap.Display()),
-				zap.Float64("scaling_ratio", scalingRatio),
+				zap.Float64("scaling_ratio", scalingRatio(combinedOriginal, combinedFinal)),
 				zap.String("original_rewards", precise.NewETH(nil).SetWei(combinedOriginal).Display()),
 				zap.String("final_rewards", precise.NewETH(nil).SetWei(combinedFinal).Display()),
 			)
 		}
+		if separateLimits && round.ETHTree.InflationCap != nil {
+			// The ETH tree is scaled to its own inflation cap.
+			logFields = append(logFields,
+				zap.String("eth_inflation_cap", round.ETHTree.InflationCap.Display()),
+				zap.Float64("eth_scaling_ratio", scalingRatio(ethResults.originalRewards.Wei(), ethResults.finalRewards.Wei())),
+				zap.String("eth_original_rewards", ethResults.originalRewards.Display()),
+				zap.String("eth_final_rewards", ethResults.finalRewards.Display()),
+			)
+		}
 
 		if ethResults != nil && len(ethResults.validatorParticipations) > 0 {
 			logFields = append(logFields,
@@ -464,6 +533,22 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 				zap.Int("ssv_validators", len(results.validatorParticipations)),
 				zap.Int("eth_validators", len(ethResults.validatorParticipations)),
 			)
+			if round.ETHTree != nil {
+				logFields = append(logFields, zap.String("eth_network_fee", round.ETHTreeNetworkFee().Display()))
+				if round.ETHTree.Budget != nil {
+					logFields = append(logFields, zap.String("eth_budget", round.ETHTree.Budget.Display()))
+				}
+			}
+			if mechanics.ETHTree != nil && mechanics.ETHTree.APRBoostMultiplier != nil {
+				logFields = append(logFields, zap.String("eth_apr_boost_multiplier", mechanics.ETHTree.APRBoostMultiplier.Display()))
+			}
+			if ethResults.denomination == rewards.DenominationETH {
+				logFields = append(logFields,
+					zap.String("eth_denomination", string(ethResults.denomination)),
+					zap.String("eth_ssv_eth", round.ETHTreeSSVETH().Display()),
+					zap.String("eth_final_rewards_eth", ethResults.payoutRewards.Display()),
+				)
+			}
 		}
 
 		logger.Info("Exported rewards for round", logFields...)
@@ -511,22 +596,22 @@ func (c *CalcCmd) run(ctx context.Context, logger *zap.Logger, dir string) error
 			r.Normalize()
 		}
 
-		if err := exportCSV(ethByValidator, filepath.Join(dir, "by-validator-eth.csv")); err != nil {
+		if err := exportETHTreeCSV(ethByValidator, ethDenomination, filepath.Join(dir, "by-validator-eth.csv")); err != nil {
 			return fmt.Errorf("export ETH total validator rewards: %w", err)
 		}
-		if err := exportCSV(ethByOwner, filepath.Join(dir, "by-owner-eth.csv")); err != nil {
+		if err := exportETHTreeCSV(ethByOwner, ethDenomination, filepath.Join(dir, "by-owner-eth.csv")); err != nil {
 			return fmt.Errorf("export ETH total owner rewards: %w", err)
 		}
-		if err := exportCSV(ethByRecipient, filepath.Join(dir, "by-recipient-eth.csv")); err != nil {
+		if err := exportETHTreeCSV(ethByRecipient, ethDenomination, filepath.Join(dir, "by-recipient-eth.csv")); err != nil {
 			return fmt.Errorf("export ETH total recipient rewards: %w", err)
 		}
-		if err := exportCSV(maps.Values(ethTotalByValidator), filepath.Join(dir, "total-by-validator-eth.csv")); err != nil {
+		if err := exportETHTreeCSV(maps.Values(ethTotalByValidator), ethDenomination, filepath.Join(dir, "total-by-validator-eth.csv")); err != nil {
 			return fmt.Errorf("export ETH total validator rewards: %w", err)
 		}
-		if err := exportCSV(maps.Values(ethTotalByOwner), filepath.Join(dir, "total-by-owner-eth.csv")); err != nil {
+		if err := exportETHTreeCSV(maps.Values(ethTotalByOwner), ethDenomination, filepath.Join(dir, "total-by-owner-eth.csv")); err != nil {
 			return fmt.Errorf("export ETH total owner rewards: %w", err)
 		}
-		if err := exportCSV(maps.Values(ethTotalByRecipient), filepath.Join(dir, "total-by-recipient-eth.csv")); err != nil {
+		if err := exportETHTreeCSV(maps.Values(ethTotalByRecipient), ethDenomination, filepath.Join(dir, "total-by-recipient-eth.csv")); err != nil {
 			return fmt.Errorf("export ETH total recipient rewards: %w", err)
 		}
 	}
@@ -678,14 +763,9 @@ func (c *CalcCmd) processRound(
 	} else {
 		totalEffectiveBalance = c.calculateTotalEffectiveBalance(validatorParticipations, round.Period.Days())
 	}
-	tier, err := c.plan.Tier(round.Period, totalEffectiveBalance)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tier: %w", err)
-	}
-
-	dailyReward, _, _, err := c.plan.ValidatorRewards(round.Period, tier)
+	tier, dailyReward, err := c.roundDailyReward(round, totalEffectiveBalance)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rewards: %w", err)
+		return nil, err
 	}
 
 	roundDays := round.Period.Days()
@@ -713,16 +793,12 @@ func (c *CalcCmd) processRound(
 		totalBaseReward.Add(totalBaseReward, baseReward)
 	}
 
-	// Scale the daily reward rate, if needed.
-	scaledDailyReward := new(big.Int).Set(dailyReward)
-	if round.InflationCap != nil &&
-		totalBaseReward.Sign() > 0 && totalBaseReward.Cmp(round.InflationCap.Wei()) > 0 {
-		scaledDailyReward.Mul(scaledDailyReward, round.InflationCap.Wei())
-		scaledDailyReward.Div(scaledDailyReward, totalBaseReward)
-	}
+	// Scale the daily reward rate to the budget or inflation cap, if needed.
+	scaledDailyReward := scaleDailyReward(dailyReward, totalBaseReward, round.Budget, round.InflationCap)
 
 	// Compute rewards/fees with the (potentially) scaled daily reward.
 	totalRoundRewards := big.NewInt(0)
+	totalScaledBaseReward := big.NewInt(0)
 	for _, v := range validatorParticipations {
 		var err error
 		v.reward, v.feeDeduction, err = c.calculateReward(
@@ -737,6 +813,8 @@ func (c *CalcCmd) processRound(
 			return nil, fmt.Errorf("failed to calculate validator reward: %w", err)
 		}
 		totalRoundRewards.Add(totalRoundRewards, v.reward)
+		totalScaledBaseReward.Add(totalScaledBaseReward, v.reward)
+		totalScaledBaseReward.Add(totalScaledBaseReward, v.feeDeduction)
 	}
 
 	originalRewards := precise.NewETH(nil).SetWei(originalRewardsWei)
@@ -751,14 +829,70 @@ func (c *CalcCmd) processRound(
 		recipientParticipations: recipientParticipations,
 		totalEffectiveBalance:   totalEffectiveBalance,
 		tier:                    tier,
+		dailyReward:             scaledDailyReward,
 		finalRewards:            finalRewards,
 		originalRewards:         originalRewards,
+		baseRewards:             precise.NewETH(nil).SetWei(totalScaledBaseReward),
 	}, nil
 }
 
+// budgetNominalDailyReward is the daily reward used to weigh validators against
+// each other in budget rounds, before it is scaled to match the budget.
+var budgetNominalDailyReward = big.NewInt(1e18)
+
+// roundDailyReward returns the tier and the (unscaled) daily reward for a round.
+// Budget rounds have no tier and use budgetNominalDailyReward.
+func (c *CalcCmd) roundDailyReward(
+	round rewards.Round,
+	totalEffectiveBalance *precise.ETH,
+) (*rewards.Tier, *big.Int, error) {
+	if round.Budget != nil {
+		return nil, new(big.Int).Set(budgetNominalDailyReward), nil
+	}
+	tier, err := c.plan.Tier(round.Period, totalEffectiveBalance)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get tier: %w", err)
+	}
+	dailyReward, _, _, err := c.plan.ValidatorRewards(round.Period, tier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get rewards: %w", err)
+	}
+	return tier, dailyReward, nil
+}
+
+// scalingRatio returns the ratio of the final rewards to the original rewards, or 1
+// if there were no original rewards.
+func scalingRatio(original, final *big.Int) float64 {
+	if original.Sign() <= 0 {
+		return 1
+	}
+	ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(final), new(big.Float).SetInt(original)).Float64()
+	return ratio
+}
+
+// scaleDailyReward scales the daily reward so that the total base reward
+// (before fee deduction) matches the budget exactly, or doesn't exceed the
+// inflation cap. Both may be nil.
+func scaleDailyReward(dailyReward, totalBaseReward *big.Int, budget, inflationCap *precise.ETH) *big.Int {
+	scaled := new(big.Int).Set(dailyReward)
+	if totalBaseReward.Sign() <= 0 {
+		return scaled
+	}
+	switch {
+	case budget != nil:
+		scaled.Mul(scaled, budget.Wei())
+		scaled.Div(scaled, totalBaseReward)
+	case inflationCap != nil && totalBaseReward.Cmp(inflationCap.Wei()) > 0:
+		scaled.Mul(scaled, inflationCap.Wei())
+		scaled.Div(scaled, totalBaseReward)
+	}
+	return scaled
+}
+
 // processRoundWithMigration handles reward calculation for rounds with ETH migration support.
-// It queries both SSV and ETH validator participations, computes a combined effective balance
-// for tier selection, and applies a single shared inflation cap across both trees.
+// It queries both SSV and ETH validator participations and computes a combined effective balance
+// for tier selection. The ETH tree may override the network fee and APR boost, and either shares
+// the round's budget or inflation cap with the SSV tree or is limited separately.
 func (c *CalcCmd) processRoundWithMigration(
 	ctx context.Context,
 	round rewards.Round,
@@ -785,79 +919,90 @@ func (c *CalcCmd) processRoundWithMigration(
 	} else {
 		totalEffectiveBalance = c.calculateTotalEffectiveBalance(allVPs, round.Period.Days())
 	}
-	tier, err := c.plan.Tier(round.Period, totalEffectiveBalance)
-	if err != nil {
-		return nil, nil, fmt.Errorf("get tier: %w", err)
-	}
 
-	dailyReward, _, _, err := c.plan.ValidatorRewards(round.Period, tier)
-	if err != nil {
-		return nil, nil, fmt.Errorf("get rewards: %w", err)
+	// Each tree either derives its daily reward from the tier, or weighs its
+	// validators with a nominal daily reward when it is limited by a budget.
+	separateLimits := round.SeparateETHTreeLimit()
+	ssvBudget := round.Budget != nil
+	ethBudget := round.Budget != nil
+	if separateLimits {
+		ethBudget = round.ETHTree.Budget != nil
 	}
 
-	roundDays := round.Period.Days()
-	ssvNetworkFee := round.NetworkFee.Wei()
-	ethNetworkFee := big.NewInt(0)
-
-	// First pass: compute combined base reward for inflation cap.
-	totalBaseReward := big.NewInt(0)
-	ssvOriginalWei := big.NewInt(0)
-	ethOriginalWei := big.NewInt(0)
+	var tier *rewards.Tier
+	if !ssvBudget || !ethBudget {
+		tier, err = c.plan.Tier(round.Period, totalEffectiveBalance)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get tier: %w", err)
+		}
+	}
+	ethTier := mechanics.ETHTreeTier(tier)
 
-	for _, v := range ssvVPs {
-		reward, fee, err := c.calculateReward(
-			v.TotalActiveEffectiveBalance, v.TotalRegisteredEffectiveBalance,
-			v.RegisteredDays, roundDays, dailyReward, ssvNetworkFee,
-		)
+	ssvDailyReward := new(big.Int).Set(budgetNominalDailyReward)
+	if !ssvBudget {
+		ssvDailyReward, _, _, err = c.plan.ValidatorRewards(round.Period, tier)
 		if err != nil {
-			return nil, nil, fmt.Errorf("calculate SSV base reward: %w", err)
+			return nil, nil, fmt.Errorf("get rewards: %w", err)
 		}
-		ssvOriginalWei.Add(ssvOriginalWei, reward)
-		totalBaseReward.Add(totalBaseReward, new(big.Int).Add(reward, fee))
 	}
-	for _, v := range ethVPs {
-		reward, fee, err := c.calculateReward(
-			v.TotalActiveEffectiveBalance, v.TotalRegisteredEffectiveBalance,
-			v.RegisteredDays, roundDays, dailyReward, ethNetworkFee,
-		)
+	ethDailyReward := new(big.Int).Set(budgetNominalDailyReward)
+	switch {
+	case !ethBudget:
+		ethDailyReward, _, _, err = c.plan.ValidatorRewards(round.Period, ethTier)
 		if err != nil {
-			return nil, nil, fmt.Errorf("calculate ETH base reward: %w", err)
+			return nil, nil, fmt.Errorf("get ETH rewards: %w", err)
 		}
-		ethOriginalWei.Add(ethOriginalWei, reward)
-		totalBaseReward.Add(totalBaseReward, new(big.Int).Add(reward, fee))
+	case !separateLimits && mechanics.ETHTree != nil && mechanics.ETHTree.APRBoostMultiplier != nil:
+		// Within a shared budget, the multiplier weighs ETH-tree validators against SSV-tree ones.
+		ethDailyReward = precise.NewETH(nil).Mul(
+			precise.NewETH(nil).SetWei(ethDailyReward),
+			mechanics.ETHTree.APRBoostMultiplier,
+		).Wei()
 	}
 
-	// Scale daily reward if inflation cap exceeded.
-	scaledDailyReward := new(big.Int).Set(dailyReward)
-	if round.InflationCap != nil &&
-		totalBaseReward.Sign() > 0 && totalBaseReward.Cmp(round.InflationCap.Wei()) > 0 {
-		scaledDailyReward.Mul(scaledDailyReward, round.InflationCap.Wei())
-		scaledDailyReward.Div(scaledDailyReward, totalBaseReward)
+	roundDays := round.Period.Days()
+	ssvNetworkFee := round.NetworkFee.Wei()
+	ethNetworkFee := round.ETHTreeNetworkFee().Wei()
+
+	// First pass: compute base rewards for the budget or inflation cap.
+	ssvOriginalWei, ssvBaseWei, err := c.applyRewards(ssvVPs, roundDays, ssvDailyReward, ssvNetworkFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("calculate SSV base reward: %w", err)
+	}
+	ethOriginalWei, ethBaseWei, err := c.applyRewards(ethVPs, roundDays, ethDailyReward, ethNetworkFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("calculate ETH base reward: %w", err)
 	}
 
-	// Second pass: compute final rewards with (potentially) scaled daily reward.
-	ssvFinalWei := big.NewInt(0)
-	for _, v := range ssvVPs {
-		v.reward, v.feeDeduction, err = c.calculateReward(
-			v.TotalActiveEffectiveBalance, v.TotalRegisteredEffectiveBalance,
-			v.RegisteredDays, roundDays, scaledDailyReward, ssvNetworkFee,
-		)
-		if err != nil {
-			return nil, nil, fmt.Errorf("calculate SSV final reward: %w", err)
-		}
-		ssvFinalWei.Add(ssvFinalWei, v.reward)
+	// Scale daily rewards to the budget or inflation cap, if needed.
+	var ssvScaledDailyReward, ethScaledDailyReward *big.Int
+	if separateLimits {
+		ssvScaledDailyReward = scaleDailyReward(ssvDailyReward, ssvBaseWei, round.Budget, round.InflationCap)
+		ethScaledDailyReward = scaleDailyReward(ethDailyReward, ethBaseWei, round.ETHTree.Budget, round.ETHTree.InflationCap)
+	} else {
+		totalBaseReward := new(big.Int).Add(ssvBaseWei, ethBaseWei)
+		ssvScaledDailyReward = scaleDailyReward(ssvDailyReward, totalBaseReward, round.Budget, round.InflationCap)
+		ethScaledDailyReward = scaleDailyReward(ethDailyReward, totalBaseReward, round.Budget, round.InflationCap)
 	}
 
-	ethFinalWei := big.NewInt(0)
-	for _, v := range ethVPs {
-		v.reward, v.feeDeduction, err = c.calculateReward(
-			v.TotalActiveEffectiveBalance, v.TotalRegisteredEffectiveBalance,
-			v.RegisteredDays, roundDays, scaledDailyReward, ethNetworkFee,
-		)
+	// Second pass: compute final rewards with (potentially) scaled daily rewards.
+	ssvFinalWei, ssvBaseWei, err := c.applyRewards(ssvVPs, roundDays, ssvScaledDailyReward, ssvNetworkFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("calculate SSV final reward: %w", err)
+	}
+	ethFinalWei, ethBaseWei, err := c.applyRewards(ethVPs, roundDays, ethScaledDailyReward, ethNetworkFee)
+	if err != nil {
+		return nil, nil, fmt.Errorf("calculate ETH final reward: %w", err)
+	}
+
+	// Budgets and caps are in SSV, so ETH-denominated rewards are converted last.
+	denomination := mechanics.ETHTreeDenomination()
+	ethPayoutWei := ethFinalWei
+	if denomination == rewards.DenominationETH {
+		ethPayoutWei, err = convertRewardsToETH(ethVPs, round.ETHTreeSSVETH())
 		if err != nil {
-			return nil, nil, fmt.Errorf("calculate ETH final reward: %w", err)
+			return nil, nil, fmt.Errorf("convert ETH tree rewards to ETH: %w", err)
 		}
-		ethFinalWei.Add(ethFinalWei, v.reward)
 	}
 
 	ssvResults = &roundResults{
@@ -866,22 +1011,178 @@ func (c *CalcCmd) processRoundWithMigration(
 		recipientParticipations: c.aggregateByRecipient(ssvVPs),
 		totalEffectiveBalance:   totalEffectiveBalance,
 		tier:                    tier,
+		dailyReward:             ssvScaledDailyReward,
 		originalRewards:         precise.NewETH(nil).SetWei(ssvOriginalWei),
 		finalRewards:            precise.NewETH(nil).SetWei(ssvFinalWei),
+		baseRewards:             precise.NewETH(nil).SetWei(ssvBaseWei),
 	}
 	ethResults = &roundResults{
 		validatorParticipations: ethVPs,
 		ownerParticipations:     c.aggregateByOwner(ethVPs),
 		recipientParticipations: c.aggregateByRecipient(ethVPs),
 		totalEffectiveBalance:   totalEffectiveBalance,
-		tier:                    tier,
+		tier:                    ethTier,
+		dailyReward:             ethScaledDailyReward,
 		originalRewards:         precise.NewETH(nil).SetWei(ethOriginalWei),
 		finalRewards:            precise.NewETH(nil).SetWei(ethFinalWei),
+		baseRewards:             precise.NewETH(nil).SetWei(ethBaseWei),
+		denomination:            denomination,
+		payoutRewards:           precise.NewETH(nil).SetWei(ethPayoutWei),
 	}
 
 	return ssvResults, ethResults, nil
 }
 
+// applyRewards calculates and sets the reward and fee deduction of each validator,
+// and returns the total rewards after and before fee deduction.
+func (c *CalcCmd) applyRewards(
+	validators []*ValidatorParticipation,
+	roundDays int,
+	dailyReward *big.Int,
+	networkFee *big.Int,
+) (final *big.Int, base *big.Int, err error) {
+	final = big.NewInt(0)
+	base = big.NewInt(0)
+	for _, v := range validators {
+		v.reward, v.feeDeduction, err = c.calculateReward(
+			v.TotalActiveEffectiveBalance, v.TotalRegisteredEffectiveBalance,
+			v.RegisteredDays, roundDays, dailyReward, networkFee,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		final.Add(final, v.reward)
+		base.Add(base, v.reward)
+		base.Add(base, v.feeDeduction)
+	}
+	return final, base, nil
+}
+
+// convertRewardsToETH converts the reward and fee deduction of each validator
+// from SSV to ETH, rounding each down to the nearest wei, and returns the total
+// rewards in ETH. Owner and recipient totals are sums of these amounts, so they
+// never exceed the exact conversion.
+func convertRewardsToETH(validators []*ValidatorParticipation, ssvETH *precise.ETH) (*big.Int, error) {
+	if ssvETH == nil {
+		return nil, fmt.Errorf("missing ssv_eth")
+	}
+	total := big.NewInt(0)
+	for _, v := range validators {
+		reward, err := rewards.SSVToETH(v.reward, ssvETH)
+		if err != nil {
+			return nil, err
+		}
+		feeDeduction, err := rewards.SSVToETH(v.feeDeduction, ssvETH)
+		if err != nil {
+			return nil, err
+		}
+		v.reward, v.feeDeduction = reward, feeDeduction
+		total.Add(total, reward)
+	}
+	return total, nil
+}
+
+// appendNetworkFeeEntries adds entries for the network fee address collecting the fees
+// deducted from the round's recipients, and accumulates them into the totals.
+func appendNetworkFeeEntries(
+	feeAddress rewards.ExecutionAddress,
+	period rewards.Period,
+	ownerParticipations []*OwnerParticipation,
+	recipientParticipations []*RecipientParticipation,
+	byOwner *[]*OwnerParticipationRound,
+	byRecipient *[]*RecipientParticipationRound,
+	totalByOwner map[string]*OwnerParticipation,
+	totalByRecipient map[string]*RecipientParticipation,
+) ([]*OwnerParticipation, []*RecipientParticipation) {
+	totalFees := big.NewInt(0)
+	totalActiveDays := 0
+	totalRegisteredDays := 0
+
+	for _, p := range recipientParticipations {
+		if p.feeDeduction != nil {
+			totalFees.Add(totalFees, p.feeDeduction)
+			totalActiveDays += p.ActiveDays
+			totalRegisteredDays += p.RegisteredDays
+		}
+	}
+
+	if totalFees.Sign() > 0 {
+		networkFeeAddr := feeAddress.String()
+
+		ownerFeeEntry := &OwnerParticipation{
+			OwnerAddress:                    networkFeeAddr,
+			RecipientAddress:                networkFeeAddr,
+			Validators:                      0,
+			ActiveDays:                      totalActiveDays,
+			RegisteredDays:                  totalRegisteredDays,
+			TotalActiveEffectiveBalance:     0,
+			TotalRegisteredEffectiveBalance: 0,
+			feeDeduction:                    big.NewInt(0),
+			reward:                          totalFees,
+		}
+		ownerFeeEntry.Normalize()
+		ownerParticipations = append(ownerParticipations, ownerFeeEntry)
+
+		recipientFeeEntry := &RecipientParticipation{
+			RecipientAddress:                networkFeeAddr,
+			Validators:                      0,
+			ActiveDays:                      totalActiveDays,
+			RegisteredDays:                  totalRegisteredDays,
+			TotalActiveEffectiveBalance:     0,
+			TotalRegisteredEffectiveBalance: 0,
+			feeDeduction:                    big.NewInt(0),
+			reward:                          totalFees,
+		}
+		recipientFeeEntry.Normalize()
+		recipientParticipations = append(recipientParticipations, recipientFeeEntry)
+
+		*byOwner = append(*byOwner, &OwnerParticipationRound{
+			Round:              period,
+			OwnerParticipation: ownerFeeEntry,
+		})
+
+		*byRecipient = append(*byRecipient, &RecipientParticipationRound{
+			Round:                  period,
+			RecipientParticipation: recipientFeeEntry,
+		})
+
+		if existing, ok := totalByOwner[networkFeeAddr]; ok {
+			existing.ActiveDays += totalActiveDays
+			existing.reward = new(big.Int).Add(existing.reward, totalFees)
+		} else {
+			totalByOwner[networkFeeAddr] = &OwnerParticipation{
+				OwnerAddress:                    networkFeeAddr,
+				RecipientAddress:                networkFeeAddr,
+				Validators:                      0,
+				ActiveDays:                      totalActiveDays,
+				RegisteredDays:                  totalRegisteredDays,
+				TotalActiveEffectiveBalance:     0,
+				TotalRegisteredEffectiveBalance: 0,
+				feeDeduction:                    big.NewInt(0),
+				reward:                          new(big.Int).Set(totalFees),
+			}
+		}
+
+		if existing, ok := totalByRecipient[networkFeeAddr]; ok {
+			existing.ActiveDays += totalActiveDays
+			existing.reward = new(big.Int).Add(existing.reward, totalFees)
+		} else {
+			totalByRecipient[networkFeeAddr] = &RecipientParticipation{
+				RecipientAddress:                networkFeeAddr,
+				Validators:                      0,
+				ActiveDays:                      totalActiveDays,
+				RegisteredDays:                  totalRegisteredDays,
+				TotalActiveEffectiveBalance:     0,
+				TotalRegisteredEffectiveBalance: 0,
+				feeDeduction:                    big.NewInt(0),
+				reward:                          new(big.Int).Set(totalFees),
+			}
+		}
+	}
+
+	return ownerParticipations, recipientParticipations
+}
+
 func accumulateValidators(
 	participations []*ValidatorParticipation,
 	period rewards.Period,
@@ -1089,9 +1390,15 @@ type roundResults struct {
 	ownerParticipations     []*OwnerParticipation
 	recipientParticipations []*RecipientParticipation
 	totalEffectiveBalance   *precise.ETH
-	tier                    *rewards.Tier
-	finalRewards            *precise.ETH // Final rewards distributed (after scaling)
-	originalRewards         *precise.ETH // Original rewards before scaling
+	tier                    *rewards.Tier // nil for budget rounds
+	dailyReward             *big.Int      // Daily reward applied (after scaling)
+	finalRewards            *precise.ETH  // Final rewards distributed (after scaling)
+	originalRewards         *precise.ETH  // Original rewards before scaling
+	baseRewards             *precise.ETH  // Rewards before fee deduction (after scaling)
+
+	// ETH tree only: the payout token, and final rewards in it.
+	denomination  rewards.Denomination
+	payoutRewards *precise.ETH
 }
 
 func (c *CalcCmd) calculateReward(
@@ -1186,19 +1493,26 @@ func (c *CalcCmd) validatorParticipations(
 	ownerRedirectsSupport, validatorRedirectsSupport bool,
 	migrationFilter string,
 ) ([]*ValidatorParticipation, error) {
-	var participations []*ValidatorParticipation
-	return participations, queries.Raw(
-		"SELECT * FROM participations_by_validator($1, $2, $3, $4, $5, $6, $7, $8, $9)",
-		c.PerformanceProvider,
-		mechanics.Criteria.MinAttestationsPerDay,
-		mechanics.Criteria.MinDecidedsPerDay,
-		time.Time(period),
-		nil, // to_period can be nil for single-period queries
-		ownerRedirectsSupport,
-		validatorRedirectsSupport,
-		mechanics.PectraSupport,
-		migrationFilter,
-	).Bind(ctx, c.db, &participations)
+	rows, err := c.store.ValidatorParticipations(
+		ctx,
+		c.query(period, mechanics, ownerRedirectsSupport, validatorRedirectsSupport, migrationFilter),
+	)
+	if err != nil {
+		return nil, err
+	}
+	participations := make([]*ValidatorParticipation, len(rows))
+	for i, row := range rows {
+		participations[i] = &ValidatorParticipation{
+			RecipientAddress:                row.RecipientAddress,
+			OwnerAddress:                    row.OwnerAddress,
+			PublicKey:                       row.PublicKey,
+			ActiveDays:                      row.ActiveDays,
+			RegisteredDays:                  row.RegisteredDays,
+			TotalActiveEffectiveBalance:     row.TotalActiveEffectiveBalance,
+			TotalRegisteredEffectiveBalance: row.TotalRegisteredEffectiveBalance,
+		}
+	}
+	return participations, nil
 }
 
 type OwnerParticipation struct {
@@ -1240,19 +1554,26 @@ func (c *CalcCmd) ownerParticipations(
 	ownerRedirectsSupport, validatorRedirectsSupport bool,
 	migrationFilter string,
 ) ([]*OwnerParticipation, error) {
-	var participations []*OwnerParticipation
-	return participations, queries.Raw(
-		"SELECT * FROM participations_by_owner($1, $2, $3, $4, $5, $6, $7, $8, $9)",
-		c.PerformanceProvider,
-		mechanics.Criteria.MinAttestationsPerDay,
-		mechanics.Criteria.MinDecidedsPerDay,
-		time.Time(period),
-		nil,
-		ownerRedirectsSupport,
-		validatorRedirectsSupport,
-		mechanics.PectraSupport,
-		migrationFilter,
-	).Bind(ctx, c.db, &participations)
+	rows, err := c.store.OwnerParticipations(
+		ctx,
+		c.query(period, mechanics, ownerRedirectsSupport, validatorRedirectsSupport, migrationFilter),
+	)
+	if err != nil {
+		return nil, err
+	}
+	participations := make([]*OwnerParticipation, len(rows))
+	for i, row := range rows {
+		participations[i] = &OwnerParticipation{
+			OwnerAddress:                    row.OwnerAddress,
+			RecipientAddress:                row.RecipientAddress,
+			Validators:                      row.Validators,
+			ActiveDays:                      row.ActiveDays,
+			RegisteredDays:                  row.RegisteredDays,
+			TotalActiveEffectiveBalance:     row.TotalActiveEffectiveBalance,
+			TotalRegisteredEffectiveBalance: row.TotalRegisteredEffectiveBalance,
+		}
+	}
+	return participations, nil
 }
 
 type RecipientParticipation struct {
@@ -1293,19 +1614,44 @@ func (c *CalcCmd) recipientParticipations(
 	ownerRedirectsSupport, validatorRedirectsSupport bool,
 	migrationFilter string,
 ) ([]*RecipientParticipation, error) {
-	var participations []*RecipientParticipation
-	return participations, queries.Raw(
-		"SELECT * FROM participations_by_recipient($1, $2, $3, $4, $5, $6, $7, $8, $9)",
-		c.PerformanceProvider,
-		mechanics.Criteria.MinAttestationsPerDay,
-		mechanics.Criteria.MinDecidedsPerDay,
-		time.Time(period),
-		nil,
-		ownerRedirectsSupport,
-		validatorRedirectsSupport,
-		mechanics.PectraSupport,
-		migrationFilter,
-	).Bind(ctx, c.db, &participations)
+	rows, err := c.store.RecipientParticipations(
+		ctx,
+		c.query(period, mechanics, ownerRedirectsSupport, validatorRedirectsSupport, migrationFilter),
+	)
+	if err != nil {
+		return nil, err
+	}
+	participations := make([]*RecipientParticipation, len(rows))
+	for i, row := range rows {
+		participations[i] = &RecipientParticipation{
+			RecipientAddress:                row.RecipientAddress,
+			Validators:                      row.Validators,
+			ActiveDays:                      row.ActiveDays,
+			RegisteredDays:                  row.RegisteredDays,
+			TotalActiveEffectiveBalance:     row.TotalActiveEffectiveBalance,
+			TotalRegisteredEffectiveBalance: row.TotalRegisteredEffectiveBalance,
+		}
+	}
+	return participations, nil
+}
+
+// query returns the store query of a period's participations under the given mechanics.
+func (c *CalcCmd) query(
+	period rewards.Period,
+	mechanics *rewards.Mechanics,
+	ownerRedirectsSupport, validatorRedirectsSupport bool,
+	migrationFilter string,
+) calcdb.Query {
+	return calcdb.Query{
+		Provider:           c.PerformanceProvider,
+		MinAttestations:    mechanics.Criteria.MinAttestationsPerDay,
+		MinDecideds:        mechanics.Criteria.MinDecidedsPerDay,
+		Period:             time.Time(period),
+		OwnerRedirects:     ownerRedirectsSupport,
+		ValidatorRedirects: validatorRedirectsSupport,
+		PectraSupport:      mechanics.PectraSupport,
+		MigrationFilter:    migrationFilter,
+	}
 }
 
 func (c *CalcCmd) prepareRedirections(
@@ -1315,7 +1661,7 @@ func (c *CalcCmd) prepareRedirections(
 	// Check and populate Owner Redirects
 	ownerRedirectsSupport := len(mechanics.OwnerRedirects) > 0
 	if ownerRedirectsSupport {
-		if err := c.populateOwnerRedirectsTable(ctx, mechanics.OwnerRedirects); err != nil {
+		if err := c.store.SetOwnerRedirects(ctx, mechanics.OwnerRedirects); err != nil {
 			return false, false, fmt.Errorf("failed to populate owner redirects: %w", err)
 		}
 	}
@@ -1323,7 +1669,7 @@ func (c *CalcCmd) prepareRedirections(
 	// Check and populate Validator Redirects
 	validatorRedirectsSupport := len(mechanics.ValidatorRedirects) > 0
 	if validatorRedirectsSupport {
-		if err := c.populateValidatorRedirectsTable(ctx, mechanics.ValidatorRedirects); err != nil {
+		if err := c.store.SetValidatorRedirects(ctx, mechanics.ValidatorRedirects); err != nil {
 			return false, false, fmt.Errorf("failed to populate validator redirects: %w", err)
 		}
 	}
@@ -1332,110 +1678,6 @@ func (c *CalcCmd) prepareRedirections(
 	return ownerRedirectsSupport, validatorRedirectsSupport, nil
 }
 
-func (c *CalcCmd) populateOwnerRedirectsTable(
-	ctx context.Context,
-	redirects rewards.OwnerRedirects,
-) error {
-	// Truncate the owner_redirects table.
-	_, err := queries.Raw(
-		"TRUNCATE TABLE "+models.TableNames.OwnerRedirects,
-	).ExecContext(ctx, c.db)
-	if err != nil {
-		return fmt.Errorf("failed to truncate owner_redirects: %w", err)
-	}
-
-	// Verify that the table is empty.
-	count, err := models.OwnerRedirects().Count(ctx, c.db)
-	if err != nil {
-		return fmt.Errorf("failed to count owner_redirects: %w", err)
-	}
-	if count != 0 {
-		return fmt.Errorf("owner_redirects table was not truncated")
-	}
-
-	// Populate with given redirects.
-	tx, err := c.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-	for from, to := range redirects {
-		model := models.OwnerRedirect{
-			FromAddress: from.String(),
-			ToAddress:   to.String(),
-		}
-		if err := model.Insert(ctx, tx, boil.Infer()); err != nil {
-			return fmt.Errorf("failed to insert rewards_redirect: %w", err)
-		}
-	}
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	// Verify that the table is populated.
-	count, err = models.OwnerRedirects().Count(ctx, c.db)
-	if err != nil {
-		return fmt.Errorf("failed to count owner_redirects: %w", err)
-	}
-	if int(count) != len(redirects) {
-		return fmt.Errorf("owner_redirects table was not populated")
-	}
-
-	return nil
-}
-
-func (c *CalcCmd) populateValidatorRedirectsTable(
-	ctx context.Context,
-	redirects rewards.ValidatorRedirects,
-) error {
-	// Truncate the validator_redirects table.
-	_, err := queries.Raw(
-		"TRUNCATE TABLE "+models.TableNames.ValidatorRedirects,
-	).ExecContext(ctx, c.db)
-	if err != nil {
-		return fmt.Errorf("failed to truncate validator_redirects: %w", err)
-	}
-
-	// Verify that the table is empty.
-	count, err := models.ValidatorRedirects().Count(ctx, c.db)
-	if err != nil {
-		return fmt.Errorf("failed to count validator_redirects: %w", err)
-	}
-	if count != 0 {
-		return fmt.Errorf("validator_redirects table was not truncated")
-	}
-
-	// Populate with given redirects.
-	tx, err := c.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-	for pubkey, to := range redirects {
-		model := models.ValidatorRedirect{
-			PublicKey: pubkey.String(),
-			ToAddress: to.String(),
-		}
-		if err := model.Insert(ctx, tx, boil.Infer()); err != nil {
-			return fmt.Errorf("failed to insert rewards_redirect: %w", err)
-		}
-	}
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	// Verify that the table is populated.
-	count, err = models.ValidatorRedirects().Count(ctx, c.db)
-	if err != nil {
-		return fmt.Errorf("failed to count validator_redirects: %w", err)
-	}
-	if int(count) != len(redirects) {
-		return fmt.Errorf("validator_redirects table was not populated")
-	}
-
-	return nil
-}
-
 type Exclusion struct {
 	Day               time.Time
 	FromEpoch         phase0.Epoch
@@ -1457,26 +1699,13 @@ func (c *CalcCmd) exclusionsForRound(
 		return nil, fmt.Errorf("failed to get mechanics for period %s: %w", period, err)
 	}
 
-	var rows []struct {
-		Day               time.Time
-		FromEpoch         phase0.Epoch
-		ToEpoch           phase0.Epoch
-		PublicKey         string
-		StartBeaconStatus sql.NullString
-		EndBeaconStatus   sql.NullString
-		Events            sql.NullString
-		ExclusionReason   string
-	}
-
-	err = queries.Raw(
-		"SELECT * FROM exclusions_by_validator($1, $2, $3, $4, $5, $6)",
-		c.PerformanceProvider,
-		mechanics.Criteria.MinAttestationsPerDay,
-		mechanics.Criteria.MinDecidedsPerDay,
-		time.Time(period),
-		time.Time(period),
-		migrationFilter,
-	).Bind(ctx, c.db, &rows)
+	rows, err := c.store.Exclusions(ctx, calcdb.Query{
+		Provider:        c.PerformanceProvider,
+		MinAttestations: mechanics.Criteria.MinAttestationsPerDay,
+		MinDecideds:     mechanics.Criteria.MinDecidedsPerDay,
+		Period:          time.Time(period),
+		MigrationFilter: migrationFilter,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1488,9 +1717,9 @@ func (c *CalcCmd) exclusionsForRound(
 			FromEpoch:         row.FromEpoch,
 			ToEpoch:           row.ToEpoch,
 			PublicKey:         row.PublicKey,
-			StartBeaconStatus: row.StartBeaconStatus.String,
-			EndBeaconStatus:   row.EndBeaconStatus.String,
-			Events:            row.Events.String,
+			StartBeaconStatus: row.StartBeaconStatus,
+			EndBeaconStatus:   row.EndBeaconStatus,
+			Events:            row.Events,
 			ExclusionReason:   row.ExclusionReason,
 		}
 	}
@@ -1532,6 +1761,60 @@ func exportCSV(data any, fileName string) error {
 	return nil
 }
 
+// exportETHTreeCSV exports ETH-tree data like exportCSV, with an extra Denomination
+// column stating the token of the reward and fee columns.
+func exportETHTreeCSV(data any, denomination rewards.Denomination, fileName string) error {
+	b, err := gocsv.MarshalBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %q: %w", fileName, err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read marshalled %q: %w", fileName, err)
+	}
+	for i := range records {
+		if i == 0 {
+			records[i] = append(records[i], "Denomination")
+		} else {
+			records[i] = append(records[i], string(denomination))
+		}
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", fileName, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Comma = '\t' // Set tab delimiter locally
+	if err := w.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write %q: %w", fileName, err)
+	}
+	return nil
+}
+
+// cumulativeETHTree is the cumulative-eth.json file, which states the token its
+// rewards are denominated in, since the ETH tree may be paid in SSV or ETH.
+type cumulativeETHTree struct {
+	Denomination rewards.Denomination `json:"denomination"`
+	Rewards      map[string]string    `json:"rewards"`
+}
+
+func exportJSON(data any, fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", fileName, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		f.Close() // Close before returning error
+		return fmt.Errorf("failed to encode %q: %w", fileName, err)
+	}
+	return f.Close()
+}
+
 func exportRedirectsToCSV(redirects interface{}, fileName string) error {
 	type RedirectRow struct {
 		From string `csv:"from"`
@@ -1561,3 +1844,54 @@ func exportRedirectsToCSV(redirects interface{}, fileName string) error {
 
 	return exportCSV(rows, fileName)
 }
+
+// checkQuarantinedDays fails if the round has days that were synced without decided
+// counts, whose validators would be neither rewarded nor excluded.
+func (c *CalcCmd) checkQuarantinedDays(ctx context.Context, logger *zap.Logger, round rewards.Round) error {
+	days, err := c.store.QuarantinedDays(
+		ctx,
+		c.PerformanceProvider,
+		round.Period.FirstDay(),
+		round.Period.LastDay(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return nil
+	}
+	for _, day := range days {
+		logger.Warn("Quarantined day",
+			zap.String("day", day.Day.Format("2006-01-02")),
+			zap.String("decideds_source", day.DecidedsSource),
+			zap.String("problems", day.Problems),
+		)
+	}
+	return fmt.Errorf("round %s has %d quarantined days without decided counts, re-sync them once their decided counts are available", round.Period, len(days))
+}
+
+// checkPerformanceGaps fails if the round has unresolved performance gaps, which would
+// exclude validators that were attesting.
+func (c *CalcCmd) checkPerformanceGaps(ctx context.Context, logger *zap.Logger, round rewards.Round) error {
+	gaps, err := c.store.UnresolvedPerformanceGaps(
+		ctx,
+		c.PerformanceProvider,
+		round.Period.FirstDay(),
+		round.Period.LastDay(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(gaps) == 0 {
+		return nil
+	}
+	for _, gap := range gaps {
+		logger.Warn("Unresolved performance gap",
+			zap.String("day", gap.Day.Format("2006-01-02")),
+			zap.String("public_key", gap.PublicKey),
+			zap.Int("index", gap.Index),
+			zap.String("reason", gap.Reason),
+		)
+	}
+	return fmt.Errorf("round %s has %d unresolved performance gaps, re-sync them with sync --redo-days", round.Period, len(gaps))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+	"github.com/bloxapp/ssv-rewards/pkg/sync"
+)
+
+type ExportLogsCmd struct {
+	LogsFile   string `default:"logs.jsonl"   help:"Path to write the contract logs to, one JSON-encoded log per line."`
+	BlocksFile string `default:"blocks.jsonl" help:"Path to write the number, hash and timestamp of the logs' blocks to."`
+}
+
+func (c *ExportLogsCmd) Run(logger *zap.Logger, db *sql.DB) error {
+	ctx := context.Background()
+	if err := database.CheckVersion(ctx, db); err != nil {
+		return err
+	}
+
+	logsFile, err := os.Create(c.LogsFile)
+	if err != nil {
+		return fmt.Errorf("failed to create logs file: %w", err)
+	}
+	defer logsFile.Close()
+	blocksFile, err := os.Create(c.BlocksFile)
+	if err != nil {
+		return fmt.Errorf("failed to create blocks file: %w", err)
+	}
+	defer blocksFile.Close()
+
+	events, blocks, err := sync.ExportContractLogs(ctx, db, logsFile, blocksFile)
+	if err != nil {
+		return err
+	}
+	if err := logsFile.Close(); err != nil {
+		return fmt.Errorf("failed to write logs file: %w", err)
+	}
+	if err := blocksFile.Close(); err != nil {
+		return fmt.Errorf("failed to write blocks file: %w", err)
+	}
+
+	logger.Info("Exported contract logs",
+		zap.String("logs_file", c.LogsFile),
+		zap.String("blocks_file", c.BlocksFile),
+		zap.Int("events", events),
+		zap.Int("blocks", blocks),
+	)
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bloxapp/ssv/networkconfig"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+)
+
+type MigrateCmd struct {
+	Up     MigrateUpCmd     `cmd:"" default:"1" help:"Applies all pending migrations."`
+	Down   MigrateDownCmd   `cmd:""             help:"Reverts the most recently applied migrations."`
+	Status MigrateStatusCmd `cmd:""             help:"Lists migrations and whether they are applied."`
+}
+
+type MigrateUpCmd struct{}
+
+func (c *MigrateUpCmd) Run(
+	logger *zap.Logger,
+	db *sql.DB,
+	network networkconfig.NetworkConfig,
+) error {
+	ctx := context.Background()
+
+	schema := database.NetworkSchema(network.Name)
+	adopted, err := database.EnsureSchema(ctx, db, network.Name, schema)
+	if err != nil {
+		return err
+	}
+	if adopted {
+		logger.Info("Moved existing data to the network's schema", zap.String("schema", schema))
+	}
+
+	return migrateUp(ctx, logger, db)
+}
+
+func migrateUp(ctx context.Context, logger *zap.Logger, db *sql.DB) error {
+	applied, err := database.MigrateUp(ctx, db)
+	for _, migration := range applied {
+		logger.Info("Applied migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	}
+	if err != nil {
+		return err
+	}
+	version, err := database.CurrentVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	logger.Info("Database schema is up to date", zap.Int("version", version))
+	return nil
+}
+
+type MigrateDownCmd struct {
+	Steps int `default:"1" help:"Number of migrations to revert."`
+}
+
+func (c *MigrateDownCmd) Run(logger *zap.Logger, db *sql.DB) error {
+	ctx := context.Background()
+
+	reverted, err := database.MigrateDown(ctx, db, c.Steps)
+	for _, migration := range reverted {
+		logger.Info("Reverted migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	}
+	if err != nil {
+		return err
+	}
+	version, err := database.CurrentVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	logger.Info("Database schema reverted", zap.Int("version", version))
+	return nil
+}
+
+type MigrateStatusCmd struct{}
+
+func (c *MigrateStatusCmd) Run(db *sql.DB) error {
+	statuses, err := database.Status(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied at " + status.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bloxapp/ssv/networkconfig"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/reconcile"
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+)
+
+type ReconcileCmd struct {
+	ProviderA string    `arg:"" help:"Performance provider to compare (A)." enum:"beaconcha,e2m,beaconnode,file"`
+	ProviderB string    `arg:"" help:"Performance provider to compare with (B)." enum:"beaconcha,e2m,beaconnode,file"`
+	Dir       string    `default:"./reconcile" help:"Path to save the reports to."`
+	FromDay   time.Time `format:"2006-01-02"   help:"Only compare validator performance from this day (YYYY-MM-DD)."`
+	ToDay     time.Time `format:"2006-01-02"   help:"Only compare validator performance up to this day (YYYY-MM-DD)."`
+}
+
+func (c *ReconcileCmd) Run(
+	logger *zap.Logger,
+	db *sql.DB,
+	network networkconfig.NetworkConfig,
+	plan *rewards.Plan,
+) error {
+	ctx := context.Background()
+	if c.ProviderA == c.ProviderB {
+		return fmt.Errorf("providers must differ")
+	}
+	if err := database.CheckVersion(ctx, db); err != nil {
+		return err
+	}
+
+	performancesA, err := reconcile.Load(ctx, db, models.ProviderType(c.ProviderA), c.FromDay, c.ToDay)
+	if err != nil {
+		return err
+	}
+	performancesB, err := reconcile.Load(ctx, db, models.ProviderType(c.ProviderB), c.FromDay, c.ToDay)
+	if err != nil {
+		return err
+	}
+	report, err := reconcile.Compare(performancesA, performancesB, reconcile.PlanCriteria(plan))
+	if err != nil {
+		return fmt.Errorf("failed to compare providers: %w", err)
+	}
+	if len(report.OnlyA) > 0 || len(report.OnlyB) > 0 {
+		logger.Warn("Some days were synced by only one provider and aren't compared",
+			zap.Int(c.ProviderA, len(report.OnlyA)),
+			zap.Int(c.ProviderB, len(report.OnlyB)),
+		)
+	}
+	if len(report.Days) == 0 {
+		return fmt.Errorf("no days with performance from both %s and %s", c.ProviderA, c.ProviderB)
+	}
+
+	dir := filepath.Join(c.Dir, network.Name, c.ProviderA+"-"+c.ProviderB)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %q: %w", dir, err)
+	}
+	statusDifferences := report.StatusDifferences()
+	if err := exportCSV(report.Differences, filepath.Join(dir, "differences.csv")); err != nil {
+		return err
+	}
+	if err := exportCSV(statusDifferences, filepath.Join(dir, "status-differences.csv")); err != nil {
+		return err
+	}
+
+	logger.Info("Reconciled validator performance",
+		zap.String("a", c.ProviderA),
+		zap.String("b", c.ProviderB),
+		zap.Time("from", report.Days[0]),
+		zap.Time("to", report.Days[len(report.Days)-1]),
+		zap.Int("days", len(report.Days)),
+		zap.Int("validator_days", report.ValidatorDays),
+		zap.Int("differences", len(report.Differences)),
+		zap.Int("status_differences", len(statusDifferences)),
+		zap.String("dir", dir),
+	)
+	return nil
+}
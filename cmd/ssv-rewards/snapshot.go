@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bloxapp/ssv/networkconfig"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/calcdb"
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+	"github.com/bloxapp/ssv-rewards/pkg/snapshot"
+)
+
+type SnapshotCmd struct {
+	Export SnapshotExportCmd `cmd:"" help:"Exports the network's synced data to a snapshot file."`
+	Import SnapshotImportCmd `cmd:"" help:"Imports a snapshot file into the network's empty schema."`
+}
+
+type SnapshotExportCmd struct {
+	File    string    `arg:"" default:"snapshot.tar.gz" help:"Path to write the snapshot to."`
+	FromDay time.Time `format:"2006-01-02"               help:"Only include validator performance from this day (YYYY-MM-DD)."`
+	ToDay   time.Time `format:"2006-01-02"               help:"Only include validator performance and events up to this day (YYYY-MM-DD)."`
+}
+
+func (c *SnapshotExportCmd) Run(
+	logger *zap.Logger,
+	db *sql.DB,
+	network networkconfig.NetworkConfig,
+) error {
+	ctx := context.Background()
+	if err := database.CheckVersion(ctx, db); err != nil {
+		return err
+	}
+	version, err := database.CurrentVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(c.File)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := snapshot.Export(ctx, db, f, network.Name, version, snapshot.Options{
+		FromDay: c.FromDay,
+		ToDay:   c.ToDay,
+	})
+	if err != nil {
+		os.Remove(c.File)
+		return fmt.Errorf("failed to export snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	fields := []zap.Field{zap.String("file", c.File), zap.String("network", manifest.Network)}
+	for _, table := range manifest.Tables {
+		fields = append(fields, zap.Int(table.Name, table.Rows))
+	}
+	logger.Info("Exported snapshot", fields...)
+	return nil
+}
+
+type SnapshotImportCmd struct {
+	File   string `arg:"" help:"Path of the snapshot to import."`
+	SQLite string `name:"sqlite" help:"Path of a SQLite file to import into instead of PostgreSQL, for calc --sqlite. Created if missing."`
+}
+
+func (c *SnapshotImportCmd) Run(
+	logger *zap.Logger,
+	db *sql.DB,
+	network networkconfig.NetworkConfig,
+) error {
+	ctx := context.Background()
+
+	f, err := os.Open(c.File)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	var manifest *snapshot.Manifest
+	if c.SQLite != "" {
+		manifest, err = c.importSQLite(ctx, f, network)
+	} else {
+		manifest, err = c.importPostgres(ctx, logger, db, f, network)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import snapshot: %w", err)
+	}
+
+	fields := []zap.Field{
+		zap.String("file", c.File),
+		zap.String("network", manifest.Network),
+		zap.Time("created_at", manifest.CreatedAt),
+	}
+	if c.SQLite != "" {
+		fields = append(fields, zap.String("sqlite", c.SQLite))
+	}
+	if manifest.FromDay != "" {
+		fields = append(fields, zap.String("from_day", manifest.FromDay))
+	}
+	if manifest.ToDay != "" {
+		fields = append(fields, zap.String("to_day", manifest.ToDay))
+	}
+	for _, table := range manifest.Tables {
+		fields = append(fields, zap.Int(table.Name, table.Rows))
+	}
+	logger.Info("Imported snapshot", fields...)
+	return nil
+}
+
+func (c *SnapshotImportCmd) importPostgres(
+	ctx context.Context,
+	logger *zap.Logger,
+	db *sql.DB,
+	r io.Reader,
+	network networkconfig.NetworkConfig,
+) (*snapshot.Manifest, error) {
+	// Prepare the network's schema, so that snapshots can be imported into a new database.
+	schema := database.NetworkSchema(network.Name)
+	if _, err := database.EnsureSchema(ctx, db, network.Name, schema); err != nil {
+		return nil, err
+	}
+	if err := migrateUp(ctx, logger, db); err != nil {
+		return nil, err
+	}
+	version, err := database.CurrentVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Import(ctx, db, r, network.Name, version)
+}
+
+func (c *SnapshotImportCmd) importSQLite(
+	ctx context.Context,
+	r io.Reader,
+	network networkconfig.NetworkConfig,
+) (*snapshot.Manifest, error) {
+	// The SQLite queries are those of the latest schema version,
+	// so snapshots must have been exported at that version.
+	version, err := database.LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+	store, err := calcdb.OpenSQLite(c.SQLite)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return store.ImportSnapshot(ctx, r, network.Name, version)
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/networks"
+	"github.com/bloxapp/ssv-rewards/pkg/status"
+	"github.com/bloxapp/ssv-rewards/pkg/sync"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds/exporter"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance/beaconcha"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance/beaconnode"
+)
+
+type StatusCmd struct {
+	DataDir           string    `env:"DATA_DIR"           default:"./data"    help:"Path to the data directory."`
+	ExecutionEndpoint string    `env:"EXECUTION_ENDPOINT"                     help:"RPC endpoint to an Ethereum execution node, to report the lag behind its finalized block."`
+	ConsensusEndpoint string    `env:"CONSENSUS_ENDPOINT"                     help:"HTTP endpoint to an Ethereum Beacon node API, to compare each day's rows with the validators active that day."`
+	FromDay           time.Time `format:"2006-01-02"                          help:"Only report validator performance from this day (YYYY-MM-DD)."`
+	ToDay             time.Time `format:"2006-01-02"                          help:"Only report validator performance up to this day (YYYY-MM-DD)."`
+	AnomaliesOnly     bool      `help:"Only list days with anomalies."`
+}
+
+// cacheProviders are the providers with data in the key-value cache.
+var cacheProviders = []string{
+	string(beaconcha.ProviderType),
+	string(beaconnode.ProviderType),
+	sync.SSVCacheProvider,
+	string(exporter.SourceType),
+}
+
+func (c *StatusCmd) Run(
+	logger *zap.Logger,
+	db *sql.DB,
+	network networkconfig.NetworkConfig,
+	ssvAPINetwork networks.SSVAPINetwork,
+) error {
+	ctx := context.Background()
+	if err := database.CheckVersion(ctx, db); err != nil {
+		return err
+	}
+
+	state, err := models.States().One(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to get state: %w", err)
+	}
+	if err := c.printState(ctx, state); err != nil {
+		return err
+	}
+	if err := c.printPerformanceDays(ctx, logger, db, ssvAPINetwork); err != nil {
+		return err
+	}
+	if err := printPerformanceGaps(ctx, db); err != nil {
+		return err
+	}
+	if err := printQuarantinedDays(ctx, db); err != nil {
+		return err
+	}
+
+	eventErrors, err := status.ContractEventErrors(ctx, db)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Contract events with errors:")
+	if len(eventErrors) == 0 {
+		fmt.Println("  none")
+	}
+	for _, e := range eventErrors {
+		fmt.Printf("  %s\t%d\n", e.EventName, e.Count)
+	}
+
+	indices, err := status.MissingValidatorIndices(ctx, db)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Validators without a beacon index: %d of %d (%d active)\n",
+		indices.WithoutIndex, indices.Validators, indices.ActiveWithoutIndex)
+
+	c.printCache(logger, filepath.Join(c.DataDir, network.Name, cacheDirName))
+	return nil
+}
+
+func (c *StatusCmd) printState(ctx context.Context, state *models.State) error {
+	fmt.Println("State:")
+	fmt.Printf("  network\t%s\n", state.NetworkName)
+	fmt.Printf("  blocks\t%d - %d\n", state.LowestBlockNumber, state.HighestBlockNumber)
+
+	if state.HighestBlockTime.Valid {
+		fmt.Printf("  highest block time\t%s (%s ago)\n",
+			state.HighestBlockTime.Time.UTC().Format(time.DateTime),
+			time.Since(state.HighestBlockTime.Time).Round(time.Second),
+		)
+	}
+	if c.ExecutionEndpoint != "" {
+		el, err := ethclient.DialContext(ctx, c.ExecutionEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to connect to execution node: %w", err)
+		}
+		defer el.Close()
+		finalized, err := el.HeaderByNumber(ctx, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		if err != nil {
+			return fmt.Errorf("failed to get finalized block: %w", err)
+		}
+		fmt.Printf("  lag\t%d blocks behind finalized block %d\n",
+			finalized.Number.Int64()-int64(state.HighestBlockNumber), finalized.Number)
+	}
+	fmt.Printf("  validator performance\t%s - %s\n",
+		formatDay(state.EarliestValidatorPerformance), formatDay(state.LatestValidatorPerformance))
+	return nil
+}
+
+// printPerformanceDays prints the rows of each provider by day, along with the
+// validators active that day if a consensus node is given, highlighting anomalies.
+func (c *StatusCmd) printPerformanceDays(
+	ctx context.Context,
+	logger *zap.Logger,
+	db *sql.DB,
+	ssvAPINetwork networks.SSVAPINetwork,
+) error {
+	days, err := status.PerformanceDays(ctx, db, c.FromDay, c.ToDay)
+	if err != nil {
+		return err
+	}
+	if c.ConsensusEndpoint != "" && len(days) > 0 {
+		_, spec, err := connectConsensus(ctx, logger, c.ConsensusEndpoint, ssvAPINetwork)
+		if err != nil {
+			return err
+		}
+		fromDay, toDay := days[0].Day, days[0].Day
+		for _, d := range days {
+			if d.Day.Before(fromDay) {
+				fromDay = d.Day
+			}
+			if d.Day.After(toDay) {
+				toDay = d.Day
+			}
+		}
+		active, err := sync.ActiveValidatorsByDay(ctx, db, spec, fromDay, toDay)
+		if err != nil {
+			return err
+		}
+		for _, d := range days {
+			d.Active = null.IntFrom(active[d.Day])
+		}
+	}
+
+	fmt.Println("Validator performance by day (provider, day, rows, active, missing attestations, missing decideds):")
+	listed := 0
+	for _, d := range days {
+		anomalies := d.Anomalies()
+		if c.AnomaliesOnly && len(anomalies) == 0 {
+			continue
+		}
+		listed++
+		active := "-"
+		if d.Active.Valid {
+			active = fmt.Sprint(d.Active.Int)
+		}
+		line := fmt.Sprintf("  %s\t%s\t%d\t%s\t%d\t%d",
+			d.Provider, d.Day.Format("2006-01-02"), d.Rows, active, d.MissingAttestations, d.MissingDecideds)
+		if len(anomalies) > 0 {
+			line += "\t! " + strings.Join(anomalies, ", ")
+		}
+		fmt.Println(line)
+	}
+	if listed == 0 {
+		fmt.Println("  none")
+	}
+	return nil
+}
+
+// printPerformanceGaps prints the number of performance gaps by provider and reason,
+// and the days with unresolved gaps.
+func printPerformanceGaps(ctx context.Context, db *sql.DB) error {
+	summaries, err := sync.SummarizePerformanceGaps(ctx, db)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Performance gaps:")
+	if len(summaries) == 0 {
+		fmt.Println("  none")
+		return nil
+	}
+	for _, s := range summaries {
+		fmt.Printf("  %s\t%s\tunresolved %d\tresolved %d\t%s - %s\n",
+			s.Provider, s.Reason, s.Unresolved, s.Resolved,
+			s.FirstDay.Format("2006-01-02"), s.LastDay.Format("2006-01-02"),
+		)
+	}
+
+	days, err := sync.UnresolvedPerformanceGapDays(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(days) > 0 {
+		fmt.Println("Days with unresolved performance gaps:")
+	}
+	for _, d := range days {
+		fmt.Printf("  %s\t%s\t%d\n", d.Provider, d.Day.Format("2006-01-02"), d.Gaps)
+	}
+	return nil
+}
+
+// printQuarantinedDays prints the days that were synced without decided counts, which
+// calc refuses until they're synced again.
+func printQuarantinedDays(ctx context.Context, db *sql.DB) error {
+	days, err := models.QuarantinedDays(qm.OrderBy("provider, day")).All(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to get quarantined days: %w", err)
+	}
+	fmt.Println("Quarantined days:")
+	if len(days) == 0 {
+		fmt.Println("  none")
+	}
+	for _, d := range days {
+		fmt.Printf("  %s\t%s\t%s\t%s\n", d.Provider, d.Day.Format("2006-01-02"), d.DecidedsSource, d.Problems)
+	}
+	return nil
+}
+
+// printCache prints the number of cached values per provider, and the size of the
+// cache on disk. The cache is locked while sync is running, so it's reported as
+// unavailable then.
+func (c *StatusCmd) printCache(logger *zap.Logger, dir string) {
+	fmt.Println("Cache:")
+	kvDir := filepath.Join(dir, "kv")
+	if _, err := os.Stat(kvDir); err == nil {
+		kvCache, err := cache.New(zap.NewNop(), kvDir)
+		if err != nil {
+			fmt.Printf("  unavailable: %v\n", err)
+		} else {
+			defer kvCache.Close()
+			for _, provider := range cacheProviders {
+				n, err := kvCache.Count(provider)
+				if err != nil {
+					logger.Warn("Failed to count cache", zap.String("provider", provider), zap.Error(err))
+					continue
+				}
+				fmt.Printf("  %s\t%d entries\n", provider, n)
+			}
+		}
+		files, size := dirSize(kvDir)
+		fmt.Printf("  kv\t%d files, %s on disk\n", files, formatBytes(size))
+	}
+}
+
+// dirSize returns the number and total size of the files in a directory.
+func dirSize(dir string) (files int, size int64) {
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDay(day null.Time) string {
+	if !day.Valid {
+		return "-"
+	}
+	return day.Time.Format("2006-01-02")
+}
@@ -5,14 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"math"
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
+	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/auto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/eth/contract"
 	"github.com/bloxapp/ssv/eth/eventparser"
 	"github.com/bloxapp/ssv/eth/executionclient"
 	"github.com/bloxapp/ssv/networkconfig"
@@ -27,27 +30,58 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/database"
 	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/networks"
+	"github.com/bloxapp/ssv-rewards/pkg/reconcile"
 	"github.com/bloxapp/ssv-rewards/pkg/rewards"
 	"github.com/bloxapp/ssv-rewards/pkg/sync"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds/exporter"
+	decidedsfile "github.com/bloxapp/ssv-rewards/pkg/sync/decideds/file"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds/ssvapi"
 	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
 	"github.com/bloxapp/ssv-rewards/pkg/sync/performance/beaconcha"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance/beaconnode"
 	"github.com/bloxapp/ssv-rewards/pkg/sync/performance/e2m"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance/file"
 )
 
 type SyncCmd struct {
-	DataDir                    string  `env:"DATA_DIR"                       default:"./data"               help:"Path to the data directory."`
-	ExecutionEndpoint          string  `env:"EXECUTION_ENDPOINT"                                            help:"RPC endpoint to an Ethereum execution node."                                        required:""`
-	ConsensusEndpoint          string  `env:"CONSENSUS_ENDPOINT"                                            help:"HTTP endpoint to an Ethereum Beacon node API."                                      required:""`
-	SSVAPIEndpoint             string  `env:"SSV_API_ENDPOINT"                                              help:"HTTP endpoint to an SSV API."                                                       required:""`
-	E2MEndpoint                string  `env:"E2M_ENDPOINT"                                                  help:"HTTP endpoint to an ethereum2-monitor API."                                         required:"" xor:"monitoring-endpoint" name:"e2m-endpoint"`
-	BeaconchaEndpoint          string  `env:"BEACONCHA_ENDPOINT"             default:"https://beaconcha.in" help:"HTTP endpoint to a beaconcha.in API."                                               required:"" xor:"monitoring-endpoint"`
-	BeaconchaAPIKey            string  `env:"BEACONCHA_API_KEY"                                             help:"API key for beaconcha.in API."`
-	BeaconchaRequestsPerMinute float64 `env:"BEACONCHA_REQUESTS_PER_MINUTE"  default:"20"                   help:"Maximum number of requests per minute to beaconcha.in API."`
-	HighestExecutionBlock      uint64  `env:"HIGHEST_EXECUTION_BLOCK"                                       help:"Execution block number to end syncing at. Defaults to the highest finalized block."`
-	Fresh                      bool    `env:"FRESH"                                                         help:"Delete all data and start from scratch."`
-	FreshSSV                   bool    `env:"FRESH_SSV"                                                     help:"Delete all SSV data and start from scratch."`
-	KeepCache                  bool    `env:"KEEP_CACHE"                                                    help:"Preserve the .cache directory at <DATA_DIR>/<network>/.cache even when using --fresh or --fresh-ssv."`
+	DataDir                    string   `env:"DATA_DIR"                       default:"./data"               help:"Path to the data directory."`
+	ExecutionEndpoint          string   `env:"EXECUTION_ENDPOINT"                                            help:"RPC endpoint to an Ethereum execution node. Required unless --logs-file is given."`
+	LogsFile                   string   `env:"LOGS_FILE"                                                     help:"Path to a file of SSV registry contract logs to sync from instead of an execution node (see export-logs)."`
+	BlocksFile                 string   `env:"BLOCKS_FILE"                                                   help:"Path to a file of block numbers, hashes and timestamps for --logs-file."`
+	ConsensusEndpoint          string   `env:"CONSENSUS_ENDPOINT"                                            help:"HTTP endpoint to an Ethereum Beacon node API."                                      required:""`
+	SSVAPIEndpoint             string   `env:"SSV_API_ENDPOINT"                                              help:"HTTP endpoint to an SSV API."`
+	DecidedsSource             string   `env:"DECIDEDS_SOURCE"                default:"ssv_api"              help:"Source of the decided counts of validators (ssv_api, exporter or file)." enum:"ssv_api,exporter,file"`
+	SSVExporterEndpoint        string   `env:"SSV_EXPORTER_ENDPOINT"                                         help:"HTTP endpoint to the API of an SSV node running as an exporter, for the exporter decideds source."`
+	DecidedsFile               string   `env:"DECIDEDS_FILE"                                                 help:"Path to a directory of decided counts per day (YYYY-MM-DD.csv or .json), for the file decideds source." type:"path"`
+	E2MEndpoint                string   `env:"E2M_ENDPOINT"                                                  help:"HTTP endpoint to an ethereum2-monitor API."                                         name:"e2m-endpoint"`
+	BeaconchaEndpoint          string   `env:"BEACONCHA_ENDPOINT"             default:"https://beaconcha.in" help:"HTTP endpoint to a beaconcha.in API."`
+	BeaconchaAPIKeys           []string `env:"BEACONCHA_API_KEY"                                             help:"API keys for beaconcha.in API, separated by commas. Requests are spread across the keys." name:"beaconcha-api-key"`
+	BeaconchaRequestsPerMinute float64  `env:"BEACONCHA_REQUESTS_PER_MINUTE"  default:"20"                   help:"Maximum number of requests per minute to beaconcha.in API, per API key. Lowered to the key's limit if the API reports a lower one."`
+	BeaconchaAPIVersion        string   `env:"BEACONCHA_API_VERSION"          default:"v1"                   help:"Version of the beaconcha.in API to fetch validator stats from. v2 fetches only the days being synced instead of a validator's whole history." enum:"v1,v2"`
+	BeaconchaBatchSize         int      `env:"BEACONCHA_BATCH_SIZE"           default:"100"                  help:"Number of validators per beaconcha.in API request. Set to 1 to request validators individually."`
+	BeaconNodePerformance      bool     `env:"BEACON_NODE_PERFORMANCE"                                       help:"Fetch validator performance from the consensus node instead of a monitoring API. Historical days require an archive node."`
+	PerformanceFile            string   `env:"PERFORMANCE_FILE"                                              help:"Path to a CSV or JSONL file, or a directory of them, to read validator performance from instead of a monitoring API." type:"path"`
+	PerformanceProvider        string   `env:"PERFORMANCE_PROVIDER"          default:""                     help:"Performance provider to sync (beaconcha, e2m, beaconnode or file). Defaults to file with --performance-file, beaconnode with --beacon-node-performance, e2m with --e2m-endpoint, and beaconcha otherwise." enum:",beaconcha,e2m,beaconnode,file"`
+	PerformanceFallbacks       []string `env:"PERFORMANCE_FALLBACKS"                                         help:"Providers to fill in validators that the performance provider has no performance for, in order (e.g. beaconnode,e2m)." enum:"beaconcha,e2m,beaconnode,file"`
+	DecidedsMinCoverage        float64  `env:"DECIDEDS_MIN_COVERAGE"          default:"0.9"                  help:"Minimum share of validators active the whole day with a decided count in the SSV API's response."`
+	DecidedsMaxDrift           float64  `env:"DECIDEDS_MAX_DRIFT"             default:"0.1"                  help:"Maximum drop in the share of validators with a decided count from the previous day. 0 to disable."`
+	DecidedsQuarantine         bool     `env:"DECIDEDS_QUARANTINE"                                           help:"Leave the decided counts of days that fail the coverage checks unset instead of failing the sync."`
+	VerifySSVCache             bool     `env:"VERIFY_SSV_CACHE"                                              help:"Re-fetch cached decided counts from the SSV API and compare them before trusting the cache." name:"verify-ssv-cache"`
+	LogChunkSize               uint64   `env:"LOG_CHUNK_SIZE"                 default:"5000"                 help:"Number of blocks per eth_getLogs request. Ranges with too many results are split automatically."`
+	LogWorkers                 int      `env:"LOG_WORKERS"                    default:"4"                    help:"Number of concurrent eth_getLogs requests."`
+	HighestExecutionBlock      uint64   `env:"HIGHEST_EXECUTION_BLOCK"                                       help:"Execution block number to end syncing at. Defaults to the highest finalized block."`
+	Confirmations              uint64   `env:"CONFIRMATIONS"                                                 help:"Sync up to this many blocks behind the head instead of the highest finalized block. Requires reorg detection to be effective."`
+	ReorgCheckDepth            int      `env:"REORG_CHECK_DEPTH"              default:"128"                  help:"Maximum number of recently synced blocks to verify against the execution node when looking for the fork point of a reorg."`
+	Fresh                      bool     `env:"FRESH"                                                         help:"Delete all data and start from scratch."`
+	FreshSSV                   bool     `env:"FRESH_SSV"                                                     help:"Delete all SSV data and start from scratch."`
+	KeepCache                  bool     `env:"KEEP_CACHE"                                                    help:"Preserve the .cache directory at <DATA_DIR>/<network>/.cache even when using --fresh or --fresh-ssv."`
+	RedoDays                   string   `env:"REDO_DAYS"                                                     help:"Delete and re-fetch the validator performance of a day (YYYY-MM-DD) or range of days (YYYY-MM-DD..YYYY-MM-DD), invalidating their cache."`
+	RedoValidators             []string `env:"REDO_VALIDATORS"                                               help:"Public keys of the validators to redo with --redo-days, separated by commas. Defaults to all validators."`
 }
 
 const cacheDirName = ".cache"
@@ -56,10 +90,22 @@ func (c *SyncCmd) Run(
 	logger *zap.Logger,
 	db *sql.DB,
 	network networkconfig.NetworkConfig,
+	ssvAPINetwork networks.SSVAPINetwork,
 	plan *rewards.Plan,
 ) error {
 	ctx := context.Background()
 
+	if c.LogsFile == "" && c.ExecutionEndpoint == "" {
+		return fmt.Errorf("--execution-endpoint is required unless --logs-file is given")
+	}
+	if c.LogsFile != "" && c.BlocksFile == "" {
+		return fmt.Errorf("--blocks-file is required with --logs-file")
+	}
+	redo, err := c.redo()
+	if err != nil {
+		return err
+	}
+
 	dataDir := filepath.Join(c.DataDir, network.Name)
 	logger.Info(
 		"Starting ssv-rewards",
@@ -67,24 +113,21 @@ func (c *SyncCmd) Run(
 		zap.String("data_dir", dataDir),
 	)
 
-	// Start from scratch, if requested.
+	// Start from scratch, if requested. Only the network's schema is dropped,
+	// so other networks in the same database are left intact.
 	if c.Fresh {
-		// Drop schema.
-		if _, err := db.ExecContext(ctx, "DROP SCHEMA public CASCADE; CREATE SCHEMA public;"); err != nil {
-			return fmt.Errorf("failed to drop schema: %w", err)
+		schema := database.NetworkSchema(network.Name)
+		if err := database.ResetSchema(ctx, db, schema); err != nil {
+			return err
+		}
+		logger.Info("Dropped PostgreSQL schema", zap.String("schema", schema))
+		if err := migrateUp(ctx, logger, db); err != nil {
+			return err
 		}
-		logger.Info("Dropped PostgreSQL schema")
-	}
-
-	// Create tables if they don't exist.
-	schemaSQL, err := os.ReadFile("schema.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read schema.sql: %w", err)
 	}
-	if _, err := db.ExecContext(ctx, string(schemaSQL)); err != nil {
-		return fmt.Errorf("failed to execute schema.sql: %w", err)
+	if err := database.CheckVersion(ctx, db); err != nil {
+		return err
 	}
-	logger.Info("Applied PostgreSQL schema")
 
 	if c.FreshSSV || c.Fresh {
 		if !c.KeepCache {
@@ -112,6 +155,8 @@ func (c *SyncCmd) Run(
 			TRUNCATE TABLE validators CASCADE;
 			TRUNCATE TABLE validator_events CASCADE;
 			TRUNCATE TABLE validator_performances CASCADE;
+			TRUNCATE TABLE performance_gaps CASCADE;
+			TRUNCATE TABLE quarantined_days CASCADE;
 		`
 		if _, err := db.ExecContext(ctx, truncate); err != nil {
 			return fmt.Errorf("failed to truncate validator_events: %w", err)
@@ -136,59 +181,72 @@ func (c *SyncCmd) Run(
 		return fmt.Errorf("failed to create node storage: %w", err)
 	}
 
-	// Connect to execution node.
-	el, err := executionclient.New(
-		ctx,
-		c.ExecutionEndpoint,
-		common.HexToAddress(network.RegistryContractAddr),
+	// Connect to execution node, unless reading contract logs from files.
+	registryContract := common.HexToAddress(network.RegistryContractAddr)
+	var (
+		el            *executionclient.ExecutionClient
+		fileLogSource *sync.FileLogSource
+		eventFilterer *contract.ContractFilterer
 	)
-	if err != nil {
-		return fmt.Errorf("failed to connect to execution node: %w", err)
-	}
+	if c.LogsFile != "" {
+		fileLogSource, err = sync.NewFileLogSource(logger, c.LogsFile, c.BlocksFile, registryContract)
+		if err != nil {
+			return fmt.Errorf("failed to read contract logs: %w", err)
+		}
 
-	eventFilterer, err := el.Filterer()
-	if err != nil {
-		return fmt.Errorf("failed to create event filterer: %w", err)
+		// Events are only parsed, so the filterer doesn't need a backend.
+		eventFilterer, err = contract.NewContractFilterer(registryContract, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create event filterer: %w", err)
+		}
+		logger.Info("Reading contract logs from files",
+			zap.String("logs_file", c.LogsFile),
+			zap.String("blocks_file", c.BlocksFile),
+		)
+	} else {
+		el, err = executionclient.New(ctx, c.ExecutionEndpoint, registryContract)
+		if err != nil {
+			return fmt.Errorf("failed to connect to execution node: %w", err)
+		}
+
+		eventFilterer, err = el.Filterer()
+		if err != nil {
+			return fmt.Errorf("failed to create event filterer: %w", err)
+		}
+		logger.Info("Connected to execution node", zap.String("endpoint", c.ExecutionEndpoint))
 	}
 	eventParser := eventparser.New(eventFilterer)
 
-	logger.Info("Connected to execution node", zap.String("endpoint", c.ExecutionEndpoint))
-
 	// Connect to consensus node.
-	cl, err := auto.New(
-		ctx,
-		auto.WithAddress(c.ConsensusEndpoint),
-		auto.WithLogLevel(zerolog.ErrorLevel),
-	)
+	cl, spec, err := connectConsensus(ctx, logger, c.ConsensusEndpoint, ssvAPINetwork)
 	if err != nil {
-		return fmt.Errorf("failed to connect to consensus node: %w", err)
+		return err
 	}
-	genesisTime, err := cl.(eth2client.GenesisTimeProvider).GenesisTime(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get genesis time: %w", err)
-	}
-	spec := beacon.Spec{
-		Network:        network.Beacon.GetNetwork().String(),
-		GenesisTime:    genesisTime.UTC(),
-		SlotsPerEpoch:  32,
-		SlotDuration:   12 * time.Second,
-		FarFutureEpoch: math.MaxUint64,
-	}
-	logger.Info("Connected to consensus node", zap.String("endpoint", c.ConsensusEndpoint))
 
 	// Derive fromBlock and toBlock.
 	fromBlock := network.RegistrySyncOffset.Uint64()
 	toBlock := c.HighestExecutionBlock
 
-	finalizedBlock, err := el.RPC().
-		BlockByNumber(ctx, new(big.Int).SetInt64(rpc.FinalizedBlockNumber.Int64()))
-	if err != nil {
-		return fmt.Errorf("failed to get current block number: %w", err)
+	var highestBlock uint64
+	if fileLogSource != nil {
+		highestBlock = fileLogSource.HighestBlock().Number
+	} else {
+		highestBlock, err = c.highestSyncableBlock(ctx, el)
+		if err != nil {
+			return err
+		}
 	}
 	if toBlock == 0 {
-		toBlock = finalizedBlock.Number().Uint64()
-	} else if toBlock > finalizedBlock.Number().Uint64() {
-		return fmt.Errorf("--to-block is not yet finalized")
+		toBlock = highestBlock
+	} else if toBlock > highestBlock {
+		switch {
+		case fileLogSource != nil:
+			return fmt.Errorf("--highest-execution-block is beyond the highest block %d in --blocks-file", highestBlock)
+		case c.Confirmations > 0:
+			return fmt.Errorf("--highest-execution-block does not yet have %d confirmations", c.Confirmations)
+		default:
+			return fmt.Errorf("--highest-execution-block is not yet finalized")
+		}
 	}
 
 	// Create or verify the state of the database.
@@ -201,6 +259,7 @@ func (c *SyncCmd) Run(
 			NetworkName:        network.Name,
 			LowestBlockNumber:  int(fromBlock),
 			HighestBlockNumber: int(toBlock),
+			SlotsPerEpoch:      int(spec.SlotsPerEpoch),
 		}
 		if err := state.Insert(ctx, db, boil.Infer()); err != nil {
 			return fmt.Errorf("failed to insert state: %w", err)
@@ -209,20 +268,47 @@ func (c *SyncCmd) Run(
 		if state.NetworkName != network.Name {
 			return fmt.Errorf("database is already synced with %s, want %s", state.NetworkName, network.Name)
 		}
+		if state.SlotsPerEpoch != int(spec.SlotsPerEpoch) {
+			return fmt.Errorf("database is already synced with %d slots per epoch, want %d", state.SlotsPerEpoch, spec.SlotsPerEpoch)
+		}
 		if state.LowestBlockNumber != int(fromBlock) {
 			return fmt.Errorf("database is already synced from block %d, want %d", state.LowestBlockNumber, fromBlock)
 		}
+
+		// Roll back to the fork point if previously synced blocks were reorged.
+		// Without an execution node, there's nothing to verify against.
+		if el != nil {
+			forkBlock, reorged, err := sync.DetectReorg(ctx, logger, db, el.RPC(), c.ReorgCheckDepth)
+			if err != nil {
+				return fmt.Errorf("failed to detect reorg: %w", err)
+			}
+			if reorged {
+				if err := sync.RollbackToBlock(ctx, logger, db, el.RPC(), nodeStorage, forkBlock); err != nil {
+					return fmt.Errorf("failed to roll back reorg: %w", err)
+				}
+				state.HighestBlockNumber = forkBlock
+			}
+		}
 		fromBlock = uint64(state.HighestBlockNumber) + 1
 	}
 
 	// Sync contract events.
 	if fromBlock <= toBlock {
+		var logSource sync.ContractLogSource
+		if fileLogSource != nil {
+			logSource = fileLogSource
+		} else {
+			logSource, err = sync.NewLogFetcher(logger, el.RPC(), registryContract, c.LogChunkSize, c.LogWorkers)
+			if err != nil {
+				return fmt.Errorf("failed to create log fetcher: %w", err)
+			}
+		}
 		err = sync.SyncContractEvents(
 			ctx,
 			logger,
 			spec,
 			eventParser,
-			el,
+			logSource,
 			db,
 			fromBlock,
 			toBlock,
@@ -247,45 +333,310 @@ func (c *SyncCmd) Run(
 		return fmt.Errorf("failed to sync validator events: %w", err)
 	}
 
+	// Open the cache of fetched performance data.
+	kvCache, err := cache.New(logger, filepath.Join(dataDir, cacheDirName, "kv"))
+	if err != nil {
+		return err
+	}
+	defer kvCache.Close()
+	err = importCacheDir(logger, "ssv", filepath.Join(dataDir, cacheDirName, "ssv"), func(dir string) (int, error) {
+		return sync.ImportSSVCacheDir(kvCache, dir)
+	})
+	if err != nil {
+		return err
+	}
+
 	// Sync validator performance.
-	var performanceProvider performance.Provider
-	switch {
-	case c.E2MEndpoint != "":
-		performanceProvider = e2m.New(c.E2MEndpoint)
-	case c.BeaconchaEndpoint != "":
-		performanceProvider, err = beaconcha.New(
-			c.BeaconchaEndpoint,
-			c.BeaconchaAPIKey,
-			float64(c.BeaconchaRequestsPerMinute)*0.666, // Safety margin.
-			filepath.Join(dataDir, cacheDirName, "beaconcha"),
-		)
+	providerType := c.performanceProviderType()
+	performanceProvider, err := c.newPerformanceProvider(logger, dataDir, kvCache, providerType)
+	if err != nil {
+		return err
+	}
+	var fallbacks []performance.Provider
+	for _, fallbackType := range c.PerformanceFallbacks {
+		if fallbackType == providerType {
+			return fmt.Errorf("performance fallback %s is the performance provider", fallbackType)
+		}
+		fallback, err := c.newPerformanceProvider(logger, dataDir, kvCache, fallbackType)
 		if err != nil {
-			return fmt.Errorf("failed to create beaconcha client: %w", err)
+			return err
 		}
-	default:
-		return fmt.Errorf("either e2m-endpoint or beaconcha-endpoint must be provided")
+		fallbacks = append(fallbacks, fallback)
 	}
 
-	ssvCacheDir := filepath.Join(dataDir, cacheDirName, "ssv")
-	if err := os.MkdirAll(ssvCacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create SSV cache directory: %w", err)
+	decidedsSource, err := c.newDecidedsSource()
+	if err != nil {
+		return err
 	}
+
+	// Get the time of the highest block, up to which performance is synced.
+	var highestBlockTime time.Time
+	if fileLogSource != nil {
+		block, ok := fileLogSource.Block(toBlock)
+		if !ok {
+			return fmt.Errorf("block %d is missing from --blocks-file", toBlock)
+		}
+		highestBlockTime = block.Time
+	} else {
+		header, err := el.RPC().HeaderByNumber(ctx, new(big.Int).SetUint64(toBlock))
+		if err != nil {
+			return fmt.Errorf("failed to get latest block time: %w", err)
+		}
+		highestBlockTime = time.Unix(int64(header.Time), 0).UTC()
+	}
+
+	// Invalidate the redone days' cache, so that they're fetched again. Their rows are
+	// replaced as each day is synced.
+	var activeBefore map[time.Time]int
+	if redo != nil {
+		providerType := models.ProviderType(performanceProvider.Type())
+		activeBefore, err = redo.ActiveDays(ctx, db, providerType, reconcile.PlanCriteria(plan))
+		if err != nil {
+			return err
+		}
+		providers := append([]performance.Provider{performanceProvider}, fallbacks...)
+		if err := redo.Invalidate(ctx, db, kvCache, decidedsSource, providers); err != nil {
+			return fmt.Errorf("failed to invalidate cache: %w", err)
+		}
+		logger.Info("Redoing validator performance",
+			zap.Time("from", redo.FromDay),
+			zap.Time("to", redo.ToDay),
+			zap.Int("validators", len(redo.PublicKeys)),
+		)
+	}
+
 	err = sync.SyncValidatorPerformance(
 		ctx,
 		logger,
 		spec,
-		el.RPC(),
 		db,
-		c.SSVAPIEndpoint,
+		decidedsSource,
 		performanceProvider,
+		fallbacks,
 		plan.Rounds[0].Period.FirstDay(),
 		plan.Rounds[len(plan.Rounds)-1].Period.LastDay(),
-		toBlock,
-		ssvCacheDir,
+		highestBlockTime,
+		kvCache,
+		sync.DecidedsCheck{
+			MinCoverage: c.DecidedsMinCoverage,
+			MaxDrift:    c.DecidedsMaxDrift,
+			Quarantine:  c.DecidedsQuarantine,
+			VerifyCache: c.VerifySSVCache,
+		},
+		redo,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to sync validator performance: %w", err)
 	}
 
+	// Compare the active days of the redone days before and after.
+	if redo != nil {
+		activeAfter, err := redo.ActiveDays(ctx, db, models.ProviderType(performanceProvider.Type()), reconcile.PlanCriteria(plan))
+		if err != nil {
+			return err
+		}
+		for _, day := range redo.Days() {
+			logger.Info("Redid validator performance",
+				zap.String("day", day.Format("2006-01-02")),
+				zap.Int("active_before", activeBefore[day]),
+				zap.Int("active_after", activeAfter[day]),
+				zap.Int("diff", activeAfter[day]-activeBefore[day]),
+			)
+		}
+	}
+
 	return nil
 }
+
+// redo returns the days and validators to redo, or nil if none are given.
+func (c *SyncCmd) redo() (*sync.Redo, error) {
+	if c.RedoDays == "" {
+		if len(c.RedoValidators) > 0 {
+			return nil, fmt.Errorf("--redo-validators requires --redo-days")
+		}
+		return nil, nil
+	}
+	fromDay, toDay, err := sync.ParseDays(c.RedoDays)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --redo-days: %w", err)
+	}
+	redo := &sync.Redo{FromDay: fromDay, ToDay: toDay}
+	for _, pubKey := range c.RedoValidators {
+		pubKey = decideds.PublicKey(strings.TrimSpace(pubKey))
+		if len(pubKey) != len(phase0.BLSPubKey{})*2 {
+			return nil, fmt.Errorf("invalid --redo-validators public key: %q", pubKey)
+		}
+		redo.PublicKeys = append(redo.PublicKeys, pubKey)
+	}
+	return redo, nil
+}
+
+// connectConsensus connects to a consensus node, and returns the spec of its chain.
+func connectConsensus(
+	ctx context.Context,
+	logger *zap.Logger,
+	endpoint string,
+	ssvAPINetwork networks.SSVAPINetwork,
+) (eth2client.Service, beacon.Spec, error) {
+	cl, err := auto.New(
+		ctx,
+		auto.WithAddress(endpoint),
+		auto.WithLogLevel(zerolog.ErrorLevel),
+	)
+	if err != nil {
+		return nil, beacon.Spec{}, fmt.Errorf("failed to connect to consensus node: %w", err)
+	}
+	genesisTime, err := cl.(eth2client.GenesisTimeProvider).GenesisTime(ctx)
+	if err != nil {
+		return nil, beacon.Spec{}, fmt.Errorf("failed to get genesis time: %w", err)
+	}
+	specResponse, err := cl.(eth2client.SpecProvider).Spec(ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, beacon.Spec{}, fmt.Errorf("failed to get beacon spec: %w", err)
+	}
+	spec, err := beacon.SpecFromConfig(string(ssvAPINetwork), genesisTime, specResponse.Data)
+	if err != nil {
+		return nil, beacon.Spec{}, fmt.Errorf("failed to parse beacon spec: %w", err)
+	}
+	logger.Info("Connected to consensus node",
+		zap.String("endpoint", endpoint),
+		zap.Time("genesis_time", spec.GenesisTime),
+		zap.Uint64("slots_per_epoch", uint64(spec.SlotsPerEpoch)),
+		zap.Duration("slot_duration", spec.SlotDuration),
+	)
+	return cl, spec, nil
+}
+
+// performanceProviderType returns the type of the performance provider to sync,
+// which is inferred from the given flags unless it's set explicitly.
+func (c *SyncCmd) performanceProviderType() string {
+	switch {
+	case c.PerformanceProvider != "":
+		return c.PerformanceProvider
+	case c.PerformanceFile != "":
+		return string(file.ProviderType)
+	case c.BeaconNodePerformance:
+		return string(beaconnode.ProviderType)
+	case c.E2MEndpoint != "":
+		return string(e2m.ProviderType)
+	default:
+		return string(beaconcha.ProviderType)
+	}
+}
+
+func (c *SyncCmd) newPerformanceProvider(
+	logger *zap.Logger,
+	dataDir string,
+	kvCache *cache.Cache,
+	providerType string,
+) (performance.Provider, error) {
+	switch performance.ProviderType(providerType) {
+	case file.ProviderType:
+		if c.PerformanceFile == "" {
+			return nil, fmt.Errorf("performance-file is required for the file performance provider")
+		}
+		fileProvider, err := file.New(c.PerformanceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read performance file: %w", err)
+		}
+		days := fileProvider.Days()
+		logger.Info("Read performance file",
+			zap.String("path", c.PerformanceFile),
+			zap.Time("from", days[0]),
+			zap.Time("to", days[len(days)-1]),
+		)
+		return fileProvider, nil
+	case beaconnode.ProviderType:
+		provider := beaconnode.New(c.ConsensusEndpoint, kvCache)
+		err := importCacheDir(logger, "beaconnode", filepath.Join(dataDir, cacheDirName, "beaconnode"), provider.ImportCacheDir)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case e2m.ProviderType:
+		if c.E2MEndpoint == "" {
+			return nil, fmt.Errorf("e2m-endpoint is required for the e2m performance provider")
+		}
+		return e2m.New(c.E2MEndpoint), nil
+	case beaconcha.ProviderType:
+		provider := beaconcha.New(
+			c.BeaconchaEndpoint,
+			beaconcha.APIVersion(c.BeaconchaAPIVersion),
+			c.BeaconchaAPIKeys,
+			c.BeaconchaRequestsPerMinute,
+			c.BeaconchaBatchSize,
+			kvCache,
+		)
+		err := importCacheDir(logger, "beaconcha", filepath.Join(dataDir, cacheDirName, "beaconcha"), provider.ImportCacheDir)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown performance provider %q", providerType)
+	}
+}
+
+func (c *SyncCmd) newDecidedsSource() (decideds.Source, error) {
+	switch decideds.SourceType(c.DecidedsSource) {
+	case ssvapi.SourceType:
+		if c.SSVAPIEndpoint == "" {
+			return nil, fmt.Errorf("ssv-api-endpoint is required for the ssv_api decideds source")
+		}
+		return ssvapi.New(c.SSVAPIEndpoint), nil
+	case exporter.SourceType:
+		if c.SSVExporterEndpoint == "" {
+			return nil, fmt.Errorf("ssv-exporter-endpoint is required for the exporter decideds source")
+		}
+		return exporter.New(c.SSVExporterEndpoint), nil
+	case decidedsfile.SourceType:
+		if c.DecidedsFile == "" {
+			return nil, fmt.Errorf("decideds-file is required for the file decideds source")
+		}
+		source, err := decidedsfile.New(c.DecidedsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open decideds file: %w", err)
+		}
+		return source, nil
+	default:
+		return nil, fmt.Errorf("unknown decideds source %q", c.DecidedsSource)
+	}
+}
+
+// importCacheDir imports a cache directory of JSON files into the key-value cache,
+// and removes it once imported.
+func importCacheDir(logger *zap.Logger, name, dir string, importDir func(dir string) (int, error)) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	n, err := importDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to import %s cache: %w", name, err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove %s cache directory: %w", name, err)
+	}
+	logger.Info("Imported cache directory", zap.String("cache", name), zap.Int("files", n))
+	return nil
+}
+
+// highestSyncableBlock returns the finalized block, or the block
+// that has the requested number of confirmations.
+func (c *SyncCmd) highestSyncableBlock(ctx context.Context, el *executionclient.ExecutionClient) (uint64, error) {
+	if c.Confirmations == 0 {
+		finalizedBlock, err := el.RPC().
+			BlockByNumber(ctx, new(big.Int).SetInt64(rpc.FinalizedBlockNumber.Int64()))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get current block number: %w", err)
+		}
+		return finalizedBlock.Number().Uint64(), nil
+	}
+	headBlock, err := el.RPC().BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current block number: %w", err)
+	}
+	if headBlock < c.Confirmations {
+		return 0, fmt.Errorf("head block %d has fewer than %d confirmations", headBlock, c.Confirmations)
+	}
+	return headBlock - c.Confirmations, nil
+}
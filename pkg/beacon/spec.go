@@ -1,6 +1,8 @@
 package beacon
 
 import (
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -14,6 +16,45 @@ type Spec struct {
 	FarFutureEpoch phase0.Epoch
 }
 
+// SpecFromConfig returns the spec of a chain from its Beacon node's config, as returned
+// by /eth/v1/config/spec and decoded by go-eth2-client. FAR_FUTURE_EPOCH is a constant
+// that nodes don't usually return, so it defaults to the maximum epoch.
+func SpecFromConfig(network string, genesisTime time.Time, config map[string]any) (Spec, error) {
+	slotDuration, ok := config["SECONDS_PER_SLOT"].(time.Duration)
+	if !ok || slotDuration <= 0 {
+		return Spec{}, fmt.Errorf("invalid SECONDS_PER_SLOT: %v", config["SECONDS_PER_SLOT"])
+	}
+	slotsPerEpoch, ok := config["SLOTS_PER_EPOCH"].(uint64)
+	if !ok || slotsPerEpoch == 0 {
+		return Spec{}, fmt.Errorf("invalid SLOTS_PER_EPOCH: %v", config["SLOTS_PER_EPOCH"])
+	}
+	farFutureEpoch := phase0.Epoch(math.MaxUint64)
+	if v, ok := config["FAR_FUTURE_EPOCH"].(uint64); ok {
+		farFutureEpoch = phase0.Epoch(v)
+	}
+	spec := Spec{
+		Network:        network,
+		GenesisTime:    genesisTime.UTC(),
+		SlotsPerEpoch:  phase0.Slot(slotsPerEpoch),
+		SlotDuration:   slotDuration,
+		FarFutureEpoch: farFutureEpoch,
+	}
+	if err := spec.Validate(); err != nil {
+		return Spec{}, err
+	}
+	return spec, nil
+}
+
+// Validate checks that a day is made of whole epochs, since validator performance is
+// synced by day.
+func (s *Spec) Validate() error {
+	epochDuration := s.SlotDuration * time.Duration(s.SlotsPerEpoch)
+	if epochDuration <= 0 || 24*time.Hour%epochDuration != 0 {
+		return fmt.Errorf("epochs of %s (%d slots of %s) don't divide a day evenly", epochDuration, s.SlotsPerEpoch, s.SlotDuration)
+	}
+	return nil
+}
+
 func (s *Spec) FirstSlot(epoch phase0.Epoch) phase0.Slot {
 	return phase0.Slot(epoch) * s.SlotsPerEpoch
 }
@@ -33,3 +74,31 @@ func (s *Spec) SlotAt(t time.Time) phase0.Slot {
 func (s *Spec) TimeAt(slot phase0.Slot) time.Time {
 	return s.GenesisTime.Add(time.Duration(slot) * s.SlotDuration)
 }
+
+// EpochsPerDay returns the number of epochs in a day.
+func (s *Spec) EpochsPerDay() phase0.Epoch {
+	return phase0.Epoch(24 * time.Hour / s.SlotDuration / time.Duration(s.SlotsPerEpoch))
+}
+
+// DayStart returns the time of the first slot of the given day, counting days
+// from the genesis time.
+func (s *Spec) DayStart(day time.Time) time.Time {
+	return time.Date(
+		day.Year(),
+		day.Month(),
+		day.Day(),
+		s.GenesisTime.Hour(),
+		s.GenesisTime.Minute(),
+		s.GenesisTime.Second(),
+		s.GenesisTime.Nanosecond(),
+		time.UTC,
+	)
+}
+
+// DayEpochs returns the range of epochs of the given day (inclusive).
+func (s *Spec) DayEpochs(day time.Time) (fromEpoch, toEpoch phase0.Epoch) {
+	dayStart := s.DayStart(day)
+	fromEpoch = s.EpochAt(s.SlotAt(dayStart))
+	toEpoch = s.EpochAt(s.SlotAt(dayStart.AddDate(0, 0, 1))) - 1
+	return fromEpoch, toEpoch
+}
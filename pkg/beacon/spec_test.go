@@ -1,6 +1,7 @@
 package beacon
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -32,3 +33,46 @@ func TestSpec_TimeAt(t *testing.T) {
 	spec := Spec{GenesisTime: time.Unix(0, 0), SlotDuration: time.Second}
 	require.Equal(t, time.Unix(5, 0), spec.TimeAt(5))
 }
+
+func TestSpecFromConfig(t *testing.T) {
+	genesisTime := time.Date(2020, 12, 1, 12, 0, 23, 0, time.UTC)
+	spec, err := SpecFromConfig("mainnet", genesisTime, map[string]any{
+		"SECONDS_PER_SLOT": 12 * time.Second,
+		"SLOTS_PER_EPOCH":  uint64(32),
+	})
+	require.NoError(t, err)
+	require.Equal(t, phase0.Slot(32), spec.SlotsPerEpoch)
+	require.Equal(t, 12*time.Second, spec.SlotDuration)
+	require.Equal(t, phase0.Epoch(math.MaxUint64), spec.FarFutureEpoch)
+	require.Equal(t, phase0.Epoch(225), spec.EpochsPerDay())
+
+	// Devnets with other slot timings.
+	spec, err = SpecFromConfig("devnet", genesisTime, map[string]any{
+		"SECONDS_PER_SLOT": 6 * time.Second,
+		"SLOTS_PER_EPOCH":  uint64(8),
+	})
+	require.NoError(t, err)
+	require.Equal(t, phase0.Epoch(1800), spec.EpochsPerDay())
+
+	// Days must be made of whole epochs.
+	_, err = SpecFromConfig("devnet", genesisTime, map[string]any{
+		"SECONDS_PER_SLOT": 7 * time.Second,
+		"SLOTS_PER_EPOCH":  uint64(32),
+	})
+	require.Error(t, err)
+	_, err = SpecFromConfig("devnet", genesisTime, map[string]any{"SECONDS_PER_SLOT": 12 * time.Second})
+	require.Error(t, err)
+}
+
+func TestSpec_DayEpochs(t *testing.T) {
+	spec := Spec{
+		GenesisTime:   time.Date(2020, 12, 1, 12, 0, 23, 0, time.UTC),
+		SlotsPerEpoch: 32,
+		SlotDuration:  12 * time.Second,
+	}
+	day := time.Date(2020, 12, 2, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2020, 12, 2, 12, 0, 23, 0, time.UTC), spec.DayStart(day))
+	fromEpoch, toEpoch := spec.DayEpochs(day)
+	require.Equal(t, phase0.Epoch(225), fromEpoch)
+	require.Equal(t, phase0.Epoch(449), toEpoch)
+}
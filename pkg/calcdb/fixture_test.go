@@ -0,0 +1,280 @@
+package calcdb
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+	"github.com/bloxapp/ssv-rewards/pkg/snapshot"
+)
+
+const fixtureNetwork = "mainnet"
+
+// Addresses and public keys are stored as unprefixed hex, as formatted by the rewards package.
+var (
+	ownerA     = strings.Repeat("a1", 20)
+	ownerB     = strings.Repeat("b1", 20)
+	recipientA = strings.Repeat("a2", 20)
+	recipientC = strings.Repeat("c2", 20)
+
+	validatorA = strings.Repeat("01", 48)
+	validatorB = strings.Repeat("02", 48)
+	validatorC = strings.Repeat("03", 48)
+	validatorD = strings.Repeat("04", 48)
+)
+
+type row = map[string]any
+
+// fixture is the synced data of a network with the following validators in January 2024:
+//   - A: owned by ownerA, active on days 1-3.
+//   - B: owned by ownerA, active on day 1, then missing attestations on day 2 and
+//     insolvent on day 3, with events on both.
+//   - C: owned by ownerB, migrated to the ETH tree on day 2, with 64 ETH of effective balance.
+//   - D: owned by ownerB, without decideds.
+//
+// B and D have unresolved performance gaps, and A a resolved one. Day 3 is quarantined.
+//
+// A's days in February and an e2m day are outside of most queries.
+func fixture() map[string][]row {
+	const slotsPerDay = 32 * 225
+	dayEpochs := func(day int) (int, int) {
+		return (day - 1) * 225, day*225 - 1
+	}
+	gap := func(pubkey string, index, day int, detectedAt string, resolvedAt any) row {
+		return row{
+			"provider":    "beaconcha",
+			"day":         fmt.Sprintf("2024-01-%02d", day),
+			"public_key":  pubkey,
+			"index":       index,
+			"reason":      "missing",
+			"detected_at": detectedAt,
+			"resolved_at": resolvedAt,
+		}
+	}
+	performance := func(pubkey, owner string, day int, attestations, decideds any, solvent bool, balance int64) row {
+		fromEpoch, toEpoch := dayEpochs(day)
+		return row{
+			"provider":              "beaconcha",
+			"day":                   fmt.Sprintf("2024-01-%02d", day),
+			"from_epoch":            fromEpoch,
+			"to_epoch":              toEpoch,
+			"owner_address":         owner,
+			"public_key":            pubkey,
+			"solvent_whole_day":     solvent,
+			"end_effective_balance": balance,
+			"start_beacon_status":   "active_ongoing",
+			"end_beacon_status":     "active_ongoing",
+			"decideds":              decideds,
+			"attestations_executed": attestations,
+		}
+	}
+	var (
+		contractEvents  []row
+		validatorEvents []row
+	)
+	event := func(pubkey, owner, name string, day, slotOfDay int) {
+		id := len(contractEvents) + 1
+		slot := (day-1)*slotsPerDay + slotOfDay
+		blockNumber := 1_000_000 + slot
+		blockTime := time.Date(2024, 1, day, 0, 0, slotOfDay*12, 0, time.UTC).Format("2006-01-02T15:04:05")
+		contractEvents = append(contractEvents, row{
+			"id":                id,
+			"event_name":        name,
+			"slot":              slot,
+			"block_number":      blockNumber,
+			"block_hash":        fmt.Sprintf("0x%064x", id),
+			"block_time":        blockTime,
+			"transaction_hash":  fmt.Sprintf("0x%064x", 1000+id),
+			"transaction_index": 0,
+			"log_index":         0,
+			"raw_log":           row{},
+			"raw_event":         row{},
+		})
+		validatorEvents = append(validatorEvents, row{
+			"id":                id,
+			"contract_event_id": id,
+			"slot":              slot,
+			"block_number":      blockNumber,
+			"block_time":        blockTime,
+			"log_index":         0,
+			"public_key":        pubkey,
+			"owner_address":     owner,
+			"event_name":        name,
+			"activated":         name != "ValidatorRemoved",
+		})
+	}
+	// Events are inserted out of order, to verify that they're aggregated in block order.
+	event(validatorB, ownerA, "ValidatorRemoved", 3, 200)
+	event(validatorB, ownerA, "ValidatorAdded", 2, 100)
+	event(validatorB, ownerA, "ValidatorExited", 2, 50)
+
+	validator := func(pubkey string, migrationDay any) row {
+		return row{"public_key": pubkey, "index": nil, "active": true, "migration_day": migrationDay}
+	}
+	const eth32, eth64 = 32_000_000_000, 64_000_000_000
+	return map[string][]row{
+		"state": {{
+			"id":                             1,
+			"network_name":                   fixtureNetwork,
+			"lowest_block_number":            1_000_000,
+			"highest_block_number":           1_100_000,
+			"earliest_validator_performance": "2024-01-01",
+			"latest_validator_performance":   "2024-02-01",
+			"slots_per_epoch":                32,
+		}},
+		"contract_events": contractEvents,
+		"validators": {
+			validator(validatorA, nil),
+			validator(validatorB, nil),
+			validator(validatorC, "2024-01-02"),
+			validator(validatorD, nil),
+		},
+		"validator_events": validatorEvents,
+		"validator_performances": {
+			performance(validatorA, ownerA, 1, 225, 30, true, eth32),
+			performance(validatorA, ownerA, 2, 225, 30, true, eth32),
+			performance(validatorA, ownerA, 3, 225, 30, true, eth32),
+			performance(validatorB, ownerA, 1, 225, 30, true, eth32),
+			performance(validatorB, ownerA, 2, 100, 30, true, eth32),
+			performance(validatorB, ownerA, 3, 225, 30, false, eth32),
+			performance(validatorC, ownerB, 1, 225, 30, true, eth64),
+			performance(validatorC, ownerB, 2, 225, 30, true, eth64),
+			performance(validatorC, ownerB, 3, 225, 5, true, eth64),
+			performance(validatorD, ownerB, 1, 225, nil, true, eth32),
+			func() row {
+				r := performance(validatorA, ownerA, 1, 225, 30, true, eth32)
+				r["day"] = "2024-02-01"
+				return r
+			}(),
+			func() row {
+				r := performance(validatorD, ownerB, 1, 225, 30, true, eth32)
+				r["provider"] = "e2m"
+				return r
+			}(),
+		},
+		"performance_gaps": {
+			gap(validatorD, 4, 2, "2024-01-03T00:00:00+00:00", nil),
+			gap(validatorB, 2, 3, "2024-01-04T00:00:00+00:00", nil),
+			gap(validatorA, 1, 2, "2024-01-03T00:00:00+00:00", "2024-01-05T00:00:00+00:00"),
+			func() row {
+				r := gap(validatorD, 4, 3, "2024-01-04T00:00:00+00:00", nil)
+				r["provider"] = "e2m"
+				return r
+			}(),
+		},
+		"quarantined_days": {
+			{
+				"provider":        "beaconcha",
+				"day":             "2024-01-03",
+				"decideds_source": "ssv_api",
+				"problems":        "no validator has a decided count above zero",
+				"quarantined_at":  "2024-01-05T00:00:00+00:00",
+			},
+		},
+	}
+}
+
+// fixtureSnapshot encodes the fixture as a snapshot.
+func fixtureSnapshot(t *testing.T) []byte {
+	t.Helper()
+	schemaVersion, err := database.LatestVersion()
+	require.NoError(t, err)
+
+	data := fixture()
+	manifest := snapshot.Manifest{
+		FormatVersion: snapshot.FormatVersion,
+		SchemaVersion: schemaVersion,
+		Network:       fixtureNetwork,
+		CreatedAt:     time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+	}
+	files := map[string][]byte{}
+	for _, name := range []string{"state", "contract_events", "validators", "validator_events", "validator_performances", "performance_gaps", "quarantined_days"} {
+		var file []byte
+		for _, r := range data[name] {
+			encoded, err := json.Marshal(r)
+			require.NoError(t, err)
+			file = append(file, append(encoded, '\n')...)
+		}
+		sum := sha256.Sum256(file)
+		tableManifest := snapshot.TableManifest{
+			Name:   name,
+			File:   name + ".jsonl",
+			Rows:   len(data[name]),
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+		manifest.Tables = append(manifest.Tables, tableManifest)
+		files[tableManifest.File] = file
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	write("manifest.json", manifestJSON)
+	for _, table := range manifest.Tables {
+		write(table.File, files[table.File])
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// fixtureQueries are queries of the fixture covering the criteria, redirects,
+// effective balances and migration filters.
+func fixtureQueries() []Query {
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := Query{
+		Provider:        "beaconcha",
+		MinAttestations: 200,
+		MinDecideds:     10,
+		Period:          january,
+		MigrationFilter: MigrationFilterSSV,
+	}
+	var queries []Query
+	for _, filter := range []string{MigrationFilterSSV, MigrationFilterETH} {
+		for _, pectra := range []bool{false, true} {
+			for _, redirects := range []bool{false, true} {
+				q := base
+				q.MigrationFilter = filter
+				q.PectraSupport = pectra
+				q.OwnerRedirects = redirects
+				q.ValidatorRedirects = redirects
+				queries = append(queries, q)
+			}
+		}
+	}
+	february := base
+	february.Period = january.AddDate(0, 1, 0)
+	e2m := base
+	e2m.Provider = "e2m"
+	return append(queries, february, e2m)
+}
+
+// fixtureRedirects redirects ownerA's rewards to recipientA, and validator C's to recipientC.
+func fixtureRedirects(t *testing.T) (rewards.OwnerRedirects, rewards.ValidatorRedirects) {
+	t.Helper()
+	var from, to, toC rewards.ExecutionAddress
+	var pubkey rewards.BLSPubKey
+	require.NoError(t, from.UnmarshalText([]byte("0x"+ownerA)))
+	require.NoError(t, to.UnmarshalText([]byte("0x"+recipientA)))
+	require.NoError(t, toC.UnmarshalText([]byte("0x"+recipientC)))
+	require.NoError(t, pubkey.UnmarshalText([]byte("0x"+validatorC)))
+	return rewards.OwnerRedirects{from: to}, rewards.ValidatorRedirects{pubkey: toC}
+}
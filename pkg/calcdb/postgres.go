@@ -0,0 +1,282 @@
+package calcdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+)
+
+// PostgresStore is a Store backed by the rewards functions of the synced PostgreSQL database.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore returns a Store backed by the given database, scoped to a network's schema.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) State(ctx context.Context) (*State, error) {
+	state, err := models.States().One(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	return &State{
+		NetworkName:                  state.NetworkName,
+		EarliestValidatorPerformance: state.EarliestValidatorPerformance.Time,
+		LatestValidatorPerformance:   state.LatestValidatorPerformance.Time,
+	}, nil
+}
+
+func (s *PostgresStore) SetOwnerRedirects(ctx context.Context, redirects rewards.OwnerRedirects) error {
+	// Truncate the owner_redirects table.
+	_, err := queries.Raw(
+		"TRUNCATE TABLE "+models.TableNames.OwnerRedirects,
+	).ExecContext(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to truncate owner_redirects: %w", err)
+	}
+
+	// Verify that the table is empty.
+	count, err := models.OwnerRedirects().Count(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to count owner_redirects: %w", err)
+	}
+	if count != 0 {
+		return fmt.Errorf("owner_redirects table was not truncated")
+	}
+
+	// Populate with given redirects.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for from, to := range redirects {
+		model := models.OwnerRedirect{
+			FromAddress: from.String(),
+			ToAddress:   to.String(),
+		}
+		if err := model.Insert(ctx, tx, boil.Infer()); err != nil {
+			return fmt.Errorf("failed to insert rewards_redirect: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Verify that the table is populated.
+	count, err = models.OwnerRedirects().Count(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to count owner_redirects: %w", err)
+	}
+	if int(count) != len(redirects) {
+		return fmt.Errorf("owner_redirects table was not populated")
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) SetValidatorRedirects(ctx context.Context, redirects rewards.ValidatorRedirects) error {
+	// Truncate the validator_redirects table.
+	_, err := queries.Raw(
+		"TRUNCATE TABLE "+models.TableNames.ValidatorRedirects,
+	).ExecContext(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to truncate validator_redirects: %w", err)
+	}
+
+	// Verify that the table is empty.
+	count, err := models.ValidatorRedirects().Count(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to count validator_redirects: %w", err)
+	}
+	if count != 0 {
+		return fmt.Errorf("validator_redirects table was not truncated")
+	}
+
+	// Populate with given redirects.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for pubkey, to := range redirects {
+		model := models.ValidatorRedirect{
+			PublicKey: pubkey.String(),
+			ToAddress: to.String(),
+		}
+		if err := model.Insert(ctx, tx, boil.Infer()); err != nil {
+			return fmt.Errorf("failed to insert rewards_redirect: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Verify that the table is populated.
+	count, err = models.ValidatorRedirects().Count(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("failed to count validator_redirects: %w", err)
+	}
+	if int(count) != len(redirects) {
+		return fmt.Errorf("validator_redirects table was not populated")
+	}
+
+	return nil
+}
+
+// Results are ordered with the "C" collation, so that text is compared bytewise like in SQLite.
+
+func (s *PostgresStore) ValidatorParticipations(ctx context.Context, q Query) ([]ValidatorParticipation, error) {
+	var participations []ValidatorParticipation
+	return participations, queries.Raw(
+		`SELECT * FROM participations_by_validator($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ORDER BY public_key COLLATE "C", owner_address COLLATE "C", recipient_address COLLATE "C"`,
+		q.Provider,
+		q.MinAttestations,
+		q.MinDecideds,
+		q.Period,
+		nil, // to_period can be nil for single-period queries
+		q.OwnerRedirects,
+		q.ValidatorRedirects,
+		q.PectraSupport,
+		q.MigrationFilter,
+	).Bind(ctx, s.db, &participations)
+}
+
+func (s *PostgresStore) OwnerParticipations(ctx context.Context, q Query) ([]OwnerParticipation, error) {
+	var participations []OwnerParticipation
+	return participations, queries.Raw(
+		`SELECT * FROM participations_by_owner($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ORDER BY owner_address COLLATE "C", recipient_address COLLATE "C"`,
+		q.Provider,
+		q.MinAttestations,
+		q.MinDecideds,
+		q.Period,
+		nil,
+		q.OwnerRedirects,
+		q.ValidatorRedirects,
+		q.PectraSupport,
+		q.MigrationFilter,
+	).Bind(ctx, s.db, &participations)
+}
+
+func (s *PostgresStore) RecipientParticipations(ctx context.Context, q Query) ([]RecipientParticipation, error) {
+	var participations []RecipientParticipation
+	return participations, queries.Raw(
+		`SELECT * FROM participations_by_recipient($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ORDER BY recipient_address COLLATE "C"`,
+		q.Provider,
+		q.MinAttestations,
+		q.MinDecideds,
+		q.Period,
+		nil,
+		q.OwnerRedirects,
+		q.ValidatorRedirects,
+		q.PectraSupport,
+		q.MigrationFilter,
+	).Bind(ctx, s.db, &participations)
+}
+
+func (s *PostgresStore) Exclusions(ctx context.Context, q Query) ([]Exclusion, error) {
+	var rows []struct {
+		Day               time.Time
+		FromEpoch         phase0.Epoch
+		ToEpoch           phase0.Epoch
+		PublicKey         string
+		StartBeaconStatus sql.NullString
+		EndBeaconStatus   sql.NullString
+		Events            sql.NullString
+		ExclusionReason   string
+	}
+	err := queries.Raw(
+		`SELECT * FROM exclusions_by_validator($1, $2, $3, $4, $5, $6)
+		ORDER BY day, public_key COLLATE "C"`,
+		q.Provider,
+		q.MinAttestations,
+		q.MinDecideds,
+		q.Period,
+		q.Period,
+		q.MigrationFilter,
+	).Bind(ctx, s.db, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusions := make([]Exclusion, len(rows))
+	for i, row := range rows {
+		exclusions[i] = Exclusion{
+			Day:               row.Day,
+			FromEpoch:         row.FromEpoch,
+			ToEpoch:           row.ToEpoch,
+			PublicKey:         row.PublicKey,
+			StartBeaconStatus: row.StartBeaconStatus.String,
+			EndBeaconStatus:   row.EndBeaconStatus.String,
+			Events:            row.Events.String,
+			ExclusionReason:   row.ExclusionReason,
+		}
+	}
+	return exclusions, nil
+}
+
+func (s *PostgresStore) UnresolvedPerformanceGaps(
+	ctx context.Context,
+	provider string,
+	fromDay, toDay time.Time,
+) ([]PerformanceGap, error) {
+	rows, err := models.PerformanceGaps(
+		models.PerformanceGapWhere.Provider.EQ(models.ProviderType(provider)),
+		models.PerformanceGapWhere.Day.GTE(fromDay),
+		models.PerformanceGapWhere.Day.LTE(toDay),
+		models.PerformanceGapWhere.ResolvedAt.IsNull(),
+		qm.OrderBy(`day, public_key COLLATE "C"`),
+	).All(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get performance gaps: %w", err)
+	}
+	gaps := make([]PerformanceGap, len(rows))
+	for i, row := range rows {
+		gaps[i] = PerformanceGap{
+			Day:       row.Day,
+			PublicKey: row.PublicKey,
+			Index:     row.Index,
+			Reason:    row.Reason,
+		}
+	}
+	return gaps, nil
+}
+
+func (s *PostgresStore) QuarantinedDays(
+	ctx context.Context,
+	provider string,
+	fromDay, toDay time.Time,
+) ([]QuarantinedDay, error) {
+	rows, err := models.QuarantinedDays(
+		models.QuarantinedDayWhere.Provider.EQ(models.ProviderType(provider)),
+		models.QuarantinedDayWhere.Day.GTE(fromDay),
+		models.QuarantinedDayWhere.Day.LTE(toDay),
+		qm.OrderBy("day"),
+	).All(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quarantined days: %w", err)
+	}
+	days := make([]QuarantinedDay, len(rows))
+	for i, row := range rows {
+		days[i] = QuarantinedDay{
+			Day:            row.Day,
+			DecidedsSource: string(row.DecidedsSource),
+			Problems:       row.Problems,
+		}
+	}
+	return days, nil
+}
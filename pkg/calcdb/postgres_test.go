@@ -0,0 +1,106 @@
+package calcdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+	"github.com/bloxapp/ssv-rewards/pkg/snapshot"
+)
+
+// TestBackendsMatch verifies that the PostgreSQL and SQLite stores return the same
+// results for the same snapshot. It runs against the PostgreSQL database given by
+// SSV_REWARDS_TEST_POSTGRES, in a temporary schema, and fails instead of skipping in CI.
+func TestBackendsMatch(t *testing.T) {
+	connStr := os.Getenv("SSV_REWARDS_TEST_POSTGRES")
+	if connStr == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("SSV_REWARDS_TEST_POSTGRES must be set in CI")
+		}
+		t.Skip("SSV_REWARDS_TEST_POSTGRES is not set")
+	}
+	ctx := context.Background()
+
+	// Import the fixture into a temporary schema.
+	admin, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	defer admin.Close()
+	schema := fmt.Sprintf("calcdb_test_%d", time.Now().UnixNano())
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		require.NoError(t, err)
+	})
+
+	scoped, err := database.WithSearchPath(connStr, schema)
+	require.NoError(t, err)
+	db, err := sql.Open("postgres", scoped)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = database.MigrateUp(ctx, db)
+	require.NoError(t, err)
+	schemaVersion, err := database.LatestVersion()
+	require.NoError(t, err)
+	_, err = snapshot.Import(ctx, db, bytes.NewReader(fixtureSnapshot(t)), fixtureNetwork, schemaVersion)
+	require.NoError(t, err)
+
+	stores := []Store{NewPostgresStore(db), openFixtureSQLite(t)}
+	ownerRedirects, validatorRedirects := fixtureRedirects(t)
+	for _, store := range stores {
+		require.NoError(t, store.SetOwnerRedirects(ctx, ownerRedirects))
+		require.NoError(t, store.SetValidatorRedirects(ctx, validatorRedirects))
+	}
+
+	// results returns all of a store's results, which are compared as a whole.
+	type results struct {
+		State      *State
+		Validators [][]ValidatorParticipation
+		Owners     [][]OwnerParticipation
+		Recipients [][]RecipientParticipation
+		Exclusions [][]Exclusion
+		Gaps       [][]PerformanceGap
+		Quarantine [][]QuarantinedDay
+	}
+	collect := func(store Store) results {
+		var r results
+		r.State, err = store.State(ctx)
+		require.NoError(t, err)
+		for _, q := range fixtureQueries() {
+			validators, err := store.ValidatorParticipations(ctx, q)
+			require.NoError(t, err)
+			owners, err := store.OwnerParticipations(ctx, q)
+			require.NoError(t, err)
+			recipients, err := store.RecipientParticipations(ctx, q)
+			require.NoError(t, err)
+			exclusions, err := store.Exclusions(ctx, q)
+			require.NoError(t, err)
+			r.Validators = append(r.Validators, validators)
+			r.Owners = append(r.Owners, owners)
+			r.Recipients = append(r.Recipients, recipients)
+			r.Exclusions = append(r.Exclusions, exclusions)
+
+			fromDay := time.Date(q.Period.Year(), q.Period.Month(), 1, 0, 0, 0, 0, time.UTC)
+			gaps, err := store.UnresolvedPerformanceGaps(ctx, q.Provider, fromDay, fromDay.AddDate(0, 1, -1))
+			require.NoError(t, err)
+			r.Gaps = append(r.Gaps, gaps)
+			quarantined, err := store.QuarantinedDays(ctx, q.Provider, fromDay, fromDay.AddDate(0, 1, -1))
+			require.NoError(t, err)
+			r.Quarantine = append(r.Quarantine, quarantined)
+		}
+		return r
+	}
+	postgres, sqlite := collect(stores[0]), collect(stores[1])
+	require.NotEmpty(t, postgres.Validators[0])
+	require.NotEmpty(t, postgres.Exclusions[0])
+	require.NotEmpty(t, postgres.Gaps[0])
+	require.NotEmpty(t, postgres.Quarantine[0])
+	require.Equal(t, postgres, sqlite)
+}
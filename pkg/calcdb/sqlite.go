@@ -0,0 +1,637 @@
+package calcdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+	"github.com/bloxapp/ssv-rewards/pkg/snapshot"
+)
+
+// sqliteSchema mirrors the PostgreSQL tables that rewards are calculated from.
+// Dates are stored as YYYY-MM-DD text, timestamps as ISO 8601 text and booleans as 0 or 1.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS state (
+	id INTEGER PRIMARY KEY,
+	network_name TEXT NOT NULL,
+	lowest_block_number INTEGER NOT NULL,
+	highest_block_number INTEGER NOT NULL,
+	highest_block_hash TEXT,
+	highest_block_time TEXT,
+	earliest_validator_performance TEXT,
+	latest_validator_performance TEXT,
+	slots_per_epoch INTEGER NOT NULL DEFAULT 32
+);
+
+CREATE TABLE IF NOT EXISTS validators (
+	public_key TEXT NOT NULL PRIMARY KEY,
+	"index" INTEGER,
+	active INTEGER NOT NULL,
+	beacon_status TEXT,
+	beacon_effective_balance INTEGER,
+	beacon_activation_eligibility_epoch INTEGER,
+	beacon_activation_epoch INTEGER,
+	beacon_exit_epoch INTEGER,
+	beacon_slashed INTEGER,
+	beacon_withdrawable_epoch INTEGER,
+	migration_day TEXT
+);
+
+CREATE TABLE IF NOT EXISTS validator_events (
+	id INTEGER PRIMARY KEY,
+	contract_event_id INTEGER NOT NULL,
+	slot INTEGER NOT NULL,
+	block_number INTEGER NOT NULL,
+	block_time TEXT NOT NULL,
+	log_index INTEGER NOT NULL,
+	public_key TEXT NOT NULL,
+	owner_address TEXT NOT NULL,
+	event_name TEXT NOT NULL,
+	activated INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_validator_events_public_key ON validator_events(public_key);
+
+CREATE TABLE IF NOT EXISTS validator_performances (
+	provider TEXT NOT NULL,
+	day TEXT NOT NULL,
+	from_epoch INTEGER NOT NULL,
+	to_epoch INTEGER NOT NULL,
+	owner_address TEXT NOT NULL,
+	public_key TEXT NOT NULL,
+	solvent_whole_day INTEGER NOT NULL,
+	"index" INTEGER,
+	end_effective_balance INTEGER,
+	start_beacon_status TEXT,
+	end_beacon_status TEXT,
+	decideds INTEGER,
+	effectiveness REAL,
+	attestation_rate REAL,
+	attestations_assigned INTEGER,
+	attestations_executed INTEGER,
+	attestations_missed INTEGER,
+	proposals_assigned INTEGER,
+	proposals_executed INTEGER,
+	proposals_missed INTEGER,
+	sync_committee_assigned INTEGER,
+	sync_committee_executed INTEGER,
+	sync_committee_missed INTEGER,
+	PRIMARY KEY (provider, day, public_key)
+);
+
+CREATE TABLE IF NOT EXISTS performance_gaps (
+	provider TEXT NOT NULL,
+	day TEXT NOT NULL,
+	public_key TEXT NOT NULL,
+	"index" INTEGER NOT NULL,
+	reason TEXT NOT NULL,
+	detected_at TEXT NOT NULL,
+	resolved_at TEXT,
+	PRIMARY KEY (provider, day, public_key)
+);
+
+CREATE TABLE IF NOT EXISTS quarantined_days (
+	provider TEXT NOT NULL,
+	day TEXT NOT NULL,
+	decideds_source TEXT NOT NULL,
+	problems TEXT NOT NULL,
+	quarantined_at TEXT NOT NULL,
+	PRIMARY KEY (provider, day)
+);
+
+CREATE TABLE IF NOT EXISTS owner_redirects (
+	from_address TEXT NOT NULL PRIMARY KEY,
+	to_address TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS validator_redirects (
+	public_key TEXT NOT NULL PRIMARY KEY,
+	to_address TEXT NOT NULL
+);
+`
+
+// sqliteTables are the columns of the snapshot tables that are imported, in
+// the order they're imported in. Other tables, such as the raw contract events,
+// aren't needed to calculate rewards.
+var sqliteTables = []struct {
+	name    string
+	columns []string
+}{
+	{"state", []string{
+		"id", "network_name", "lowest_block_number", "highest_block_number", "highest_block_hash",
+		"highest_block_time", "earliest_validator_performance", "latest_validator_performance",
+		"slots_per_epoch",
+	}},
+	{"validators", []string{
+		"public_key", "index", "active", "beacon_status", "beacon_effective_balance",
+		"beacon_activation_eligibility_epoch", "beacon_activation_epoch", "beacon_exit_epoch",
+		"beacon_slashed", "beacon_withdrawable_epoch", "migration_day",
+	}},
+	{"validator_events", []string{
+		"id", "contract_event_id", "slot", "block_number", "block_time", "log_index",
+		"public_key", "owner_address", "event_name", "activated",
+	}},
+	{"validator_performances", []string{
+		"provider", "day", "from_epoch", "to_epoch", "owner_address", "public_key",
+		"solvent_whole_day", "index", "end_effective_balance", "start_beacon_status",
+		"end_beacon_status", "decideds", "effectiveness", "attestation_rate",
+		"attestations_assigned", "attestations_executed", "attestations_missed",
+		"proposals_assigned", "proposals_executed", "proposals_missed",
+		"sync_committee_assigned", "sync_committee_executed", "sync_committee_missed",
+	}},
+	{"performance_gaps", []string{
+		"provider", "day", "public_key", "index", "reason", "detected_at", "resolved_at",
+	}},
+	{"quarantined_days", []string{
+		"provider", "day", "decideds_source", "problems", "quarantined_at",
+	}},
+}
+
+// SQLiteStore is a Store backed by an embedded SQLite file, with the rewards
+// functions of the PostgreSQL schema rewritten as queries.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens the SQLite file at path, creating it and its tables if needed.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	// SQLite allows a single writer, and in-memory databases are per connection.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables in %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the SQLite file.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// ImportSnapshot loads a snapshot of the given network, exported at the given schema
+// version, into the store, which must hold no data. All tables are loaded in a single
+// transaction, which is rolled back if the snapshot doesn't match its manifest.
+func (s *SQLiteStore) ImportSnapshot(
+	ctx context.Context,
+	r io.Reader,
+	network string,
+	schemaVersion int,
+) (*snapshot.Manifest, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	inserts := map[string]string{}
+	for _, t := range sqliteTables {
+		var empty bool
+		if err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT NOT EXISTS (SELECT 1 FROM %s)", t.name)).Scan(&empty); err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", t.name, err)
+		}
+		if !empty {
+			return nil, fmt.Errorf("table %s is not empty: snapshots can only be imported into an empty file", t.name)
+		}
+
+		// Rows are inserted from their JSON, like json_populate_recordset does in PostgreSQL.
+		quoted := make([]string, len(t.columns))
+		values := make([]string, len(t.columns))
+		for i, column := range t.columns {
+			quoted[i] = `"` + column + `"`
+			values[i] = fmt.Sprintf("json_extract(value, '$.%s')", column)
+		}
+		inserts[t.name] = fmt.Sprintf(
+			"INSERT INTO %s (%s) SELECT %s FROM json_each(?)",
+			t.name, strings.Join(quoted, ", "), strings.Join(values, ", "),
+		)
+	}
+
+	check := func(manifest *snapshot.Manifest) error {
+		return snapshot.CheckManifest(manifest, network, schemaVersion)
+	}
+	insert := func(table string, rows []json.RawMessage) error {
+		query, ok := inserts[table]
+		if !ok {
+			return nil
+		}
+		data, err := json.Marshal(rows)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, query, string(data))
+		return err
+	}
+	manifest, err := snapshot.Read(r, check, insert)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return manifest, nil
+}
+
+func (s *SQLiteStore) State(ctx context.Context) (*State, error) {
+	var (
+		state            State
+		earliest, latest sql.NullString
+	)
+	err := s.db.QueryRowContext(
+		ctx,
+		"SELECT network_name, earliest_validator_performance, latest_validator_performance FROM state ORDER BY id LIMIT 1",
+	).Scan(&state.NetworkName, &earliest, &latest)
+	if err != nil {
+		return nil, err
+	}
+	if state.EarliestValidatorPerformance, err = parseDay(earliest); err != nil {
+		return nil, err
+	}
+	if state.LatestValidatorPerformance, err = parseDay(latest); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *SQLiteStore) SetOwnerRedirects(ctx context.Context, redirects rewards.OwnerRedirects) error {
+	rows := make(map[string]string, len(redirects))
+	for from, to := range redirects {
+		rows[from.String()] = to.String()
+	}
+	return s.setRedirects(ctx, "owner_redirects", "from_address", rows)
+}
+
+func (s *SQLiteStore) SetValidatorRedirects(ctx context.Context, redirects rewards.ValidatorRedirects) error {
+	rows := make(map[string]string, len(redirects))
+	for pubkey, to := range redirects {
+		rows[pubkey.String()] = to.String()
+	}
+	return s.setRedirects(ctx, "validator_redirects", "public_key", rows)
+}
+
+func (s *SQLiteStore) setRedirects(ctx context.Context, table, fromColumn string, redirects map[string]string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+		return fmt.Errorf("failed to empty %s: %w", table, err)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s, to_address) VALUES (?, ?)", table, fromColumn)
+	for from, to := range redirects {
+		if _, err := tx.ExecContext(ctx, insert, from, to); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteParticipationsByValidator is participations_by_validator from the PostgreSQL schema.
+const sqliteParticipationsByValidator = `
+	WITH vp_redirected AS (
+		SELECT
+			vp.owner_address,
+			vp.public_key,
+			CASE
+				WHEN :pectra_support THEN vp.end_effective_balance
+				ELSE 32000000000
+			END AS end_effective_balance,
+			COALESCE(
+				CASE WHEN :validator_redirects_support THEN vr.to_address END,
+				CASE WHEN :owner_redirects_support THEN owr.to_address END,
+				vp.owner_address
+			) AS recipient_address,
+			(vp.attestations_executed >= :min_attestations) AND (vp.decideds >= :min_decideds) AS is_active
+		FROM validator_performances vp
+		LEFT JOIN validator_redirects vr ON :validator_redirects_support AND vp.public_key = vr.public_key
+		LEFT JOIN owner_redirects owr ON :owner_redirects_support AND vp.owner_address = owr.from_address
+		LEFT JOIN validators v ON vp.public_key = v.public_key
+		WHERE vp.provider = :provider
+		  AND vp.day >= :from_day AND vp.day < :to_day
+		  AND vp.solvent_whole_day
+		  AND (
+			  CASE :migration_filter
+				  WHEN 'ssv' THEN (v.migration_day IS NULL OR vp.day < v.migration_day)
+				  WHEN 'eth' THEN (v.migration_day IS NOT NULL AND vp.day >= v.migration_day)
+				  ELSE FALSE
+			  END
+		  )
+	)
+	SELECT
+		vpr.recipient_address,
+		vpr.owner_address,
+		vpr.public_key,
+		COUNT(*) FILTER (WHERE vpr.is_active) AS active_days,
+		COUNT(*) AS registered_days,
+		COALESCE(SUM(end_effective_balance) FILTER (WHERE vpr.is_active), 0) AS total_active_effective_balance,
+		COALESCE(SUM(end_effective_balance), 0) AS total_registered_effective_balance
+	FROM vp_redirected vpr
+	GROUP BY vpr.recipient_address, vpr.owner_address, vpr.public_key
+	HAVING COUNT(*) FILTER (WHERE is_active) > 0`
+
+func (s *SQLiteStore) ValidatorParticipations(ctx context.Context, q Query) ([]ValidatorParticipation, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		sqliteParticipationsByValidator+"\nORDER BY public_key, owner_address, recipient_address",
+		q.args()...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participations []ValidatorParticipation
+	for rows.Next() {
+		var p ValidatorParticipation
+		if err := rows.Scan(
+			&p.RecipientAddress,
+			&p.OwnerAddress,
+			&p.PublicKey,
+			&p.ActiveDays,
+			&p.RegisteredDays,
+			&p.TotalActiveEffectiveBalance,
+			&p.TotalRegisteredEffectiveBalance,
+		); err != nil {
+			return nil, err
+		}
+		participations = append(participations, p)
+	}
+	return participations, rows.Err()
+}
+
+func (s *SQLiteStore) OwnerParticipations(ctx context.Context, q Query) ([]OwnerParticipation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH adv AS (`+sqliteParticipationsByValidator+`)
+		SELECT
+			adv.recipient_address,
+			adv.owner_address,
+			COUNT(adv.public_key) AS validators,
+			SUM(adv.active_days) AS active_days,
+			SUM(adv.registered_days) AS registered_days,
+			SUM(adv.total_active_effective_balance) AS total_active_effective_balance,
+			SUM(adv.total_registered_effective_balance) AS total_registered_effective_balance
+		FROM adv
+		GROUP BY adv.owner_address, adv.recipient_address
+		ORDER BY owner_address, recipient_address`,
+		q.args()...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participations []OwnerParticipation
+	for rows.Next() {
+		var p OwnerParticipation
+		if err := rows.Scan(
+			&p.RecipientAddress,
+			&p.OwnerAddress,
+			&p.Validators,
+			&p.ActiveDays,
+			&p.RegisteredDays,
+			&p.TotalActiveEffectiveBalance,
+			&p.TotalRegisteredEffectiveBalance,
+		); err != nil {
+			return nil, err
+		}
+		participations = append(participations, p)
+	}
+	return participations, rows.Err()
+}
+
+func (s *SQLiteStore) RecipientParticipations(ctx context.Context, q Query) ([]RecipientParticipation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH adv AS (`+sqliteParticipationsByValidator+`)
+		SELECT
+			adv.recipient_address,
+			COUNT(adv.public_key) AS validators,
+			SUM(adv.active_days) AS active_days,
+			SUM(adv.registered_days) AS registered_days,
+			SUM(adv.total_active_effective_balance) AS total_active_effective_balance,
+			SUM(adv.total_registered_effective_balance) AS total_registered_effective_balance
+		FROM adv
+		GROUP BY adv.recipient_address
+		ORDER BY recipient_address`,
+		q.args()...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participations []RecipientParticipation
+	for rows.Next() {
+		var p RecipientParticipation
+		if err := rows.Scan(
+			&p.RecipientAddress,
+			&p.Validators,
+			&p.ActiveDays,
+			&p.RegisteredDays,
+			&p.TotalActiveEffectiveBalance,
+			&p.TotalRegisteredEffectiveBalance,
+		); err != nil {
+			return nil, err
+		}
+		participations = append(participations, p)
+	}
+	return participations, rows.Err()
+}
+
+// sqliteExclusionsByValidator is exclusions_by_validator from the PostgreSQL schema.
+const sqliteExclusionsByValidator = `
+	WITH vp_excluded AS (
+		SELECT
+			vp.day,
+			vp.from_epoch,
+			vp.to_epoch,
+			vp.owner_address,
+			vp.public_key,
+			vp.start_beacon_status,
+			vp.end_beacon_status,
+			CASE
+				WHEN NOT vp.solvent_whole_day THEN 'not_registered_whole_day'
+				WHEN vp.attestations_executed < :min_attestations THEN 'not_enough_attestations'
+				WHEN vp.decideds < :min_decideds THEN 'not_enough_decideds'
+				ELSE 'unknown'
+			END AS exclusion_reason
+		FROM validator_performances AS vp
+		LEFT JOIN validators val ON vp.public_key = val.public_key
+		WHERE vp.provider = :provider
+		  AND vp.day >= :from_day AND vp.day < :to_day
+		  AND (NOT vp.solvent_whole_day OR vp.attestations_executed < :min_attestations OR vp.decideds < :min_decideds)
+		  AND (
+			  CASE :migration_filter
+				  WHEN 'ssv' THEN (val.migration_day IS NULL OR vp.day < val.migration_day)
+				  WHEN 'eth' THEN (val.migration_day IS NOT NULL AND vp.day >= val.migration_day)
+				  ELSE FALSE
+			  END
+		  )
+	)
+	SELECT
+		v.day,
+		v.from_epoch,
+		v.to_epoch,
+		v.public_key,
+		v.start_beacon_status,
+		v.end_beacon_status,
+		(
+			SELECT group_concat(ve.event_name, ', ' ORDER BY ve.block_number, ve.log_index, ve.id)
+			FROM validator_events AS ve
+			WHERE ve.public_key = v.public_key
+			  AND (ve.slot / (SELECT slots_per_epoch FROM state ORDER BY id LIMIT 1)) BETWEEN v.from_epoch AND v.to_epoch
+		) AS events,
+		v.exclusion_reason
+	FROM vp_excluded AS v
+	ORDER BY v.day, v.public_key`
+
+func (s *SQLiteStore) Exclusions(ctx context.Context, q Query) ([]Exclusion, error) {
+	rows, err := s.db.QueryContext(ctx, sqliteExclusionsByValidator, q.args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exclusions []Exclusion
+	for rows.Next() {
+		var (
+			e                              Exclusion
+			day                            string
+			startStatus, endStatus, events sql.NullString
+		)
+		if err := rows.Scan(
+			&day,
+			&e.FromEpoch,
+			&e.ToEpoch,
+			&e.PublicKey,
+			&startStatus,
+			&endStatus,
+			&events,
+			&e.ExclusionReason,
+		); err != nil {
+			return nil, err
+		}
+		if e.Day, err = parseDay(sql.NullString{String: day, Valid: true}); err != nil {
+			return nil, err
+		}
+		e.StartBeaconStatus = startStatus.String
+		e.EndBeaconStatus = endStatus.String
+		e.Events = events.String
+		exclusions = append(exclusions, e)
+	}
+	return exclusions, rows.Err()
+}
+
+func (s *SQLiteStore) UnresolvedPerformanceGaps(
+	ctx context.Context,
+	provider string,
+	fromDay, toDay time.Time,
+) ([]PerformanceGap, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, public_key, "index", reason
+		FROM performance_gaps
+		WHERE provider = ? AND day BETWEEN ? AND ? AND resolved_at IS NULL
+		ORDER BY day, public_key`,
+		provider, fromDay.Format(time.DateOnly), toDay.Format(time.DateOnly),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get performance gaps: %w", err)
+	}
+	defer rows.Close()
+
+	var gaps []PerformanceGap
+	for rows.Next() {
+		var (
+			gap PerformanceGap
+			day string
+		)
+		if err := rows.Scan(&day, &gap.PublicKey, &gap.Index, &gap.Reason); err != nil {
+			return nil, err
+		}
+		if gap.Day, err = parseDay(sql.NullString{String: day, Valid: true}); err != nil {
+			return nil, err
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps, rows.Err()
+}
+
+func (s *SQLiteStore) QuarantinedDays(
+	ctx context.Context,
+	provider string,
+	fromDay, toDay time.Time,
+) ([]QuarantinedDay, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, decideds_source, problems
+		FROM quarantined_days
+		WHERE provider = ? AND day BETWEEN ? AND ?
+		ORDER BY day`,
+		provider, fromDay.Format(time.DateOnly), toDay.Format(time.DateOnly),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quarantined days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []QuarantinedDay
+	for rows.Next() {
+		var (
+			d   QuarantinedDay
+			day string
+		)
+		if err := rows.Scan(&day, &d.DecidedsSource, &d.Problems); err != nil {
+			return nil, err
+		}
+		if d.Day, err = parseDay(sql.NullString{String: day, Valid: true}); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// args returns the query's named parameters, with the period as a range of days.
+func (q Query) args() []any {
+	fromDay := time.Date(q.Period.Year(), q.Period.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return []any{
+		sql.Named("provider", q.Provider),
+		sql.Named("min_attestations", q.MinAttestations),
+		sql.Named("min_decideds", q.MinDecideds),
+		sql.Named("from_day", fromDay.Format(time.DateOnly)),
+		sql.Named("to_day", fromDay.AddDate(0, 1, 0).Format(time.DateOnly)),
+		sql.Named("owner_redirects_support", q.OwnerRedirects),
+		sql.Named("validator_redirects_support", q.ValidatorRedirects),
+		sql.Named("pectra_support", q.PectraSupport),
+		sql.Named("migration_filter", q.MigrationFilter),
+	}
+}
+
+// parseDay parses a date the way the PostgreSQL driver does, so that days are
+// represented, and formatted in exports, the same with both backends.
+func parseDay(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+	day, err := pq.ParseTimestamp(nil, s.String)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse day %q: %w", s.String, err)
+	}
+	return day, nil
+}
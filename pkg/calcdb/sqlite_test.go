@@ -0,0 +1,197 @@
+package calcdb
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv-rewards/pkg/database"
+)
+
+func openFixtureSQLite(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := OpenSQLite(filepath.Join(t.TempDir(), "rewards.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	schemaVersion, err := database.LatestVersion()
+	require.NoError(t, err)
+	manifest, err := store.ImportSnapshot(context.Background(), bytes.NewReader(fixtureSnapshot(t)), fixtureNetwork, schemaVersion)
+	require.NoError(t, err)
+	require.Equal(t, fixtureNetwork, manifest.Network)
+	return store
+}
+
+func TestSQLiteStore(t *testing.T) {
+	ctx := context.Background()
+	store := openFixtureSQLite(t)
+	queries := fixtureQueries()
+	base := queries[0]
+
+	t.Run("state", func(t *testing.T) {
+		state, err := store.State(ctx)
+		require.NoError(t, err)
+		require.Equal(t, fixtureNetwork, state.NetworkName)
+		require.True(t, state.EarliestValidatorPerformance.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		require.True(t, state.LatestValidatorPerformance.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("participations", func(t *testing.T) {
+		validators, err := store.ValidatorParticipations(ctx, base)
+		require.NoError(t, err)
+		require.Equal(t, []ValidatorParticipation{
+			{ownerA, ownerA, validatorA, 3, 3, 96e9, 96e9},
+			{ownerA, ownerA, validatorB, 1, 2, 32e9, 64e9},
+			{ownerB, ownerB, validatorC, 1, 1, 32e9, 32e9},
+		}, validators)
+
+		owners, err := store.OwnerParticipations(ctx, base)
+		require.NoError(t, err)
+		require.Equal(t, []OwnerParticipation{
+			{ownerA, ownerA, 2, 4, 5, 128e9, 160e9},
+			{ownerB, ownerB, 1, 1, 1, 32e9, 32e9},
+		}, owners)
+
+		recipients, err := store.RecipientParticipations(ctx, base)
+		require.NoError(t, err)
+		require.Equal(t, []RecipientParticipation{
+			{ownerA, 2, 4, 5, 128e9, 160e9},
+			{ownerB, 1, 1, 1, 32e9, 32e9},
+		}, recipients)
+	})
+
+	t.Run("migrated with pectra", func(t *testing.T) {
+		q := base
+		q.MigrationFilter = MigrationFilterETH
+		q.PectraSupport = true
+		validators, err := store.ValidatorParticipations(ctx, q)
+		require.NoError(t, err)
+		require.Equal(t, []ValidatorParticipation{
+			{ownerB, ownerB, validatorC, 1, 2, 64e9, 128e9},
+		}, validators)
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		ownerRedirects, validatorRedirects := fixtureRedirects(t)
+		require.NoError(t, store.SetOwnerRedirects(ctx, ownerRedirects))
+		require.NoError(t, store.SetValidatorRedirects(ctx, validatorRedirects))
+
+		q := base
+		q.OwnerRedirects = true
+		q.ValidatorRedirects = true
+		owners, err := store.OwnerParticipations(ctx, q)
+		require.NoError(t, err)
+		require.Equal(t, []OwnerParticipation{
+			{recipientA, ownerA, 2, 4, 5, 128e9, 160e9},
+			{recipientC, ownerB, 1, 1, 1, 32e9, 32e9},
+		}, owners)
+
+		// Redirects only apply to queries that support them.
+		recipients, err := store.RecipientParticipations(ctx, base)
+		require.NoError(t, err)
+		require.Equal(t, ownerA, recipients[0].RecipientAddress)
+	})
+
+	t.Run("exclusions", func(t *testing.T) {
+		exclusions, err := store.Exclusions(ctx, base)
+		require.NoError(t, err)
+		require.Len(t, exclusions, 2)
+		require.Equal(t, "2024-01-02", exclusions[0].Day.Format(time.DateOnly))
+		require.Equal(t, Exclusion{
+			Day:               exclusions[0].Day,
+			FromEpoch:         225,
+			ToEpoch:           449,
+			PublicKey:         validatorB,
+			StartBeaconStatus: "active_ongoing",
+			EndBeaconStatus:   "active_ongoing",
+			Events:            "ValidatorExited, ValidatorAdded",
+			ExclusionReason:   "not_enough_attestations",
+		}, exclusions[0])
+		require.Equal(t, validatorB, exclusions[1].PublicKey)
+		require.Equal(t, "ValidatorRemoved", exclusions[1].Events)
+		require.Equal(t, "not_registered_whole_day", exclusions[1].ExclusionReason)
+
+		q := base
+		q.MigrationFilter = MigrationFilterETH
+		exclusions, err = store.Exclusions(ctx, q)
+		require.NoError(t, err)
+		require.Len(t, exclusions, 1)
+		require.Equal(t, validatorC, exclusions[0].PublicKey)
+		require.Equal(t, "", exclusions[0].Events)
+		require.Equal(t, "not_enough_decideds", exclusions[0].ExclusionReason)
+	})
+
+	t.Run("performance gaps", func(t *testing.T) {
+		january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		gaps, err := store.UnresolvedPerformanceGaps(ctx, "beaconcha", january, january.AddDate(0, 1, -1))
+		require.NoError(t, err)
+		require.Len(t, gaps, 2)
+		require.Equal(t, "2024-01-02", gaps[0].Day.Format(time.DateOnly))
+		require.Equal(t, PerformanceGap{
+			Day:       gaps[0].Day,
+			PublicKey: validatorD,
+			Index:     4,
+			Reason:    "missing",
+		}, gaps[0])
+		require.Equal(t, "2024-01-03", gaps[1].Day.Format(time.DateOnly))
+		require.Equal(t, validatorB, gaps[1].PublicKey)
+
+		// Days outside of the range aren't included.
+		gaps, err = store.UnresolvedPerformanceGaps(ctx, "beaconcha", january, january)
+		require.NoError(t, err)
+		require.Empty(t, gaps)
+	})
+
+	t.Run("quarantined days", func(t *testing.T) {
+		january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		days, err := store.QuarantinedDays(ctx, "beaconcha", january, january.AddDate(0, 1, -1))
+		require.NoError(t, err)
+		require.Len(t, days, 1)
+		require.Equal(t, "2024-01-03", days[0].Day.Format(time.DateOnly))
+		require.Equal(t, "ssv_api", days[0].DecidedsSource)
+		require.Equal(t, "no validator has a decided count above zero", days[0].Problems)
+
+		days, err = store.QuarantinedDays(ctx, "e2m", january, january.AddDate(0, 1, -1))
+		require.NoError(t, err)
+		require.Empty(t, days)
+	})
+
+	t.Run("period and provider", func(t *testing.T) {
+		february, e2m := queries[len(queries)-2], queries[len(queries)-1]
+		validators, err := store.ValidatorParticipations(ctx, february)
+		require.NoError(t, err)
+		require.Equal(t, []ValidatorParticipation{{ownerA, ownerA, validatorA, 1, 1, 32e9, 32e9}}, validators)
+
+		validators, err = store.ValidatorParticipations(ctx, e2m)
+		require.NoError(t, err)
+		require.Equal(t, []ValidatorParticipation{{ownerB, ownerB, validatorD, 1, 1, 32e9, 32e9}}, validators)
+	})
+}
+
+func TestSQLiteImportSnapshot(t *testing.T) {
+	ctx := context.Background()
+	schemaVersion, err := database.LatestVersion()
+	require.NoError(t, err)
+
+	t.Run("not empty", func(t *testing.T) {
+		store := openFixtureSQLite(t)
+		_, err := store.ImportSnapshot(ctx, bytes.NewReader(fixtureSnapshot(t)), fixtureNetwork, schemaVersion)
+		require.ErrorContains(t, err, "table state is not empty")
+	})
+
+	t.Run("wrong network", func(t *testing.T) {
+		store, err := OpenSQLite(filepath.Join(t.TempDir(), "rewards.db"))
+		require.NoError(t, err)
+		defer store.Close()
+		_, err = store.ImportSnapshot(ctx, bytes.NewReader(fixtureSnapshot(t)), "holesky", schemaVersion)
+		require.ErrorContains(t, err, "snapshot is of network mainnet, want holesky")
+
+		// The failed import leaves no data behind.
+		_, err = store.State(ctx)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,157 @@
+// Package calcdb provides the data that rewards are calculated from: the synced
+// state, the participation of validators, owners and recipients in a period, and
+// the days validators were excluded from it.
+//
+// Stores are backed either by the PostgreSQL database that data is synced to, or
+// by an embedded SQLite file imported from a snapshot, so that rewards can be
+// calculated without a database server. Both backends return the same results,
+// in the same order.
+package calcdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+)
+
+// Migration filters select which validators' days participate in a tree.
+const (
+	// MigrationFilterSSV selects the days before validators migrated to the ETH tree.
+	MigrationFilterSSV = "ssv"
+
+	// MigrationFilterETH selects the days from which validators migrated to the ETH tree.
+	MigrationFilterETH = "eth"
+)
+
+// Store provides the data that rewards are calculated from.
+type Store interface {
+	// State returns the synced state.
+	State(ctx context.Context) (*State, error)
+
+	// SetOwnerRedirects replaces the owner redirects used by queries with OwnerRedirects set.
+	SetOwnerRedirects(ctx context.Context, redirects rewards.OwnerRedirects) error
+
+	// SetValidatorRedirects replaces the validator redirects used by queries with ValidatorRedirects set.
+	SetValidatorRedirects(ctx context.Context, redirects rewards.ValidatorRedirects) error
+
+	// ValidatorParticipations returns the validators that were active in the period,
+	// ordered by public key, owner and recipient.
+	ValidatorParticipations(ctx context.Context, q Query) ([]ValidatorParticipation, error)
+
+	// OwnerParticipations returns the owners of validators that were active in the period,
+	// ordered by owner and recipient.
+	OwnerParticipations(ctx context.Context, q Query) ([]OwnerParticipation, error)
+
+	// RecipientParticipations returns the recipients of validators that were active in the period,
+	// ordered by recipient.
+	RecipientParticipations(ctx context.Context, q Query) ([]RecipientParticipation, error)
+
+	// Exclusions returns the days validators weren't active in the period,
+	// ordered by day and public key.
+	Exclusions(ctx context.Context, q Query) ([]Exclusion, error)
+
+	// UnresolvedPerformanceGaps returns the provider's unresolved performance gaps
+	// between the given days (inclusive), ordered by day and public key.
+	UnresolvedPerformanceGaps(ctx context.Context, provider string, fromDay, toDay time.Time) ([]PerformanceGap, error)
+
+	// QuarantinedDays returns the provider's days between the given days (inclusive)
+	// that were synced without decided counts, ordered by day.
+	QuarantinedDays(ctx context.Context, provider string, fromDay, toDay time.Time) ([]QuarantinedDay, error)
+}
+
+// State is the synced state.
+type State struct {
+	NetworkName string
+
+	// EarliestValidatorPerformance and LatestValidatorPerformance are the range of days
+	// with synced validator performance, or zero if none was synced.
+	EarliestValidatorPerformance time.Time
+	LatestValidatorPerformance   time.Time
+}
+
+// Query selects the validator performance of a period.
+type Query struct {
+	Provider        string
+	MinAttestations int
+	MinDecideds     int
+
+	// Period is the first day of the month to query.
+	Period time.Time
+
+	OwnerRedirects     bool
+	ValidatorRedirects bool
+
+	// PectraSupport weighs validators by their effective balance, rather than 32 ETH.
+	PectraSupport bool
+
+	// MigrationFilter is MigrationFilterSSV or MigrationFilterETH.
+	MigrationFilter string
+}
+
+// ValidatorParticipation is a validator's participation in a period.
+// Effective balances are in Gwei.
+type ValidatorParticipation struct {
+	RecipientAddress                string
+	OwnerAddress                    string
+	PublicKey                       string
+	ActiveDays                      int
+	RegisteredDays                  int
+	TotalActiveEffectiveBalance     int64
+	TotalRegisteredEffectiveBalance int64
+}
+
+// OwnerParticipation is the participation of an owner's validators in a period.
+// Effective balances are in Gwei.
+type OwnerParticipation struct {
+	RecipientAddress                string
+	OwnerAddress                    string
+	Validators                      int
+	ActiveDays                      int
+	RegisteredDays                  int
+	TotalActiveEffectiveBalance     int64
+	TotalRegisteredEffectiveBalance int64
+}
+
+// RecipientParticipation is the participation of a recipient's validators in a period.
+// Effective balances are in Gwei.
+type RecipientParticipation struct {
+	RecipientAddress                string
+	Validators                      int
+	ActiveDays                      int
+	RegisteredDays                  int
+	TotalActiveEffectiveBalance     int64
+	TotalRegisteredEffectiveBalance int64
+}
+
+// PerformanceGap is a day a validator was attesting in, but was synced without
+// performance.
+type PerformanceGap struct {
+	Day       time.Time
+	PublicKey string
+	Index     int
+	Reason    string
+}
+
+// QuarantinedDay is a day whose decided counts failed the coverage checks.
+type QuarantinedDay struct {
+	Day            time.Time
+	DecidedsSource string
+	Problems       string
+}
+
+// Exclusion is a day a validator wasn't active.
+type Exclusion struct {
+	Day               time.Time
+	FromEpoch         phase0.Epoch
+	ToEpoch           phase0.Epoch
+	PublicKey         string
+	StartBeaconStatus string
+	EndBeaconStatus   string
+
+	// Events are the validator's events during the day, separated by commas.
+	Events          string
+	ExclusionReason string
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		require.Equal(t, i+1, migration.Version)
+		require.NotEmpty(t, migration.Up)
+		require.NotEmpty(t, migration.Down)
+	}
+
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	require.Equal(t, len(migrations), latest)
+}
+
+func TestParseMigrations(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+	tests := []struct {
+		name        string
+		files       fstest.MapFS
+		expected    []Migration
+		expectedErr string
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"m/0002_second.up.sql":   file("UP 2"),
+				"m/0002_second.down.sql": file("DOWN 2"),
+				"m/0001_first.up.sql":    file("UP 1"),
+				"m/0001_first.down.sql":  file("DOWN 1"),
+			},
+			expected: []Migration{
+				{Version: 1, Name: "first", Up: "UP 1", Down: "DOWN 1"},
+				{Version: 2, Name: "second", Up: "UP 2", Down: "DOWN 2"},
+			},
+		},
+		{
+			name:        "invalid file name",
+			files:       fstest.MapFS{"m/first.sql": file("UP")},
+			expectedErr: `invalid migration file name "first.sql"`,
+		},
+		{
+			name:        "missing down",
+			files:       fstest.MapFS{"m/0001_first.up.sql": file("UP")},
+			expectedErr: "migration 1_first must have both up and down files",
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"m/0001_first.up.sql":   file("UP"),
+				"m/0001_other.down.sql": file("DOWN"),
+			},
+			expectedErr: "conflicting names for migration 1",
+		},
+		{
+			name: "gap in versions",
+			files: fstest.MapFS{
+				"m/0001_first.up.sql":   file("UP 1"),
+				"m/0001_first.down.sql": file("DOWN 1"),
+				"m/0003_third.up.sql":   file("UP 3"),
+				"m/0003_third.down.sql": file("DOWN 3"),
+			},
+			expectedErr: "migration versions must be sequential from 1, got 3 at position 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := parseMigrations(tt.files, "m")
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, migrations)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_validator_performances;
+DROP TABLE IF EXISTS validator_performances;
+DROP TYPE IF EXISTS provider_type;
+DROP INDEX IF EXISTS idx_validator_events_public_key;
+DROP TABLE IF EXISTS validator_redirects;
+DROP TABLE IF EXISTS owner_redirects;
+DROP TABLE IF EXISTS validator_events;
+DROP TABLE IF EXISTS validators;
+DROP TABLE IF EXISTS contract_events;
+DROP TABLE IF EXISTS state;
@@ -1,4 +1,5 @@
 -- PostgreSQL schema for ssv-rewards.
+-- Uses IF NOT EXISTS so that databases created before versioned migrations are adopted as is.
 CREATE TABLE IF NOT EXISTS state (
 	id SERIAL PRIMARY KEY,
 	network_name TEXT NOT NULL,
@@ -0,0 +1,4 @@
+DROP FUNCTION IF EXISTS exclusions_by_validator(provider_type, INTEGER, INTEGER, DATE, DATE, TEXT);
+DROP FUNCTION IF EXISTS participations_by_owner(provider_type, INTEGER, INTEGER, DATE, DATE, BOOLEAN, BOOLEAN, BOOLEAN, TEXT);
+DROP FUNCTION IF EXISTS participations_by_recipient(provider_type, INTEGER, INTEGER, DATE, DATE, BOOLEAN, BOOLEAN, BOOLEAN, TEXT);
+DROP FUNCTION IF EXISTS participations_by_validator(provider_type, INTEGER, INTEGER, DATE, DATE, BOOLEAN, BOOLEAN, BOOLEAN, TEXT);
@@ -1,5 +1,8 @@
--- Drop old function signatures to prevent overloaded ambiguity.
--- Required because CREATE OR REPLACE with a new parameter count creates a new overload.
+-- Stored functions for reward calculation.
+
+-- Drop function signatures from before versioned migrations to prevent overloaded
+-- ambiguity in adopted databases. Required because CREATE OR REPLACE with a new
+-- parameter count creates a new overload.
 DROP FUNCTION IF EXISTS participations_by_validator(provider_type, INTEGER, INTEGER, DATE, DATE, BOOLEAN, BOOLEAN, BOOLEAN);
 DROP FUNCTION IF EXISTS participations_by_recipient(provider_type, INTEGER, INTEGER, DATE, DATE, BOOLEAN, BOOLEAN, BOOLEAN);
 DROP FUNCTION IF EXISTS participations_by_owner(provider_type, INTEGER, INTEGER, DATE, DATE, BOOLEAN, BOOLEAN, BOOLEAN);
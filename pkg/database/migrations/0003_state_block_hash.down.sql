@@ -0,0 +1 @@
+ALTER TABLE state DROP COLUMN IF EXISTS highest_block_hash;
@@ -0,0 +1,2 @@
+-- Hash of the highest synced block, to detect reorgs of blocks without events.
+ALTER TABLE state ADD COLUMN IF NOT EXISTS highest_block_hash TEXT;
@@ -0,0 +1,2 @@
+-- Time of the highest synced block, so that it's known without an execution node.
+ALTER TABLE state ADD COLUMN IF NOT EXISTS highest_block_time TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Enum values can't be dropped, so only the performances of the provider are removed.
+DELETE FROM validator_performances WHERE provider = 'beaconnode';
@@ -0,0 +1,2 @@
+-- Performance fetched from the beacon node itself.
+ALTER TYPE provider_type ADD VALUE IF NOT EXISTS 'beaconnode';
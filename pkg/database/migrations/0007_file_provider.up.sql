@@ -0,0 +1,2 @@
+-- Performance read from imported files.
+ALTER TYPE provider_type ADD VALUE IF NOT EXISTS 'file';
@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS validator_performance_sources;
+ALTER TABLE validator_performances DROP COLUMN IF EXISTS source;
@@ -0,0 +1,12 @@
+-- Provider that a row's performance came from, which differs from the row's provider
+-- when it was filled in from a fallback, and is NULL when no provider had it.
+ALTER TABLE validator_performances ADD COLUMN IF NOT EXISTS source provider_type;
+
+UPDATE validator_performances SET source = provider
+WHERE source IS NULL AND attestation_rate IS NOT NULL;
+
+-- Number of rows of each day by the provider they came from.
+CREATE OR REPLACE VIEW validator_performance_sources AS
+SELECT provider, day, source, COUNT(*) AS validators
+FROM validator_performances
+GROUP BY provider, day, source;
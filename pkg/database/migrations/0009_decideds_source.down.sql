@@ -0,0 +1,2 @@
+ALTER TABLE validator_performances DROP COLUMN IF EXISTS decideds_source;
+DROP TYPE IF EXISTS decideds_source;
@@ -0,0 +1,7 @@
+-- Source of the decided counts of validator_performances rows, NULL if they're unset.
+CREATE TYPE decideds_source AS ENUM ('ssv_api', 'exporter', 'file');
+
+ALTER TABLE validator_performances ADD COLUMN IF NOT EXISTS decideds_source decideds_source;
+
+UPDATE validator_performances SET decideds_source = 'ssv_api'
+WHERE decideds_source IS NULL AND decideds IS NOT NULL;
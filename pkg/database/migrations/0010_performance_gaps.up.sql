@@ -0,0 +1,17 @@
+-- Validators that were attesting in a day, but that neither the performance provider
+-- nor its fallbacks had performance for. Gaps are resolved once the validator's day
+-- is synced with performance, such as with sync --redo-days.
+CREATE TABLE IF NOT EXISTS performance_gaps (
+	provider provider_type NOT NULL,
+	day DATE NOT NULL,
+	public_key TEXT NOT NULL REFERENCES validators(public_key),
+	index INT NOT NULL,
+	reason TEXT NOT NULL,
+	detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	resolved_at TIMESTAMPTZ,
+
+	PRIMARY KEY (provider, day, public_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_performance_gaps_unresolved ON performance_gaps(provider, day)
+WHERE resolved_at IS NULL;
@@ -0,0 +1,77 @@
+-- The slots per epoch of the synced chain, which were assumed to be 32 before they
+-- were loaded from the consensus node's spec.
+ALTER TABLE state ADD COLUMN IF NOT EXISTS slots_per_epoch INTEGER NOT NULL DEFAULT 32;
+
+-- Aggregates the events of excluded validators in the order they were emitted,
+-- so that exclusions are reproducible across databases.
+CREATE OR REPLACE FUNCTION exclusions_by_validator(
+    _provider provider_type,
+    min_attestations INTEGER,
+    min_decideds INTEGER,
+    from_period DATE,
+    to_period DATE default NULL,
+    migration_filter TEXT DEFAULT 'ssv'
+)
+RETURNS TABLE (
+	day DATE,
+	from_epoch INTEGER,
+	to_epoch INTEGER,
+    owner_address TEXT,
+    public_key TEXT,
+    start_beacon_status TEXT,
+    end_beacon_status TEXT,
+    events TEXT,
+    exclusion_reason TEXT
+) AS $$
+DECLARE
+    _from_month DATE := date_trunc('month', from_period);
+    _to_month DATE := date_trunc('month', COALESCE(to_period, from_period));
+    _slots_per_epoch INTEGER := COALESCE((SELECT s.slots_per_epoch FROM state AS s LIMIT 1), 32);
+BEGIN
+    RETURN QUERY
+    WITH vp_excluded AS (
+        SELECT
+            vp.day,
+            vp.from_epoch,
+            vp.to_epoch,
+            vp.owner_address,
+            vp.public_key,
+            vp.start_beacon_status,
+            vp.end_beacon_status,
+            CASE
+                WHEN NOT vp.solvent_whole_day THEN 'not_registered_whole_day'
+                WHEN vp.attestations_executed < min_attestations THEN 'not_enough_attestations'
+                WHEN vp.decideds < min_decideds THEN 'not_enough_decideds'
+                ELSE 'unknown'
+            END AS exclusion_reason
+        FROM validator_performances AS vp
+        LEFT JOIN validators val ON vp.public_key = val.public_key
+        WHERE provider = _provider
+          AND vp.day >= _from_month AND vp.day < (_to_month + INTERVAL '1 month')
+          AND (NOT solvent_whole_day OR attestations_executed < min_attestations OR decideds < min_decideds)
+          AND (
+              CASE migration_filter
+                  WHEN 'ssv' THEN (val.migration_day IS NULL OR vp.day < val.migration_day)
+                  WHEN 'eth' THEN (val.migration_day IS NOT NULL AND vp.day >= val.migration_day)
+                  ELSE FALSE
+              END
+          )
+    )
+    SELECT
+    	v.day,
+    	v.from_epoch,
+    	v.to_epoch,
+        v.owner_address,
+        v.public_key,
+        v.start_beacon_status,
+        v.end_beacon_status,
+        (
+            SELECT string_agg(ve.event_name, ', ' ORDER BY ve.block_number, ve.log_index, ve.id)
+            FROM validator_events AS ve
+            WHERE ve.public_key = v.public_key
+              AND (ve.slot / _slots_per_epoch) BETWEEN v.from_epoch AND v.to_epoch
+        ) AS events,
+        v.exclusion_reason
+    FROM vp_excluded AS v;
+END;
+$$ LANGUAGE plpgsql STABLE;
@@ -0,0 +1,12 @@
+-- Days synced without decided counts, because the decided counts of the source failed
+-- the coverage checks (sync --decideds-quarantine). calc refuses rounds with
+-- quarantined days, and sync fetches them again until they pass.
+CREATE TABLE IF NOT EXISTS quarantined_days (
+	provider provider_type NOT NULL,
+	day DATE NOT NULL,
+	decideds_source decideds_source NOT NULL,
+	problems TEXT NOT NULL,
+	quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY (provider, day)
+);
@@ -0,0 +1,137 @@
+// Package database scopes the PostgreSQL database by SSV network.
+//
+// Each network lives in its own PostgreSQL schema, named after the network, which
+// holds all of its tables, types and functions. Connections select the schema of
+// their network with the search_path connection parameter, so queries use
+// unqualified names regardless of the network.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// legacySchema is where all data lived before the database was scoped by network.
+const legacySchema = "public"
+
+// NetworkSchema returns the name of the schema holding the given network's data.
+func NetworkSchema(network string) string {
+	return strings.ToLower(network)
+}
+
+// WithSearchPath returns the connection string with its search_path set to the given schema.
+// Both URL (postgres://...) and key/value (host=... dbname=...) connection strings are supported.
+func WithSearchPath(connStr, schema string) (string, error) {
+	searchPath := pq.QuoteIdentifier(schema)
+	if strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://") {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse connection string: %w", err)
+		}
+		query := u.Query()
+		query.Set("search_path", searchPath)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+	if strings.Contains(connStr, "search_path=") {
+		return "", fmt.Errorf("connection string must not set search_path, which is derived from the network")
+	}
+	return strings.TrimSpace(connStr + " search_path=" + searchPath), nil
+}
+
+// SchemaExists reports whether the given schema exists.
+func SchemaExists(ctx context.Context, db *sql.DB, schema string) (bool, error) {
+	var exists bool
+	err := db.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)",
+		schema,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check schema %q: %w", schema, err)
+	}
+	return exists, nil
+}
+
+// EnsureSchema creates the given network's schema if it doesn't exist.
+//
+// If the legacy public schema holds data synced for the same network, it's
+// moved to the network's schema instead, and reports adopted as true.
+func EnsureSchema(ctx context.Context, db *sql.DB, network, schema string) (adopted bool, err error) {
+	exists, err := SchemaExists(ctx, db, schema)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	legacyNetwork, err := legacyNetworkName(ctx, db)
+	if err != nil {
+		return false, err
+	}
+	if legacyNetwork == network && schema != legacySchema {
+		_, err := db.ExecContext(ctx, fmt.Sprintf(
+			"ALTER SCHEMA %s RENAME TO %s; CREATE SCHEMA %s;",
+			pq.QuoteIdentifier(legacySchema),
+			pq.QuoteIdentifier(schema),
+			pq.QuoteIdentifier(legacySchema),
+		))
+		if err != nil {
+			return false, fmt.Errorf("failed to move schema %q to %q: %w", legacySchema, schema, err)
+		}
+		return true, nil
+	}
+
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+pq.QuoteIdentifier(schema)); err != nil {
+		return false, fmt.Errorf("failed to create schema %q: %w", schema, err)
+	}
+	return false, nil
+}
+
+// ResetSchema drops the given schema with all of its data and recreates it empty.
+func ResetSchema(ctx context.Context, db *sql.DB, schema string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(
+		"DROP SCHEMA IF EXISTS %[1]s CASCADE; CREATE SCHEMA %[1]s;",
+		pq.QuoteIdentifier(schema),
+	))
+	if err != nil {
+		return fmt.Errorf("failed to reset schema %q: %w", schema, err)
+	}
+	return nil
+}
+
+// legacyNetworkName returns the network synced into the legacy public schema,
+// or an empty string if it holds no synced data.
+func legacyNetworkName(ctx context.Context, db *sql.DB) (string, error) {
+	var hasState bool
+	err := db.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = 'state')",
+		legacySchema,
+	).Scan(&hasState)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for legacy state: %w", err)
+	}
+	if !hasState {
+		return "", nil
+	}
+
+	var network string
+	err = db.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT network_name FROM %s.state LIMIT 1", pq.QuoteIdentifier(legacySchema)),
+	).Scan(&network)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get legacy state: %w", err)
+	}
+	return network, nil
+}
@@ -6,6 +6,8 @@ package models
 var TableNames = struct {
 	ContractEvents        string
 	OwnerRedirects        string
+	PerformanceGaps       string
+	QuarantinedDays       string
 	State                 string
 	ValidatorEvents       string
 	ValidatorPerformances string
@@ -14,6 +16,8 @@ var TableNames = struct {
 }{
 	ContractEvents:        "contract_events",
 	OwnerRedirects:        "owner_redirects",
+	PerformanceGaps:       "performance_gaps",
+	QuarantinedDays:       "quarantined_days",
 	State:                 "state",
 	ValidatorEvents:       "validator_events",
 	ValidatorPerformances: "validator_performances",
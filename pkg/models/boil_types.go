@@ -4,9 +4,14 @@
 package models
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
 	"strconv"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/null/v8/convert"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/strmangle"
 )
@@ -55,20 +60,24 @@ type ProviderType string
 
 // Enum values for ProviderType
 const (
-	ProviderTypeE2m       ProviderType = "e2m"
-	ProviderTypeBeaconcha ProviderType = "beaconcha"
+	ProviderTypeE2m        ProviderType = "e2m"
+	ProviderTypeBeaconcha  ProviderType = "beaconcha"
+	ProviderTypeBeaconnode ProviderType = "beaconnode"
+	ProviderTypeFile       ProviderType = "file"
 )
 
 func AllProviderType() []ProviderType {
 	return []ProviderType{
 		ProviderTypeE2m,
 		ProviderTypeBeaconcha,
+		ProviderTypeBeaconnode,
+		ProviderTypeFile,
 	}
 }
 
 func (e ProviderType) IsValid() error {
 	switch e {
-	case ProviderTypeE2m, ProviderTypeBeaconcha:
+	case ProviderTypeE2m, ProviderTypeBeaconcha, ProviderTypeBeaconnode, ProviderTypeFile:
 		return nil
 	default:
 		return errors.New("enum is not valid")
@@ -85,8 +94,272 @@ func (e ProviderType) Ordinal() int {
 		return 0
 	case ProviderTypeBeaconcha:
 		return 1
+	case ProviderTypeBeaconnode:
+		return 2
+	case ProviderTypeFile:
+		return 3
 
 	default:
 		panic(errors.New("enum is not valid"))
 	}
 }
+
+type DecidedsSource string
+
+// Enum values for DecidedsSource
+const (
+	DecidedsSourceSSVAPI   DecidedsSource = "ssv_api"
+	DecidedsSourceExporter DecidedsSource = "exporter"
+	DecidedsSourceFile     DecidedsSource = "file"
+)
+
+func AllDecidedsSource() []DecidedsSource {
+	return []DecidedsSource{
+		DecidedsSourceSSVAPI,
+		DecidedsSourceExporter,
+		DecidedsSourceFile,
+	}
+}
+
+func (e DecidedsSource) IsValid() error {
+	switch e {
+	case DecidedsSourceSSVAPI, DecidedsSourceExporter, DecidedsSourceFile:
+		return nil
+	default:
+		return errors.New("enum is not valid")
+	}
+}
+
+func (e DecidedsSource) String() string {
+	return string(e)
+}
+
+func (e DecidedsSource) Ordinal() int {
+	switch e {
+	case DecidedsSourceSSVAPI:
+		return 0
+	case DecidedsSourceExporter:
+		return 1
+	case DecidedsSourceFile:
+		return 2
+
+	default:
+		panic(errors.New("enum is not valid"))
+	}
+}
+
+// NullProviderType is a nullable ProviderType enum type. It supports SQL and JSON serialization.
+type NullProviderType struct {
+	Val   ProviderType
+	Valid bool
+}
+
+// NullProviderTypeFrom creates a new ProviderType that will never be blank.
+func NullProviderTypeFrom(v ProviderType) NullProviderType {
+	return NewNullProviderType(v, true)
+}
+
+// NullProviderTypeFromPtr creates a new NullProviderType that be null if s is nil.
+func NullProviderTypeFromPtr(v *ProviderType) NullProviderType {
+	if v == nil {
+		return NewNullProviderType("", false)
+	}
+	return NewNullProviderType(*v, true)
+}
+
+// NewNullProviderType creates a new NullProviderType
+func NewNullProviderType(v ProviderType, valid bool) NullProviderType {
+	return NullProviderType{
+		Val:   v,
+		Valid: valid,
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *NullProviderType) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null.NullBytes) {
+		e.Val = ""
+		e.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &e.Val); err != nil {
+		return err
+	}
+
+	e.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e NullProviderType) MarshalJSON() ([]byte, error) {
+	if !e.Valid {
+		return null.NullBytes, nil
+	}
+	return json.Marshal(e.Val)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e NullProviderType) MarshalText() ([]byte, error) {
+	if !e.Valid {
+		return []byte{}, nil
+	}
+	return []byte(e.Val), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *NullProviderType) UnmarshalText(text []byte) error {
+	if text == nil || len(text) == 0 {
+		e.Valid = false
+		return nil
+	}
+
+	e.Val = ProviderType(text)
+	e.Valid = true
+	return nil
+}
+
+// SetValid changes this NullProviderType value and also sets it to be non-null.
+func (e *NullProviderType) SetValid(v ProviderType) {
+	e.Val = v
+	e.Valid = true
+}
+
+// Ptr returns a pointer to this NullProviderType value, or a nil pointer if this NullProviderType is null.
+func (e NullProviderType) Ptr() *ProviderType {
+	if !e.Valid {
+		return nil
+	}
+	return &e.Val
+}
+
+// IsZero returns true for null types.
+func (e NullProviderType) IsZero() bool {
+	return !e.Valid
+}
+
+// Scan implements the Scanner interface.
+func (e *NullProviderType) Scan(value interface{}) error {
+	if value == nil {
+		e.Val, e.Valid = "", false
+		return nil
+	}
+	e.Valid = true
+	return convert.ConvertAssign((*string)(&e.Val), value)
+}
+
+// Value implements the driver Valuer interface.
+func (e NullProviderType) Value() (driver.Value, error) {
+	if !e.Valid {
+		return nil, nil
+	}
+	return string(e.Val), nil
+}
+
+// NullDecidedsSource is a nullable DecidedsSource enum type. It supports SQL and JSON serialization.
+type NullDecidedsSource struct {
+	Val   DecidedsSource
+	Valid bool
+}
+
+// NullDecidedsSourceFrom creates a new DecidedsSource that will never be blank.
+func NullDecidedsSourceFrom(v DecidedsSource) NullDecidedsSource {
+	return NewNullDecidedsSource(v, true)
+}
+
+// NullDecidedsSourceFromPtr creates a new NullDecidedsSource that be null if s is nil.
+func NullDecidedsSourceFromPtr(v *DecidedsSource) NullDecidedsSource {
+	if v == nil {
+		return NewNullDecidedsSource("", false)
+	}
+	return NewNullDecidedsSource(*v, true)
+}
+
+// NewNullDecidedsSource creates a new NullDecidedsSource
+func NewNullDecidedsSource(v DecidedsSource, valid bool) NullDecidedsSource {
+	return NullDecidedsSource{
+		Val:   v,
+		Valid: valid,
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *NullDecidedsSource) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null.NullBytes) {
+		e.Val = ""
+		e.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &e.Val); err != nil {
+		return err
+	}
+
+	e.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e NullDecidedsSource) MarshalJSON() ([]byte, error) {
+	if !e.Valid {
+		return null.NullBytes, nil
+	}
+	return json.Marshal(e.Val)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e NullDecidedsSource) MarshalText() ([]byte, error) {
+	if !e.Valid {
+		return []byte{}, nil
+	}
+	return []byte(e.Val), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *NullDecidedsSource) UnmarshalText(text []byte) error {
+	if text == nil || len(text) == 0 {
+		e.Valid = false
+		return nil
+	}
+
+	e.Val = DecidedsSource(text)
+	e.Valid = true
+	return nil
+}
+
+// SetValid changes this NullDecidedsSource value and also sets it to be non-null.
+func (e *NullDecidedsSource) SetValid(v DecidedsSource) {
+	e.Val = v
+	e.Valid = true
+}
+
+// Ptr returns a pointer to this NullDecidedsSource value, or a nil pointer if this NullDecidedsSource is null.
+func (e NullDecidedsSource) Ptr() *DecidedsSource {
+	if !e.Valid {
+		return nil
+	}
+	return &e.Val
+}
+
+// IsZero returns true for null types.
+func (e NullDecidedsSource) IsZero() bool {
+	return !e.Valid
+}
+
+// Scan implements the Scanner interface.
+func (e *NullDecidedsSource) Scan(value interface{}) error {
+	if value == nil {
+		e.Val, e.Valid = "", false
+		return nil
+	}
+	e.Valid = true
+	return convert.ConvertAssign((*string)(&e.Val), value)
+}
+
+// Value implements the driver Valuer interface.
+func (e NullDecidedsSource) Value() (driver.Value, error) {
+	if !e.Valid {
+		return nil, nil
+	}
+	return string(e.Val), nil
+}
@@ -4,4 +4,7 @@
 package models
 
 var ViewNames = struct {
-}{}
+	ValidatorPerformanceSources string
+}{
+	ValidatorPerformanceSources: "validator_performance_sources",
+}
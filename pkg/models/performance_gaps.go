@@ -0,0 +1,1212 @@
+// Code generated by SQLBoiler 4.16.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// PerformanceGap is an object representing the database table.
+type PerformanceGap struct {
+	Provider   ProviderType `boil:"provider" json:"provider" toml:"provider" yaml:"provider"`
+	Day        time.Time    `boil:"day" json:"day" toml:"day" yaml:"day"`
+	PublicKey  string       `boil:"public_key" json:"public_key" toml:"public_key" yaml:"public_key"`
+	Index      int          `boil:"index" json:"index" toml:"index" yaml:"index"`
+	Reason     string       `boil:"reason" json:"reason" toml:"reason" yaml:"reason"`
+	DetectedAt time.Time    `boil:"detected_at" json:"detected_at" toml:"detected_at" yaml:"detected_at"`
+	ResolvedAt null.Time    `boil:"resolved_at" json:"resolved_at,omitempty" toml:"resolved_at" yaml:"resolved_at,omitempty"`
+
+	R *performanceGapR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L performanceGapL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var PerformanceGapColumns = struct {
+	Provider   string
+	Day        string
+	PublicKey  string
+	Index      string
+	Reason     string
+	DetectedAt string
+	ResolvedAt string
+}{
+	Provider:   "provider",
+	Day:        "day",
+	PublicKey:  "public_key",
+	Index:      "index",
+	Reason:     "reason",
+	DetectedAt: "detected_at",
+	ResolvedAt: "resolved_at",
+}
+
+var PerformanceGapTableColumns = struct {
+	Provider   string
+	Day        string
+	PublicKey  string
+	Index      string
+	Reason     string
+	DetectedAt string
+	ResolvedAt string
+}{
+	Provider:   "performance_gaps.provider",
+	Day:        "performance_gaps.day",
+	PublicKey:  "performance_gaps.public_key",
+	Index:      "performance_gaps.index",
+	Reason:     "performance_gaps.reason",
+	DetectedAt: "performance_gaps.detected_at",
+	ResolvedAt: "performance_gaps.resolved_at",
+}
+
+// Generated where
+
+type whereHelperProviderType struct{ field string }
+
+func (w whereHelperProviderType) EQ(x ProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperProviderType) NEQ(x ProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperProviderType) LT(x ProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperProviderType) LTE(x ProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperProviderType) GT(x ProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperProviderType) GTE(x ProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperProviderType) IN(slice []ProviderType) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperProviderType) NIN(slice []ProviderType) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+type whereHelpernull_Time struct{ field string }
+
+func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Time) NEQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Time) LT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Time) LTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Time) GT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+var PerformanceGapWhere = struct {
+	Provider   whereHelperProviderType
+	Day        whereHelpertime_Time
+	PublicKey  whereHelperstring
+	Index      whereHelperint
+	Reason     whereHelperstring
+	DetectedAt whereHelpertime_Time
+	ResolvedAt whereHelpernull_Time
+}{
+	Provider:   whereHelperProviderType{field: "\"performance_gaps\".\"provider\""},
+	Day:        whereHelpertime_Time{field: "\"performance_gaps\".\"day\""},
+	PublicKey:  whereHelperstring{field: "\"performance_gaps\".\"public_key\""},
+	Index:      whereHelperint{field: "\"performance_gaps\".\"index\""},
+	Reason:     whereHelperstring{field: "\"performance_gaps\".\"reason\""},
+	DetectedAt: whereHelpertime_Time{field: "\"performance_gaps\".\"detected_at\""},
+	ResolvedAt: whereHelpernull_Time{field: "\"performance_gaps\".\"resolved_at\""},
+}
+
+// PerformanceGapRels is where relationship names are stored.
+var PerformanceGapRels = struct {
+	PublicKeyValidator string
+}{
+	PublicKeyValidator: "PublicKeyValidator",
+}
+
+// performanceGapR is where relationships are stored.
+type performanceGapR struct {
+	PublicKeyValidator *Validator `boil:"PublicKeyValidator" json:"PublicKeyValidator" toml:"PublicKeyValidator" yaml:"PublicKeyValidator"`
+}
+
+// NewStruct creates a new relationship struct
+func (*performanceGapR) NewStruct() *performanceGapR {
+	return &performanceGapR{}
+}
+
+func (r *performanceGapR) GetPublicKeyValidator() *Validator {
+	if r == nil {
+		return nil
+	}
+	return r.PublicKeyValidator
+}
+
+// performanceGapL is where Load methods for each relationship are stored.
+type performanceGapL struct{}
+
+var (
+	performanceGapAllColumns            = []string{"provider", "day", "public_key", "index", "reason", "detected_at", "resolved_at"}
+	performanceGapColumnsWithoutDefault = []string{"provider", "day", "public_key", "index", "reason"}
+	performanceGapColumnsWithDefault    = []string{"detected_at", "resolved_at"}
+	performanceGapPrimaryKeyColumns     = []string{"provider", "day", "public_key"}
+	performanceGapGeneratedColumns      = []string{}
+)
+
+type (
+	// PerformanceGapSlice is an alias for a slice of pointers to PerformanceGap.
+	// This should almost always be used instead of []PerformanceGap.
+	PerformanceGapSlice []*PerformanceGap
+	// PerformanceGapHook is the signature for custom PerformanceGap hook methods
+	PerformanceGapHook func(context.Context, boil.ContextExecutor, *PerformanceGap) error
+
+	performanceGapQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	performanceGapType                 = reflect.TypeOf(&PerformanceGap{})
+	performanceGapMapping              = queries.MakeStructMapping(performanceGapType)
+	performanceGapPrimaryKeyMapping, _ = queries.BindMapping(performanceGapType, performanceGapMapping, performanceGapPrimaryKeyColumns)
+	performanceGapInsertCacheMut       sync.RWMutex
+	performanceGapInsertCache          = make(map[string]insertCache)
+	performanceGapUpdateCacheMut       sync.RWMutex
+	performanceGapUpdateCache          = make(map[string]updateCache)
+	performanceGapUpsertCacheMut       sync.RWMutex
+	performanceGapUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var performanceGapAfterSelectMu sync.Mutex
+var performanceGapAfterSelectHooks []PerformanceGapHook
+
+var performanceGapBeforeInsertMu sync.Mutex
+var performanceGapBeforeInsertHooks []PerformanceGapHook
+var performanceGapAfterInsertMu sync.Mutex
+var performanceGapAfterInsertHooks []PerformanceGapHook
+
+var performanceGapBeforeUpdateMu sync.Mutex
+var performanceGapBeforeUpdateHooks []PerformanceGapHook
+var performanceGapAfterUpdateMu sync.Mutex
+var performanceGapAfterUpdateHooks []PerformanceGapHook
+
+var performanceGapBeforeDeleteMu sync.Mutex
+var performanceGapBeforeDeleteHooks []PerformanceGapHook
+var performanceGapAfterDeleteMu sync.Mutex
+var performanceGapAfterDeleteHooks []PerformanceGapHook
+
+var performanceGapBeforeUpsertMu sync.Mutex
+var performanceGapBeforeUpsertHooks []PerformanceGapHook
+var performanceGapAfterUpsertMu sync.Mutex
+var performanceGapAfterUpsertHooks []PerformanceGapHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *PerformanceGap) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *PerformanceGap) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *PerformanceGap) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *PerformanceGap) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *PerformanceGap) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *PerformanceGap) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *PerformanceGap) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *PerformanceGap) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *PerformanceGap) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range performanceGapAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddPerformanceGapHook registers your hook function for all future operations.
+func AddPerformanceGapHook(hookPoint boil.HookPoint, performanceGapHook PerformanceGapHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		performanceGapAfterSelectMu.Lock()
+		performanceGapAfterSelectHooks = append(performanceGapAfterSelectHooks, performanceGapHook)
+		performanceGapAfterSelectMu.Unlock()
+	case boil.BeforeInsertHook:
+		performanceGapBeforeInsertMu.Lock()
+		performanceGapBeforeInsertHooks = append(performanceGapBeforeInsertHooks, performanceGapHook)
+		performanceGapBeforeInsertMu.Unlock()
+	case boil.AfterInsertHook:
+		performanceGapAfterInsertMu.Lock()
+		performanceGapAfterInsertHooks = append(performanceGapAfterInsertHooks, performanceGapHook)
+		performanceGapAfterInsertMu.Unlock()
+	case boil.BeforeUpdateHook:
+		performanceGapBeforeUpdateMu.Lock()
+		performanceGapBeforeUpdateHooks = append(performanceGapBeforeUpdateHooks, performanceGapHook)
+		performanceGapBeforeUpdateMu.Unlock()
+	case boil.AfterUpdateHook:
+		performanceGapAfterUpdateMu.Lock()
+		performanceGapAfterUpdateHooks = append(performanceGapAfterUpdateHooks, performanceGapHook)
+		performanceGapAfterUpdateMu.Unlock()
+	case boil.BeforeDeleteHook:
+		performanceGapBeforeDeleteMu.Lock()
+		performanceGapBeforeDeleteHooks = append(performanceGapBeforeDeleteHooks, performanceGapHook)
+		performanceGapBeforeDeleteMu.Unlock()
+	case boil.AfterDeleteHook:
+		performanceGapAfterDeleteMu.Lock()
+		performanceGapAfterDeleteHooks = append(performanceGapAfterDeleteHooks, performanceGapHook)
+		performanceGapAfterDeleteMu.Unlock()
+	case boil.BeforeUpsertHook:
+		performanceGapBeforeUpsertMu.Lock()
+		performanceGapBeforeUpsertHooks = append(performanceGapBeforeUpsertHooks, performanceGapHook)
+		performanceGapBeforeUpsertMu.Unlock()
+	case boil.AfterUpsertHook:
+		performanceGapAfterUpsertMu.Lock()
+		performanceGapAfterUpsertHooks = append(performanceGapAfterUpsertHooks, performanceGapHook)
+		performanceGapAfterUpsertMu.Unlock()
+	}
+}
+
+// One returns a single performanceGap record from the query.
+func (q performanceGapQuery) One(ctx context.Context, exec boil.ContextExecutor) (*PerformanceGap, error) {
+	o := &PerformanceGap{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for performance_gaps")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all PerformanceGap records from the query.
+func (q performanceGapQuery) All(ctx context.Context, exec boil.ContextExecutor) (PerformanceGapSlice, error) {
+	var o []*PerformanceGap
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to PerformanceGap slice")
+	}
+
+	if len(performanceGapAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all PerformanceGap records in the query.
+func (q performanceGapQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count performance_gaps rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q performanceGapQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if performance_gaps exists")
+	}
+
+	return count > 0, nil
+}
+
+// PublicKeyValidator pointed to by the foreign key.
+func (o *PerformanceGap) PublicKeyValidator(mods ...qm.QueryMod) validatorQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"public_key\" = ?", o.PublicKey),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Validators(queryMods...)
+}
+
+// LoadPublicKeyValidator allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (performanceGapL) LoadPublicKeyValidator(ctx context.Context, e boil.ContextExecutor, singular bool, maybePerformanceGap interface{}, mods queries.Applicator) error {
+	var slice []*PerformanceGap
+	var object *PerformanceGap
+
+	if singular {
+		var ok bool
+		object, ok = maybePerformanceGap.(*PerformanceGap)
+		if !ok {
+			object = new(PerformanceGap)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybePerformanceGap)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybePerformanceGap))
+			}
+		}
+	} else {
+		s, ok := maybePerformanceGap.(*[]*PerformanceGap)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybePerformanceGap)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybePerformanceGap))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &performanceGapR{}
+		}
+		args[object.PublicKey] = struct{}{}
+
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &performanceGapR{}
+			}
+
+			args[obj.PublicKey] = struct{}{}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`validators`),
+		qm.WhereIn(`validators.public_key in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Validator")
+	}
+
+	var resultSlice []*Validator
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Validator")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for validators")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for validators")
+	}
+
+	if len(validatorAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.PublicKeyValidator = foreign
+		if foreign.R == nil {
+			foreign.R = &validatorR{}
+		}
+		foreign.R.PublicKeyPerformanceGaps = append(foreign.R.PublicKeyPerformanceGaps, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.PublicKey == foreign.PublicKey {
+				local.R.PublicKeyValidator = foreign
+				if foreign.R == nil {
+					foreign.R = &validatorR{}
+				}
+				foreign.R.PublicKeyPerformanceGaps = append(foreign.R.PublicKeyPerformanceGaps, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetPublicKeyValidator of the performanceGap to the related item.
+// Sets o.R.PublicKeyValidator to related.
+// Adds o to related.R.PublicKeyPerformanceGaps.
+func (o *PerformanceGap) SetPublicKeyValidator(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Validator) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"performance_gaps\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"public_key"}),
+		strmangle.WhereClause("\"", "\"", 2, performanceGapPrimaryKeyColumns),
+	)
+	values := []interface{}{related.PublicKey, o.Provider, o.Day, o.PublicKey}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.PublicKey = related.PublicKey
+	if o.R == nil {
+		o.R = &performanceGapR{
+			PublicKeyValidator: related,
+		}
+	} else {
+		o.R.PublicKeyValidator = related
+	}
+
+	if related.R == nil {
+		related.R = &validatorR{
+			PublicKeyPerformanceGaps: PerformanceGapSlice{o},
+		}
+	} else {
+		related.R.PublicKeyPerformanceGaps = append(related.R.PublicKeyPerformanceGaps, o)
+	}
+
+	return nil
+}
+
+// PerformanceGaps retrieves all the records using an executor.
+func PerformanceGaps(mods ...qm.QueryMod) performanceGapQuery {
+	mods = append(mods, qm.From("\"performance_gaps\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"performance_gaps\".*"})
+	}
+
+	return performanceGapQuery{q}
+}
+
+// FindPerformanceGap retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindPerformanceGap(ctx context.Context, exec boil.ContextExecutor, provider ProviderType, day time.Time, publicKey string, selectCols ...string) (*PerformanceGap, error) {
+	performanceGapObj := &PerformanceGap{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"performance_gaps\" where \"provider\"=$1 AND \"day\"=$2 AND \"public_key\"=$3", sel,
+	)
+
+	q := queries.Raw(query, provider, day, publicKey)
+
+	err := q.Bind(ctx, exec, performanceGapObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from performance_gaps")
+	}
+
+	if err = performanceGapObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return performanceGapObj, err
+	}
+
+	return performanceGapObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *PerformanceGap) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no performance_gaps provided for insertion")
+	}
+
+	var err error
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(performanceGapColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	performanceGapInsertCacheMut.RLock()
+	cache, cached := performanceGapInsertCache[key]
+	performanceGapInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			performanceGapAllColumns,
+			performanceGapColumnsWithDefault,
+			performanceGapColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(performanceGapType, performanceGapMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(performanceGapType, performanceGapMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"performance_gaps\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"performance_gaps\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into performance_gaps")
+	}
+
+	if !cached {
+		performanceGapInsertCacheMut.Lock()
+		performanceGapInsertCache[key] = cache
+		performanceGapInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the PerformanceGap.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *PerformanceGap) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	performanceGapUpdateCacheMut.RLock()
+	cache, cached := performanceGapUpdateCache[key]
+	performanceGapUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			performanceGapAllColumns,
+			performanceGapPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update performance_gaps, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"performance_gaps\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, performanceGapPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(performanceGapType, performanceGapMapping, append(wl, performanceGapPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update performance_gaps row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for performance_gaps")
+	}
+
+	if !cached {
+		performanceGapUpdateCacheMut.Lock()
+		performanceGapUpdateCache[key] = cache
+		performanceGapUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q performanceGapQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for performance_gaps")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for performance_gaps")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o PerformanceGapSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), performanceGapPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"performance_gaps\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, performanceGapPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in performanceGap slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all performanceGap")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *PerformanceGap) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns, opts ...UpsertOptionFunc) error {
+	if o == nil {
+		return errors.New("models: no performance_gaps provided for upsert")
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(performanceGapColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	performanceGapUpsertCacheMut.RLock()
+	cache, cached := performanceGapUpsertCache[key]
+	performanceGapUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, _ := insertColumns.InsertColumnSet(
+			performanceGapAllColumns,
+			performanceGapColumnsWithDefault,
+			performanceGapColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			performanceGapAllColumns,
+			performanceGapPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert performance_gaps, could not build update column list")
+		}
+
+		ret := strmangle.SetComplement(performanceGapAllColumns, strmangle.SetIntersect(insert, update))
+
+		conflict := conflictColumns
+		if len(conflict) == 0 && updateOnConflict && len(update) != 0 {
+			if len(performanceGapPrimaryKeyColumns) == 0 {
+				return errors.New("models: unable to upsert performance_gaps, could not build conflict column list")
+			}
+
+			conflict = make([]string, len(performanceGapPrimaryKeyColumns))
+			copy(conflict, performanceGapPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"performance_gaps\"", updateOnConflict, ret, update, conflict, insert, opts...)
+
+		cache.valueMapping, err = queries.BindMapping(performanceGapType, performanceGapMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(performanceGapType, performanceGapMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert performance_gaps")
+	}
+
+	if !cached {
+		performanceGapUpsertCacheMut.Lock()
+		performanceGapUpsertCache[key] = cache
+		performanceGapUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single PerformanceGap record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *PerformanceGap) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no PerformanceGap provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), performanceGapPrimaryKeyMapping)
+	sql := "DELETE FROM \"performance_gaps\" WHERE \"provider\"=$1 AND \"day\"=$2 AND \"public_key\"=$3"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from performance_gaps")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for performance_gaps")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q performanceGapQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no performanceGapQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from performance_gaps")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for performance_gaps")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o PerformanceGapSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(performanceGapBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), performanceGapPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"performance_gaps\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, performanceGapPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from performanceGap slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for performance_gaps")
+	}
+
+	if len(performanceGapAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *PerformanceGap) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindPerformanceGap(ctx, exec, o.Provider, o.Day, o.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *PerformanceGapSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := PerformanceGapSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), performanceGapPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"performance_gaps\".* FROM \"performance_gaps\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, performanceGapPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in PerformanceGapSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// PerformanceGapExists checks if the PerformanceGap row exists.
+func PerformanceGapExists(ctx context.Context, exec boil.ContextExecutor, provider ProviderType, day time.Time, publicKey string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"performance_gaps\" where \"provider\"=$1 AND \"day\"=$2 AND \"public_key\"=$3 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, provider, day, publicKey)
+	}
+	row := exec.QueryRowContext(ctx, sql, provider, day, publicKey)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if performance_gaps exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the PerformanceGap row exists.
+func (o *PerformanceGap) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return PerformanceGapExists(ctx, exec, o.Provider, o.Day, o.PublicKey)
+}
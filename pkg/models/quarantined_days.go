@@ -0,0 +1,984 @@
+// Code generated by SQLBoiler 4.16.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// QuarantinedDay is an object representing the database table.
+type QuarantinedDay struct {
+	Provider       ProviderType   `boil:"provider" json:"provider" toml:"provider" yaml:"provider"`
+	Day            time.Time      `boil:"day" json:"day" toml:"day" yaml:"day"`
+	DecidedsSource DecidedsSource `boil:"decideds_source" json:"decideds_source" toml:"decideds_source" yaml:"decideds_source"`
+	Problems       string         `boil:"problems" json:"problems" toml:"problems" yaml:"problems"`
+	QuarantinedAt  time.Time      `boil:"quarantined_at" json:"quarantined_at" toml:"quarantined_at" yaml:"quarantined_at"`
+
+	R *quarantinedDayR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L quarantinedDayL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var QuarantinedDayColumns = struct {
+	Provider       string
+	Day            string
+	DecidedsSource string
+	Problems       string
+	QuarantinedAt  string
+}{
+	Provider:       "provider",
+	Day:            "day",
+	DecidedsSource: "decideds_source",
+	Problems:       "problems",
+	QuarantinedAt:  "quarantined_at",
+}
+
+var QuarantinedDayTableColumns = struct {
+	Provider       string
+	Day            string
+	DecidedsSource string
+	Problems       string
+	QuarantinedAt  string
+}{
+	Provider:       "quarantined_days.provider",
+	Day:            "quarantined_days.day",
+	DecidedsSource: "quarantined_days.decideds_source",
+	Problems:       "quarantined_days.problems",
+	QuarantinedAt:  "quarantined_days.quarantined_at",
+}
+
+// Generated where
+
+type whereHelperDecidedsSource struct{ field string }
+
+func (w whereHelperDecidedsSource) EQ(x DecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperDecidedsSource) NEQ(x DecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperDecidedsSource) LT(x DecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperDecidedsSource) LTE(x DecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperDecidedsSource) GT(x DecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperDecidedsSource) GTE(x DecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperDecidedsSource) IN(slice []DecidedsSource) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperDecidedsSource) NIN(slice []DecidedsSource) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+var QuarantinedDayWhere = struct {
+	Provider       whereHelperProviderType
+	Day            whereHelpertime_Time
+	DecidedsSource whereHelperDecidedsSource
+	Problems       whereHelperstring
+	QuarantinedAt  whereHelpertime_Time
+}{
+	Provider:       whereHelperProviderType{field: "\"quarantined_days\".\"provider\""},
+	Day:            whereHelpertime_Time{field: "\"quarantined_days\".\"day\""},
+	DecidedsSource: whereHelperDecidedsSource{field: "\"quarantined_days\".\"decideds_source\""},
+	Problems:       whereHelperstring{field: "\"quarantined_days\".\"problems\""},
+	QuarantinedAt:  whereHelpertime_Time{field: "\"quarantined_days\".\"quarantined_at\""},
+}
+
+// QuarantinedDayRels is where relationship names are stored.
+var QuarantinedDayRels = struct {
+}{}
+
+// quarantinedDayR is where relationships are stored.
+type quarantinedDayR struct {
+}
+
+// NewStruct creates a new relationship struct
+func (*quarantinedDayR) NewStruct() *quarantinedDayR {
+	return &quarantinedDayR{}
+}
+
+// quarantinedDayL is where Load methods for each relationship are stored.
+type quarantinedDayL struct{}
+
+var (
+	quarantinedDayAllColumns            = []string{"provider", "day", "decideds_source", "problems", "quarantined_at"}
+	quarantinedDayColumnsWithoutDefault = []string{"provider", "day", "decideds_source", "problems"}
+	quarantinedDayColumnsWithDefault    = []string{"quarantined_at"}
+	quarantinedDayPrimaryKeyColumns     = []string{"provider", "day"}
+	quarantinedDayGeneratedColumns      = []string{}
+)
+
+type (
+	// QuarantinedDaySlice is an alias for a slice of pointers to QuarantinedDay.
+	// This should almost always be used instead of []QuarantinedDay.
+	QuarantinedDaySlice []*QuarantinedDay
+	// QuarantinedDayHook is the signature for custom QuarantinedDay hook methods
+	QuarantinedDayHook func(context.Context, boil.ContextExecutor, *QuarantinedDay) error
+
+	quarantinedDayQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	quarantinedDayType                 = reflect.TypeOf(&QuarantinedDay{})
+	quarantinedDayMapping              = queries.MakeStructMapping(quarantinedDayType)
+	quarantinedDayPrimaryKeyMapping, _ = queries.BindMapping(quarantinedDayType, quarantinedDayMapping, quarantinedDayPrimaryKeyColumns)
+	quarantinedDayInsertCacheMut       sync.RWMutex
+	quarantinedDayInsertCache          = make(map[string]insertCache)
+	quarantinedDayUpdateCacheMut       sync.RWMutex
+	quarantinedDayUpdateCache          = make(map[string]updateCache)
+	quarantinedDayUpsertCacheMut       sync.RWMutex
+	quarantinedDayUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var quarantinedDayAfterSelectMu sync.Mutex
+var quarantinedDayAfterSelectHooks []QuarantinedDayHook
+
+var quarantinedDayBeforeInsertMu sync.Mutex
+var quarantinedDayBeforeInsertHooks []QuarantinedDayHook
+var quarantinedDayAfterInsertMu sync.Mutex
+var quarantinedDayAfterInsertHooks []QuarantinedDayHook
+
+var quarantinedDayBeforeUpdateMu sync.Mutex
+var quarantinedDayBeforeUpdateHooks []QuarantinedDayHook
+var quarantinedDayAfterUpdateMu sync.Mutex
+var quarantinedDayAfterUpdateHooks []QuarantinedDayHook
+
+var quarantinedDayBeforeDeleteMu sync.Mutex
+var quarantinedDayBeforeDeleteHooks []QuarantinedDayHook
+var quarantinedDayAfterDeleteMu sync.Mutex
+var quarantinedDayAfterDeleteHooks []QuarantinedDayHook
+
+var quarantinedDayBeforeUpsertMu sync.Mutex
+var quarantinedDayBeforeUpsertHooks []QuarantinedDayHook
+var quarantinedDayAfterUpsertMu sync.Mutex
+var quarantinedDayAfterUpsertHooks []QuarantinedDayHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *QuarantinedDay) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *QuarantinedDay) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *QuarantinedDay) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *QuarantinedDay) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *QuarantinedDay) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *QuarantinedDay) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *QuarantinedDay) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *QuarantinedDay) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *QuarantinedDay) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range quarantinedDayAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddQuarantinedDayHook registers your hook function for all future operations.
+func AddQuarantinedDayHook(hookPoint boil.HookPoint, quarantinedDayHook QuarantinedDayHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		quarantinedDayAfterSelectMu.Lock()
+		quarantinedDayAfterSelectHooks = append(quarantinedDayAfterSelectHooks, quarantinedDayHook)
+		quarantinedDayAfterSelectMu.Unlock()
+	case boil.BeforeInsertHook:
+		quarantinedDayBeforeInsertMu.Lock()
+		quarantinedDayBeforeInsertHooks = append(quarantinedDayBeforeInsertHooks, quarantinedDayHook)
+		quarantinedDayBeforeInsertMu.Unlock()
+	case boil.AfterInsertHook:
+		quarantinedDayAfterInsertMu.Lock()
+		quarantinedDayAfterInsertHooks = append(quarantinedDayAfterInsertHooks, quarantinedDayHook)
+		quarantinedDayAfterInsertMu.Unlock()
+	case boil.BeforeUpdateHook:
+		quarantinedDayBeforeUpdateMu.Lock()
+		quarantinedDayBeforeUpdateHooks = append(quarantinedDayBeforeUpdateHooks, quarantinedDayHook)
+		quarantinedDayBeforeUpdateMu.Unlock()
+	case boil.AfterUpdateHook:
+		quarantinedDayAfterUpdateMu.Lock()
+		quarantinedDayAfterUpdateHooks = append(quarantinedDayAfterUpdateHooks, quarantinedDayHook)
+		quarantinedDayAfterUpdateMu.Unlock()
+	case boil.BeforeDeleteHook:
+		quarantinedDayBeforeDeleteMu.Lock()
+		quarantinedDayBeforeDeleteHooks = append(quarantinedDayBeforeDeleteHooks, quarantinedDayHook)
+		quarantinedDayBeforeDeleteMu.Unlock()
+	case boil.AfterDeleteHook:
+		quarantinedDayAfterDeleteMu.Lock()
+		quarantinedDayAfterDeleteHooks = append(quarantinedDayAfterDeleteHooks, quarantinedDayHook)
+		quarantinedDayAfterDeleteMu.Unlock()
+	case boil.BeforeUpsertHook:
+		quarantinedDayBeforeUpsertMu.Lock()
+		quarantinedDayBeforeUpsertHooks = append(quarantinedDayBeforeUpsertHooks, quarantinedDayHook)
+		quarantinedDayBeforeUpsertMu.Unlock()
+	case boil.AfterUpsertHook:
+		quarantinedDayAfterUpsertMu.Lock()
+		quarantinedDayAfterUpsertHooks = append(quarantinedDayAfterUpsertHooks, quarantinedDayHook)
+		quarantinedDayAfterUpsertMu.Unlock()
+	}
+}
+
+// One returns a single quarantinedDay record from the query.
+func (q quarantinedDayQuery) One(ctx context.Context, exec boil.ContextExecutor) (*QuarantinedDay, error) {
+	o := &QuarantinedDay{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for quarantined_days")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all QuarantinedDay records from the query.
+func (q quarantinedDayQuery) All(ctx context.Context, exec boil.ContextExecutor) (QuarantinedDaySlice, error) {
+	var o []*QuarantinedDay
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to QuarantinedDay slice")
+	}
+
+	if len(quarantinedDayAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all QuarantinedDay records in the query.
+func (q quarantinedDayQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count quarantined_days rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q quarantinedDayQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if quarantined_days exists")
+	}
+
+	return count > 0, nil
+}
+
+// QuarantinedDays retrieves all the records using an executor.
+func QuarantinedDays(mods ...qm.QueryMod) quarantinedDayQuery {
+	mods = append(mods, qm.From("\"quarantined_days\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"quarantined_days\".*"})
+	}
+
+	return quarantinedDayQuery{q}
+}
+
+// FindQuarantinedDay retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindQuarantinedDay(ctx context.Context, exec boil.ContextExecutor, provider ProviderType, day time.Time, selectCols ...string) (*QuarantinedDay, error) {
+	quarantinedDayObj := &QuarantinedDay{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"quarantined_days\" where \"provider\"=$1 AND \"day\"=$2", sel,
+	)
+
+	q := queries.Raw(query, provider, day)
+
+	err := q.Bind(ctx, exec, quarantinedDayObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from quarantined_days")
+	}
+
+	if err = quarantinedDayObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return quarantinedDayObj, err
+	}
+
+	return quarantinedDayObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *QuarantinedDay) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no quarantined_days provided for insertion")
+	}
+
+	var err error
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(quarantinedDayColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	quarantinedDayInsertCacheMut.RLock()
+	cache, cached := quarantinedDayInsertCache[key]
+	quarantinedDayInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			quarantinedDayAllColumns,
+			quarantinedDayColumnsWithDefault,
+			quarantinedDayColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(quarantinedDayType, quarantinedDayMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(quarantinedDayType, quarantinedDayMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"quarantined_days\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"quarantined_days\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into quarantined_days")
+	}
+
+	if !cached {
+		quarantinedDayInsertCacheMut.Lock()
+		quarantinedDayInsertCache[key] = cache
+		quarantinedDayInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the QuarantinedDay.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *QuarantinedDay) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	quarantinedDayUpdateCacheMut.RLock()
+	cache, cached := quarantinedDayUpdateCache[key]
+	quarantinedDayUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			quarantinedDayAllColumns,
+			quarantinedDayPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update quarantined_days, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"quarantined_days\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, quarantinedDayPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(quarantinedDayType, quarantinedDayMapping, append(wl, quarantinedDayPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update quarantined_days row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for quarantined_days")
+	}
+
+	if !cached {
+		quarantinedDayUpdateCacheMut.Lock()
+		quarantinedDayUpdateCache[key] = cache
+		quarantinedDayUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q quarantinedDayQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for quarantined_days")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for quarantined_days")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o QuarantinedDaySlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), quarantinedDayPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"quarantined_days\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, quarantinedDayPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in quarantinedDay slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all quarantinedDay")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *QuarantinedDay) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns, opts ...UpsertOptionFunc) error {
+	if o == nil {
+		return errors.New("models: no quarantined_days provided for upsert")
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(quarantinedDayColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	quarantinedDayUpsertCacheMut.RLock()
+	cache, cached := quarantinedDayUpsertCache[key]
+	quarantinedDayUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, _ := insertColumns.InsertColumnSet(
+			quarantinedDayAllColumns,
+			quarantinedDayColumnsWithDefault,
+			quarantinedDayColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			quarantinedDayAllColumns,
+			quarantinedDayPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert quarantined_days, could not build update column list")
+		}
+
+		ret := strmangle.SetComplement(quarantinedDayAllColumns, strmangle.SetIntersect(insert, update))
+
+		conflict := conflictColumns
+		if len(conflict) == 0 && updateOnConflict && len(update) != 0 {
+			if len(quarantinedDayPrimaryKeyColumns) == 0 {
+				return errors.New("models: unable to upsert quarantined_days, could not build conflict column list")
+			}
+
+			conflict = make([]string, len(quarantinedDayPrimaryKeyColumns))
+			copy(conflict, quarantinedDayPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"quarantined_days\"", updateOnConflict, ret, update, conflict, insert, opts...)
+
+		cache.valueMapping, err = queries.BindMapping(quarantinedDayType, quarantinedDayMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(quarantinedDayType, quarantinedDayMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert quarantined_days")
+	}
+
+	if !cached {
+		quarantinedDayUpsertCacheMut.Lock()
+		quarantinedDayUpsertCache[key] = cache
+		quarantinedDayUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single QuarantinedDay record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *QuarantinedDay) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no QuarantinedDay provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), quarantinedDayPrimaryKeyMapping)
+	sql := "DELETE FROM \"quarantined_days\" WHERE \"provider\"=$1 AND \"day\"=$2"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from quarantined_days")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for quarantined_days")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q quarantinedDayQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no quarantinedDayQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from quarantined_days")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for quarantined_days")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o QuarantinedDaySlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(quarantinedDayBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), quarantinedDayPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"quarantined_days\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, quarantinedDayPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from quarantinedDay slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for quarantined_days")
+	}
+
+	if len(quarantinedDayAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *QuarantinedDay) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindQuarantinedDay(ctx, exec, o.Provider, o.Day)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *QuarantinedDaySlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := QuarantinedDaySlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), quarantinedDayPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"quarantined_days\".* FROM \"quarantined_days\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, quarantinedDayPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in QuarantinedDaySlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// QuarantinedDayExists checks if the QuarantinedDay row exists.
+func QuarantinedDayExists(ctx context.Context, exec boil.ContextExecutor, provider ProviderType, day time.Time) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"quarantined_days\" where \"provider\"=$1 AND \"day\"=$2 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, provider, day)
+	}
+	row := exec.QueryRowContext(ctx, sql, provider, day)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if quarantined_days exists")
+	}
+
+	return exists, nil
+}
+
+// Exists checks if the QuarantinedDay row exists.
+func (o *QuarantinedDay) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	return QuarantinedDayExists(ctx, exec, o.Provider, o.Day)
+}
@@ -24,12 +24,15 @@ import (
 
 // State is an object representing the database table.
 type State struct {
-	ID                           int       `boil:"id" json:"id" toml:"id" yaml:"id"`
-	NetworkName                  string    `boil:"network_name" json:"network_name" toml:"network_name" yaml:"network_name"`
-	LowestBlockNumber            int       `boil:"lowest_block_number" json:"lowest_block_number" toml:"lowest_block_number" yaml:"lowest_block_number"`
-	HighestBlockNumber           int       `boil:"highest_block_number" json:"highest_block_number" toml:"highest_block_number" yaml:"highest_block_number"`
-	EarliestValidatorPerformance null.Time `boil:"earliest_validator_performance" json:"earliest_validator_performance,omitempty" toml:"earliest_validator_performance" yaml:"earliest_validator_performance,omitempty"`
-	LatestValidatorPerformance   null.Time `boil:"latest_validator_performance" json:"latest_validator_performance,omitempty" toml:"latest_validator_performance" yaml:"latest_validator_performance,omitempty"`
+	ID                           int         `boil:"id" json:"id" toml:"id" yaml:"id"`
+	NetworkName                  string      `boil:"network_name" json:"network_name" toml:"network_name" yaml:"network_name"`
+	LowestBlockNumber            int         `boil:"lowest_block_number" json:"lowest_block_number" toml:"lowest_block_number" yaml:"lowest_block_number"`
+	HighestBlockNumber           int         `boil:"highest_block_number" json:"highest_block_number" toml:"highest_block_number" yaml:"highest_block_number"`
+	EarliestValidatorPerformance null.Time   `boil:"earliest_validator_performance" json:"earliest_validator_performance,omitempty" toml:"earliest_validator_performance" yaml:"earliest_validator_performance,omitempty"`
+	LatestValidatorPerformance   null.Time   `boil:"latest_validator_performance" json:"latest_validator_performance,omitempty" toml:"latest_validator_performance" yaml:"latest_validator_performance,omitempty"`
+	HighestBlockHash             null.String `boil:"highest_block_hash" json:"highest_block_hash,omitempty" toml:"highest_block_hash" yaml:"highest_block_hash,omitempty"`
+	HighestBlockTime             null.Time   `boil:"highest_block_time" json:"highest_block_time,omitempty" toml:"highest_block_time" yaml:"highest_block_time,omitempty"`
+	SlotsPerEpoch                int         `boil:"slots_per_epoch" json:"slots_per_epoch" toml:"slots_per_epoch" yaml:"slots_per_epoch"`
 
 	R *stateR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L stateL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -42,6 +45,9 @@ var StateColumns = struct {
 	HighestBlockNumber           string
 	EarliestValidatorPerformance string
 	LatestValidatorPerformance   string
+	HighestBlockHash             string
+	HighestBlockTime             string
+	SlotsPerEpoch                string
 }{
 	ID:                           "id",
 	NetworkName:                  "network_name",
@@ -49,6 +55,9 @@ var StateColumns = struct {
 	HighestBlockNumber:           "highest_block_number",
 	EarliestValidatorPerformance: "earliest_validator_performance",
 	LatestValidatorPerformance:   "latest_validator_performance",
+	HighestBlockHash:             "highest_block_hash",
+	HighestBlockTime:             "highest_block_time",
+	SlotsPerEpoch:                "slots_per_epoch",
 }
 
 var StateTableColumns = struct {
@@ -58,6 +67,9 @@ var StateTableColumns = struct {
 	HighestBlockNumber           string
 	EarliestValidatorPerformance string
 	LatestValidatorPerformance   string
+	HighestBlockHash             string
+	HighestBlockTime             string
+	SlotsPerEpoch                string
 }{
 	ID:                           "state.id",
 	NetworkName:                  "state.network_name",
@@ -65,34 +77,13 @@ var StateTableColumns = struct {
 	HighestBlockNumber:           "state.highest_block_number",
 	EarliestValidatorPerformance: "state.earliest_validator_performance",
 	LatestValidatorPerformance:   "state.latest_validator_performance",
+	HighestBlockHash:             "state.highest_block_hash",
+	HighestBlockTime:             "state.highest_block_time",
+	SlotsPerEpoch:                "state.slots_per_epoch",
 }
 
 // Generated where
 
-type whereHelpernull_Time struct{ field string }
-
-func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
-	return qmhelper.WhereNullEQ(w.field, false, x)
-}
-func (w whereHelpernull_Time) NEQ(x null.Time) qm.QueryMod {
-	return qmhelper.WhereNullEQ(w.field, true, x)
-}
-func (w whereHelpernull_Time) LT(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpernull_Time) LTE(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpernull_Time) GT(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-
-func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
-func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
-
 var StateWhere = struct {
 	ID                           whereHelperint
 	NetworkName                  whereHelperstring
@@ -100,6 +91,9 @@ var StateWhere = struct {
 	HighestBlockNumber           whereHelperint
 	EarliestValidatorPerformance whereHelpernull_Time
 	LatestValidatorPerformance   whereHelpernull_Time
+	HighestBlockHash             whereHelpernull_String
+	HighestBlockTime             whereHelpernull_Time
+	SlotsPerEpoch                whereHelperint
 }{
 	ID:                           whereHelperint{field: "\"state\".\"id\""},
 	NetworkName:                  whereHelperstring{field: "\"state\".\"network_name\""},
@@ -107,6 +101,9 @@ var StateWhere = struct {
 	HighestBlockNumber:           whereHelperint{field: "\"state\".\"highest_block_number\""},
 	EarliestValidatorPerformance: whereHelpernull_Time{field: "\"state\".\"earliest_validator_performance\""},
 	LatestValidatorPerformance:   whereHelpernull_Time{field: "\"state\".\"latest_validator_performance\""},
+	HighestBlockHash:             whereHelpernull_String{field: "\"state\".\"highest_block_hash\""},
+	HighestBlockTime:             whereHelpernull_Time{field: "\"state\".\"highest_block_time\""},
+	SlotsPerEpoch:                whereHelperint{field: "\"state\".\"slots_per_epoch\""},
 }
 
 // StateRels is where relationship names are stored.
@@ -126,9 +123,9 @@ func (*stateR) NewStruct() *stateR {
 type stateL struct{}
 
 var (
-	stateAllColumns            = []string{"id", "network_name", "lowest_block_number", "highest_block_number", "earliest_validator_performance", "latest_validator_performance"}
+	stateAllColumns            = []string{"id", "network_name", "lowest_block_number", "highest_block_number", "earliest_validator_performance", "latest_validator_performance", "highest_block_hash", "highest_block_time", "slots_per_epoch"}
 	stateColumnsWithoutDefault = []string{"network_name", "lowest_block_number", "highest_block_number"}
-	stateColumnsWithDefault    = []string{"id", "earliest_validator_performance", "latest_validator_performance"}
+	stateColumnsWithDefault    = []string{"id", "earliest_validator_performance", "latest_validator_performance", "highest_block_hash", "highest_block_time", "slots_per_epoch"}
 	statePrimaryKeyColumns     = []string{"id"}
 	stateGeneratedColumns      = []string{}
 )
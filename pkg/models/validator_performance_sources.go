@@ -0,0 +1,227 @@
+// Code generated by SQLBoiler 4.16.2 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// ValidatorPerformanceSource is an object representing the database table.
+type ValidatorPerformanceSource struct {
+	Provider   NullProviderType `boil:"provider" json:"provider,omitempty" toml:"provider" yaml:"provider,omitempty"`
+	Day        null.Time        `boil:"day" json:"day,omitempty" toml:"day" yaml:"day,omitempty"`
+	Source     NullProviderType `boil:"source" json:"source,omitempty" toml:"source" yaml:"source,omitempty"`
+	Validators null.Int64       `boil:"validators" json:"validators,omitempty" toml:"validators" yaml:"validators,omitempty"`
+}
+
+var ValidatorPerformanceSourceColumns = struct {
+	Provider   string
+	Day        string
+	Source     string
+	Validators string
+}{
+	Provider:   "provider",
+	Day:        "day",
+	Source:     "source",
+	Validators: "validators",
+}
+
+var ValidatorPerformanceSourceTableColumns = struct {
+	Provider   string
+	Day        string
+	Source     string
+	Validators string
+}{
+	Provider:   "validator_performance_sources.provider",
+	Day:        "validator_performance_sources.day",
+	Source:     "validator_performance_sources.source",
+	Validators: "validator_performance_sources.validators",
+}
+
+// Generated where
+
+var ValidatorPerformanceSourceWhere = struct {
+	Provider   whereHelperNullProviderType
+	Day        whereHelpernull_Time
+	Source     whereHelperNullProviderType
+	Validators whereHelpernull_Int64
+}{
+	Provider:   whereHelperNullProviderType{field: "\"validator_performance_sources\".\"provider\""},
+	Day:        whereHelpernull_Time{field: "\"validator_performance_sources\".\"day\""},
+	Source:     whereHelperNullProviderType{field: "\"validator_performance_sources\".\"source\""},
+	Validators: whereHelpernull_Int64{field: "\"validator_performance_sources\".\"validators\""},
+}
+
+var (
+	validatorPerformanceSourceAllColumns            = []string{"provider", "day", "source", "validators"}
+	validatorPerformanceSourceColumnsWithoutDefault = []string{}
+	validatorPerformanceSourceColumnsWithDefault    = []string{"provider", "day", "source", "validators"}
+	validatorPerformanceSourcePrimaryKeyColumns     = []string{}
+	validatorPerformanceSourceGeneratedColumns      = []string{}
+)
+
+type (
+	// ValidatorPerformanceSourceSlice is an alias for a slice of pointers to ValidatorPerformanceSource.
+	// This should almost always be used instead of []ValidatorPerformanceSource.
+	ValidatorPerformanceSourceSlice []*ValidatorPerformanceSource
+	// ValidatorPerformanceSourceHook is the signature for custom ValidatorPerformanceSource hook methods
+	ValidatorPerformanceSourceHook func(context.Context, boil.ContextExecutor, *ValidatorPerformanceSource) error
+
+	validatorPerformanceSourceQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	validatorPerformanceSourceType           = reflect.TypeOf(&ValidatorPerformanceSource{})
+	validatorPerformanceSourceMapping        = queries.MakeStructMapping(validatorPerformanceSourceType)
+	validatorPerformanceSourceInsertCacheMut sync.RWMutex
+	validatorPerformanceSourceInsertCache    = make(map[string]insertCache)
+	validatorPerformanceSourceUpdateCacheMut sync.RWMutex
+	validatorPerformanceSourceUpdateCache    = make(map[string]updateCache)
+	validatorPerformanceSourceUpsertCacheMut sync.RWMutex
+	validatorPerformanceSourceUpsertCache    = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+	// These are used in some views
+	_ = fmt.Sprintln("")
+	_ = reflect.Int
+	_ = strings.Builder{}
+	_ = sync.Mutex{}
+	_ = strmangle.Plural("")
+	_ = strconv.IntSize
+)
+
+var validatorPerformanceSourceAfterSelectMu sync.Mutex
+var validatorPerformanceSourceAfterSelectHooks []ValidatorPerformanceSourceHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *ValidatorPerformanceSource) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range validatorPerformanceSourceAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddValidatorPerformanceSourceHook registers your hook function for all future operations.
+func AddValidatorPerformanceSourceHook(hookPoint boil.HookPoint, validatorPerformanceSourceHook ValidatorPerformanceSourceHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		validatorPerformanceSourceAfterSelectMu.Lock()
+		validatorPerformanceSourceAfterSelectHooks = append(validatorPerformanceSourceAfterSelectHooks, validatorPerformanceSourceHook)
+		validatorPerformanceSourceAfterSelectMu.Unlock()
+	}
+}
+
+// One returns a single validatorPerformanceSource record from the query.
+func (q validatorPerformanceSourceQuery) One(ctx context.Context, exec boil.ContextExecutor) (*ValidatorPerformanceSource, error) {
+	o := &ValidatorPerformanceSource{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for validator_performance_sources")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all ValidatorPerformanceSource records from the query.
+func (q validatorPerformanceSourceQuery) All(ctx context.Context, exec boil.ContextExecutor) (ValidatorPerformanceSourceSlice, error) {
+	var o []*ValidatorPerformanceSource
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to ValidatorPerformanceSource slice")
+	}
+
+	if len(validatorPerformanceSourceAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all ValidatorPerformanceSource records in the query.
+func (q validatorPerformanceSourceQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count validator_performance_sources rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q validatorPerformanceSourceQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if validator_performance_sources exists")
+	}
+
+	return count > 0, nil
+}
+
+// ValidatorPerformanceSources retrieves all the records using an executor.
+func ValidatorPerformanceSources(mods ...qm.QueryMod) validatorPerformanceSourceQuery {
+	mods = append(mods, qm.From("\"validator_performance_sources\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"validator_performance_sources\".*"})
+	}
+
+	return validatorPerformanceSourceQuery{q}
+}
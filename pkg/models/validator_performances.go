@@ -24,29 +24,31 @@ import (
 
 // ValidatorPerformance is an object representing the database table.
 type ValidatorPerformance struct {
-	Provider              ProviderType `boil:"provider" json:"provider" toml:"provider" yaml:"provider"`
-	Day                   time.Time    `boil:"day" json:"day" toml:"day" yaml:"day"`
-	FromEpoch             int          `boil:"from_epoch" json:"from_epoch" toml:"from_epoch" yaml:"from_epoch"`
-	ToEpoch               int          `boil:"to_epoch" json:"to_epoch" toml:"to_epoch" yaml:"to_epoch"`
-	OwnerAddress          string       `boil:"owner_address" json:"owner_address" toml:"owner_address" yaml:"owner_address"`
-	PublicKey             string       `boil:"public_key" json:"public_key" toml:"public_key" yaml:"public_key"`
-	SolventWholeDay       bool         `boil:"solvent_whole_day" json:"solvent_whole_day" toml:"solvent_whole_day" yaml:"solvent_whole_day"`
-	Index                 null.Int     `boil:"index" json:"index,omitempty" toml:"index" yaml:"index,omitempty"`
-	EndEffectiveBalance   null.Int64   `boil:"end_effective_balance" json:"end_effective_balance,omitempty" toml:"end_effective_balance" yaml:"end_effective_balance,omitempty"`
-	StartBeaconStatus     null.String  `boil:"start_beacon_status" json:"start_beacon_status,omitempty" toml:"start_beacon_status" yaml:"start_beacon_status,omitempty"`
-	EndBeaconStatus       null.String  `boil:"end_beacon_status" json:"end_beacon_status,omitempty" toml:"end_beacon_status" yaml:"end_beacon_status,omitempty"`
-	Decideds              null.Int     `boil:"decideds" json:"decideds,omitempty" toml:"decideds" yaml:"decideds,omitempty"`
-	Effectiveness         null.Float32 `boil:"effectiveness" json:"effectiveness,omitempty" toml:"effectiveness" yaml:"effectiveness,omitempty"`
-	AttestationRate       null.Float32 `boil:"attestation_rate" json:"attestation_rate,omitempty" toml:"attestation_rate" yaml:"attestation_rate,omitempty"`
-	AttestationsAssigned  null.Int16   `boil:"attestations_assigned" json:"attestations_assigned,omitempty" toml:"attestations_assigned" yaml:"attestations_assigned,omitempty"`
-	AttestationsExecuted  null.Int16   `boil:"attestations_executed" json:"attestations_executed,omitempty" toml:"attestations_executed" yaml:"attestations_executed,omitempty"`
-	AttestationsMissed    null.Int16   `boil:"attestations_missed" json:"attestations_missed,omitempty" toml:"attestations_missed" yaml:"attestations_missed,omitempty"`
-	ProposalsAssigned     null.Int16   `boil:"proposals_assigned" json:"proposals_assigned,omitempty" toml:"proposals_assigned" yaml:"proposals_assigned,omitempty"`
-	ProposalsExecuted     null.Int16   `boil:"proposals_executed" json:"proposals_executed,omitempty" toml:"proposals_executed" yaml:"proposals_executed,omitempty"`
-	ProposalsMissed       null.Int16   `boil:"proposals_missed" json:"proposals_missed,omitempty" toml:"proposals_missed" yaml:"proposals_missed,omitempty"`
-	SyncCommitteeAssigned null.Int16   `boil:"sync_committee_assigned" json:"sync_committee_assigned,omitempty" toml:"sync_committee_assigned" yaml:"sync_committee_assigned,omitempty"`
-	SyncCommitteeExecuted null.Int16   `boil:"sync_committee_executed" json:"sync_committee_executed,omitempty" toml:"sync_committee_executed" yaml:"sync_committee_executed,omitempty"`
-	SyncCommitteeMissed   null.Int16   `boil:"sync_committee_missed" json:"sync_committee_missed,omitempty" toml:"sync_committee_missed" yaml:"sync_committee_missed,omitempty"`
+	Provider              ProviderType       `boil:"provider" json:"provider" toml:"provider" yaml:"provider"`
+	Day                   time.Time          `boil:"day" json:"day" toml:"day" yaml:"day"`
+	FromEpoch             int                `boil:"from_epoch" json:"from_epoch" toml:"from_epoch" yaml:"from_epoch"`
+	ToEpoch               int                `boil:"to_epoch" json:"to_epoch" toml:"to_epoch" yaml:"to_epoch"`
+	OwnerAddress          string             `boil:"owner_address" json:"owner_address" toml:"owner_address" yaml:"owner_address"`
+	PublicKey             string             `boil:"public_key" json:"public_key" toml:"public_key" yaml:"public_key"`
+	SolventWholeDay       bool               `boil:"solvent_whole_day" json:"solvent_whole_day" toml:"solvent_whole_day" yaml:"solvent_whole_day"`
+	Index                 null.Int           `boil:"index" json:"index,omitempty" toml:"index" yaml:"index,omitempty"`
+	EndEffectiveBalance   null.Int64         `boil:"end_effective_balance" json:"end_effective_balance,omitempty" toml:"end_effective_balance" yaml:"end_effective_balance,omitempty"`
+	StartBeaconStatus     null.String        `boil:"start_beacon_status" json:"start_beacon_status,omitempty" toml:"start_beacon_status" yaml:"start_beacon_status,omitempty"`
+	EndBeaconStatus       null.String        `boil:"end_beacon_status" json:"end_beacon_status,omitempty" toml:"end_beacon_status" yaml:"end_beacon_status,omitempty"`
+	Decideds              null.Int           `boil:"decideds" json:"decideds,omitempty" toml:"decideds" yaml:"decideds,omitempty"`
+	Effectiveness         null.Float32       `boil:"effectiveness" json:"effectiveness,omitempty" toml:"effectiveness" yaml:"effectiveness,omitempty"`
+	AttestationRate       null.Float32       `boil:"attestation_rate" json:"attestation_rate,omitempty" toml:"attestation_rate" yaml:"attestation_rate,omitempty"`
+	AttestationsAssigned  null.Int16         `boil:"attestations_assigned" json:"attestations_assigned,omitempty" toml:"attestations_assigned" yaml:"attestations_assigned,omitempty"`
+	AttestationsExecuted  null.Int16         `boil:"attestations_executed" json:"attestations_executed,omitempty" toml:"attestations_executed" yaml:"attestations_executed,omitempty"`
+	AttestationsMissed    null.Int16         `boil:"attestations_missed" json:"attestations_missed,omitempty" toml:"attestations_missed" yaml:"attestations_missed,omitempty"`
+	ProposalsAssigned     null.Int16         `boil:"proposals_assigned" json:"proposals_assigned,omitempty" toml:"proposals_assigned" yaml:"proposals_assigned,omitempty"`
+	ProposalsExecuted     null.Int16         `boil:"proposals_executed" json:"proposals_executed,omitempty" toml:"proposals_executed" yaml:"proposals_executed,omitempty"`
+	ProposalsMissed       null.Int16         `boil:"proposals_missed" json:"proposals_missed,omitempty" toml:"proposals_missed" yaml:"proposals_missed,omitempty"`
+	SyncCommitteeAssigned null.Int16         `boil:"sync_committee_assigned" json:"sync_committee_assigned,omitempty" toml:"sync_committee_assigned" yaml:"sync_committee_assigned,omitempty"`
+	SyncCommitteeExecuted null.Int16         `boil:"sync_committee_executed" json:"sync_committee_executed,omitempty" toml:"sync_committee_executed" yaml:"sync_committee_executed,omitempty"`
+	SyncCommitteeMissed   null.Int16         `boil:"sync_committee_missed" json:"sync_committee_missed,omitempty" toml:"sync_committee_missed" yaml:"sync_committee_missed,omitempty"`
+	Source                NullProviderType   `boil:"source" json:"source,omitempty" toml:"source" yaml:"source,omitempty"`
+	DecidedsSource        NullDecidedsSource `boil:"decideds_source" json:"decideds_source,omitempty" toml:"decideds_source" yaml:"decideds_source,omitempty"`
 
 	R *validatorPerformanceR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L validatorPerformanceL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -76,6 +78,8 @@ var ValidatorPerformanceColumns = struct {
 	SyncCommitteeAssigned string
 	SyncCommitteeExecuted string
 	SyncCommitteeMissed   string
+	Source                string
+	DecidedsSource        string
 }{
 	Provider:              "provider",
 	Day:                   "day",
@@ -100,6 +104,8 @@ var ValidatorPerformanceColumns = struct {
 	SyncCommitteeAssigned: "sync_committee_assigned",
 	SyncCommitteeExecuted: "sync_committee_executed",
 	SyncCommitteeMissed:   "sync_committee_missed",
+	Source:                "source",
+	DecidedsSource:        "decideds_source",
 }
 
 var ValidatorPerformanceTableColumns = struct {
@@ -126,6 +132,8 @@ var ValidatorPerformanceTableColumns = struct {
 	SyncCommitteeAssigned string
 	SyncCommitteeExecuted string
 	SyncCommitteeMissed   string
+	Source                string
+	DecidedsSource        string
 }{
 	Provider:              "validator_performances.provider",
 	Day:                   "validator_performances.day",
@@ -150,45 +158,12 @@ var ValidatorPerformanceTableColumns = struct {
 	SyncCommitteeAssigned: "validator_performances.sync_committee_assigned",
 	SyncCommitteeExecuted: "validator_performances.sync_committee_executed",
 	SyncCommitteeMissed:   "validator_performances.sync_committee_missed",
+	Source:                "validator_performances.source",
+	DecidedsSource:        "validator_performances.decideds_source",
 }
 
 // Generated where
 
-type whereHelperProviderType struct{ field string }
-
-func (w whereHelperProviderType) EQ(x ProviderType) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.EQ, x)
-}
-func (w whereHelperProviderType) NEQ(x ProviderType) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.NEQ, x)
-}
-func (w whereHelperProviderType) LT(x ProviderType) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelperProviderType) LTE(x ProviderType) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelperProviderType) GT(x ProviderType) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelperProviderType) GTE(x ProviderType) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-func (w whereHelperProviderType) IN(slice []ProviderType) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperProviderType) NIN(slice []ProviderType) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
 type whereHelpernull_Int struct{ field string }
 
 func (w whereHelpernull_Int) EQ(x null.Int) qm.QueryMod {
@@ -341,6 +316,84 @@ func (w whereHelpernull_Int16) NIN(slice []int16) qm.QueryMod {
 func (w whereHelpernull_Int16) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
 func (w whereHelpernull_Int16) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
+type whereHelperNullProviderType struct{ field string }
+
+func (w whereHelperNullProviderType) EQ(x NullProviderType) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelperNullProviderType) NEQ(x NullProviderType) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelperNullProviderType) LT(x NullProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperNullProviderType) LTE(x NullProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperNullProviderType) GT(x NullProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperNullProviderType) GTE(x NullProviderType) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperNullProviderType) IN(slice []NullProviderType) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperNullProviderType) NIN(slice []NullProviderType) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelperNullProviderType) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelperNullProviderType) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+type whereHelperNullDecidedsSource struct{ field string }
+
+func (w whereHelperNullDecidedsSource) EQ(x NullDecidedsSource) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelperNullDecidedsSource) NEQ(x NullDecidedsSource) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelperNullDecidedsSource) LT(x NullDecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperNullDecidedsSource) LTE(x NullDecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperNullDecidedsSource) GT(x NullDecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperNullDecidedsSource) GTE(x NullDecidedsSource) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelperNullDecidedsSource) IN(slice []NullDecidedsSource) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperNullDecidedsSource) NIN(slice []NullDecidedsSource) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelperNullDecidedsSource) IsNull() qm.QueryMod { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelperNullDecidedsSource) IsNotNull() qm.QueryMod {
+	return qmhelper.WhereIsNotNull(w.field)
+}
+
 var ValidatorPerformanceWhere = struct {
 	Provider              whereHelperProviderType
 	Day                   whereHelpertime_Time
@@ -365,6 +418,8 @@ var ValidatorPerformanceWhere = struct {
 	SyncCommitteeAssigned whereHelpernull_Int16
 	SyncCommitteeExecuted whereHelpernull_Int16
 	SyncCommitteeMissed   whereHelpernull_Int16
+	Source                whereHelperNullProviderType
+	DecidedsSource        whereHelperNullDecidedsSource
 }{
 	Provider:              whereHelperProviderType{field: "\"validator_performances\".\"provider\""},
 	Day:                   whereHelpertime_Time{field: "\"validator_performances\".\"day\""},
@@ -389,6 +444,8 @@ var ValidatorPerformanceWhere = struct {
 	SyncCommitteeAssigned: whereHelpernull_Int16{field: "\"validator_performances\".\"sync_committee_assigned\""},
 	SyncCommitteeExecuted: whereHelpernull_Int16{field: "\"validator_performances\".\"sync_committee_executed\""},
 	SyncCommitteeMissed:   whereHelpernull_Int16{field: "\"validator_performances\".\"sync_committee_missed\""},
+	Source:                whereHelperNullProviderType{field: "\"validator_performances\".\"source\""},
+	DecidedsSource:        whereHelperNullDecidedsSource{field: "\"validator_performances\".\"decideds_source\""},
 }
 
 // ValidatorPerformanceRels is where relationship names are stored.
@@ -419,9 +476,9 @@ func (r *validatorPerformanceR) GetPublicKeyValidator() *Validator {
 type validatorPerformanceL struct{}
 
 var (
-	validatorPerformanceAllColumns            = []string{"provider", "day", "from_epoch", "to_epoch", "owner_address", "public_key", "solvent_whole_day", "index", "end_effective_balance", "start_beacon_status", "end_beacon_status", "decideds", "effectiveness", "attestation_rate", "attestations_assigned", "attestations_executed", "attestations_missed", "proposals_assigned", "proposals_executed", "proposals_missed", "sync_committee_assigned", "sync_committee_executed", "sync_committee_missed"}
+	validatorPerformanceAllColumns            = []string{"provider", "day", "from_epoch", "to_epoch", "owner_address", "public_key", "solvent_whole_day", "index", "end_effective_balance", "start_beacon_status", "end_beacon_status", "decideds", "effectiveness", "attestation_rate", "attestations_assigned", "attestations_executed", "attestations_missed", "proposals_assigned", "proposals_executed", "proposals_missed", "sync_committee_assigned", "sync_committee_executed", "sync_committee_missed", "source", "decideds_source"}
 	validatorPerformanceColumnsWithoutDefault = []string{"provider", "day", "from_epoch", "to_epoch", "owner_address", "public_key", "solvent_whole_day"}
-	validatorPerformanceColumnsWithDefault    = []string{"index", "end_effective_balance", "start_beacon_status", "end_beacon_status", "decideds", "effectiveness", "attestation_rate", "attestations_assigned", "attestations_executed", "attestations_missed", "proposals_assigned", "proposals_executed", "proposals_missed", "sync_committee_assigned", "sync_committee_executed", "sync_committee_missed"}
+	validatorPerformanceColumnsWithDefault    = []string{"index", "end_effective_balance", "start_beacon_status", "end_beacon_status", "decideds", "effectiveness", "attestation_rate", "attestations_assigned", "attestations_executed", "attestations_missed", "proposals_assigned", "proposals_executed", "proposals_missed", "sync_committee_assigned", "sync_committee_executed", "sync_committee_missed", "source", "decideds_source"}
 	validatorPerformancePrimaryKeyColumns     = []string{"provider", "day", "public_key"}
 	validatorPerformanceGeneratedColumns      = []string{}
 )
@@ -147,10 +147,12 @@ var ValidatorWhere = struct {
 // ValidatorRels is where relationship names are stored.
 var ValidatorRels = struct {
 	PublicKeyValidatorRedirect     string
+	PublicKeyPerformanceGaps       string
 	PublicKeyValidatorEvents       string
 	PublicKeyValidatorPerformances string
 }{
 	PublicKeyValidatorRedirect:     "PublicKeyValidatorRedirect",
+	PublicKeyPerformanceGaps:       "PublicKeyPerformanceGaps",
 	PublicKeyValidatorEvents:       "PublicKeyValidatorEvents",
 	PublicKeyValidatorPerformances: "PublicKeyValidatorPerformances",
 }
@@ -158,6 +160,7 @@ var ValidatorRels = struct {
 // validatorR is where relationships are stored.
 type validatorR struct {
 	PublicKeyValidatorRedirect     *ValidatorRedirect        `boil:"PublicKeyValidatorRedirect" json:"PublicKeyValidatorRedirect" toml:"PublicKeyValidatorRedirect" yaml:"PublicKeyValidatorRedirect"`
+	PublicKeyPerformanceGaps       PerformanceGapSlice       `boil:"PublicKeyPerformanceGaps" json:"PublicKeyPerformanceGaps" toml:"PublicKeyPerformanceGaps" yaml:"PublicKeyPerformanceGaps"`
 	PublicKeyValidatorEvents       ValidatorEventSlice       `boil:"PublicKeyValidatorEvents" json:"PublicKeyValidatorEvents" toml:"PublicKeyValidatorEvents" yaml:"PublicKeyValidatorEvents"`
 	PublicKeyValidatorPerformances ValidatorPerformanceSlice `boil:"PublicKeyValidatorPerformances" json:"PublicKeyValidatorPerformances" toml:"PublicKeyValidatorPerformances" yaml:"PublicKeyValidatorPerformances"`
 }
@@ -174,6 +177,13 @@ func (r *validatorR) GetPublicKeyValidatorRedirect() *ValidatorRedirect {
 	return r.PublicKeyValidatorRedirect
 }
 
+func (r *validatorR) GetPublicKeyPerformanceGaps() PerformanceGapSlice {
+	if r == nil {
+		return nil
+	}
+	return r.PublicKeyPerformanceGaps
+}
+
 func (r *validatorR) GetPublicKeyValidatorEvents() ValidatorEventSlice {
 	if r == nil {
 		return nil
@@ -515,6 +525,20 @@ func (o *Validator) PublicKeyValidatorRedirect(mods ...qm.QueryMod) validatorRed
 	return ValidatorRedirects(queryMods...)
 }
 
+// PublicKeyPerformanceGaps retrieves all the performance_gap's PerformanceGaps with an executor via public_key column.
+func (o *Validator) PublicKeyPerformanceGaps(mods ...qm.QueryMod) performanceGapQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"performance_gaps\".\"public_key\"=?", o.PublicKey),
+	)
+
+	return PerformanceGaps(queryMods...)
+}
+
 // PublicKeyValidatorEvents retrieves all the validator_event's ValidatorEvents with an executor via public_key column.
 func (o *Validator) PublicKeyValidatorEvents(mods ...qm.QueryMod) validatorEventQuery {
 	var queryMods []qm.QueryMod
@@ -660,6 +684,119 @@ func (validatorL) LoadPublicKeyValidatorRedirect(ctx context.Context, e boil.Con
 	return nil
 }
 
+// LoadPublicKeyPerformanceGaps allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (validatorL) LoadPublicKeyPerformanceGaps(ctx context.Context, e boil.ContextExecutor, singular bool, maybeValidator interface{}, mods queries.Applicator) error {
+	var slice []*Validator
+	var object *Validator
+
+	if singular {
+		var ok bool
+		object, ok = maybeValidator.(*Validator)
+		if !ok {
+			object = new(Validator)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeValidator)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeValidator))
+			}
+		}
+	} else {
+		s, ok := maybeValidator.(*[]*Validator)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeValidator)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeValidator))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &validatorR{}
+		}
+		args[object.PublicKey] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &validatorR{}
+			}
+			args[obj.PublicKey] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`performance_gaps`),
+		qm.WhereIn(`performance_gaps.public_key in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load performance_gaps")
+	}
+
+	var resultSlice []*PerformanceGap
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice performance_gaps")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on performance_gaps")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for performance_gaps")
+	}
+
+	if len(performanceGapAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.PublicKeyPerformanceGaps = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &performanceGapR{}
+			}
+			foreign.R.PublicKeyValidator = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.PublicKey == foreign.PublicKey {
+				local.R.PublicKeyPerformanceGaps = append(local.R.PublicKeyPerformanceGaps, foreign)
+				if foreign.R == nil {
+					foreign.R = &performanceGapR{}
+				}
+				foreign.R.PublicKeyValidator = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadPublicKeyValidatorEvents allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (validatorL) LoadPublicKeyValidatorEvents(ctx context.Context, e boil.ContextExecutor, singular bool, maybeValidator interface{}, mods queries.Applicator) error {
@@ -936,6 +1073,59 @@ func (o *Validator) SetPublicKeyValidatorRedirect(ctx context.Context, exec boil
 	return nil
 }
 
+// AddPublicKeyPerformanceGaps adds the given related objects to the existing relationships
+// of the validator, optionally inserting them as new records.
+// Appends related to o.R.PublicKeyPerformanceGaps.
+// Sets related.R.PublicKeyValidator appropriately.
+func (o *Validator) AddPublicKeyPerformanceGaps(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*PerformanceGap) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.PublicKey = o.PublicKey
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"performance_gaps\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"public_key"}),
+				strmangle.WhereClause("\"", "\"", 2, performanceGapPrimaryKeyColumns),
+			)
+			values := []interface{}{o.PublicKey, rel.Provider, rel.Day, rel.PublicKey}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.PublicKey = o.PublicKey
+		}
+	}
+
+	if o.R == nil {
+		o.R = &validatorR{
+			PublicKeyPerformanceGaps: related,
+		}
+	} else {
+		o.R.PublicKeyPerformanceGaps = append(o.R.PublicKeyPerformanceGaps, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &performanceGapR{
+				PublicKeyValidator: o,
+			}
+		} else {
+			rel.R.PublicKeyValidator = o
+		}
+	}
+	return nil
+}
+
 // AddPublicKeyValidatorEvents adds the given related objects to the existing relationships
 // of the validator, optionally inserting them as new records.
 // Appends related to o.R.PublicKeyValidatorEvents.
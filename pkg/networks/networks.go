@@ -0,0 +1,132 @@
+// Package networks resolves the SSV network to sync and calculate rewards for, either
+// built into the ssv library or defined in a config file, such as private devnets and forks.
+package networks
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// SSVAPINetwork is the network's path in the SSV API, such as mainnet in
+// /api/v4/mainnet/validators.
+type SSVAPINetwork string
+
+// Config defines a network that isn't built into the ssv library. Since JSON is a
+// subset of YAML, it can be written in either.
+type Config struct {
+	Name                    string `yaml:"name"`
+	RegistryContractAddress string `yaml:"registry_contract_address"`
+	RegistrySyncOffset      uint64 `yaml:"registry_sync_offset"`
+	DomainType              string `yaml:"domain_type"`
+	BeaconNetwork           string `yaml:"beacon_network"`
+
+	// SSVAPINetwork defaults to BeaconNetwork, as it is for the built-in networks.
+	SSVAPINetwork SSVAPINetwork `yaml:"ssv_api_network,omitempty"`
+}
+
+// ParseConfig parses the given YAML or JSON document into a Config.
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if config.SSVAPINetwork == "" {
+		config.SSVAPINetwork = SSVAPINetwork(config.BeaconNetwork)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// LoadConfig reads and parses the Config at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// namePattern is the names of custom networks, which are safe as both schema and
+// directory names.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func (c *Config) validate() error {
+	if c.Name == "" {
+		return errors.New("missing name")
+	}
+	if !namePattern.MatchString(c.Name) {
+		return fmt.Errorf("invalid name: %q (want lowercase letters, digits, - and _)", c.Name)
+	}
+	// Each network is stored in a schema named after it, so a custom network must not
+	// share its data with a built-in one, or with Postgres' own schemas.
+	if _, ok := networkconfig.SupportedConfigs[c.Name]; ok {
+		return fmt.Errorf("name %s is of a built-in network", c.Name)
+	}
+	if c.Name == "public" || c.Name == "information_schema" || strings.HasPrefix(c.Name, "pg_") {
+		return fmt.Errorf("name %s is of a reserved schema", c.Name)
+	}
+	if !common.IsHexAddress(c.RegistryContractAddress) {
+		return fmt.Errorf("invalid registry_contract_address: %q", c.RegistryContractAddress)
+	}
+	if _, err := c.domainType(); err != nil {
+		return err
+	}
+	if c.BeaconNetwork == "" {
+		return errors.New("missing beacon_network")
+	}
+	return nil
+}
+
+func (c *Config) domainType() (spectypes.DomainType, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(c.DomainType, "0x"))
+	if err != nil || len(b) != len(spectypes.DomainType{}) {
+		return spectypes.DomainType{}, fmt.Errorf("invalid domain_type: %q (want 4 hex bytes, e.g. 0x00000401)", c.DomainType)
+	}
+	return spectypes.DomainType(b), nil
+}
+
+// NetworkConfig returns the Config as the ssv library's NetworkConfig.
+func (c *Config) NetworkConfig() networkconfig.NetworkConfig {
+	domainType, _ := c.domainType()
+	return networkconfig.NetworkConfig{
+		Name:                 c.Name,
+		Beacon:               beacon.NewNetwork(spectypes.BeaconNetwork(c.BeaconNetwork)),
+		Domain:               domainType,
+		RegistrySyncOffset:   new(big.Int).SetUint64(c.RegistrySyncOffset),
+		RegistryContractAddr: common.HexToAddress(c.RegistryContractAddress).Hex(),
+	}
+}
+
+// Resolve returns the network defined in the config file at configPath, if given,
+// or else the built-in network of the given name.
+func Resolve(name, configPath string) (networkconfig.NetworkConfig, SSVAPINetwork, error) {
+	if configPath != "" {
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			return networkconfig.NetworkConfig{}, "", err
+		}
+		return config.NetworkConfig(), config.SSVAPINetwork, nil
+	}
+	network, err := networkconfig.GetNetworkConfigByName(name)
+	if err != nil {
+		return networkconfig.NetworkConfig{}, "", err
+	}
+	return network, SSVAPINetwork(network.Beacon.GetNetwork().String()), nil
+}
@@ -0,0 +1,83 @@
+package networks
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	const yamlConfig = `
+name: devnet
+registry_contract_address: "0x38a4794cced47d3baf7370ccc43b560d3a1beefa"
+registry_sync_offset: 181612
+domain_type: "0x00000502"
+beacon_network: holesky
+`
+	config, err := ParseConfig([]byte(yamlConfig))
+	require.NoError(t, err)
+	require.Equal(t, SSVAPINetwork("holesky"), config.SSVAPINetwork)
+
+	network := config.NetworkConfig()
+	require.Equal(t, "devnet", network.Name)
+	require.Equal(t, "0x38A4794cCEd47d3baf7370CcC43B560D3a1beEFA", network.RegistryContractAddr)
+	require.Equal(t, big.NewInt(181612), network.RegistrySyncOffset)
+	require.Equal(t, spectypes.DomainType{0x0, 0x0, 0x5, 0x2}, network.Domain)
+	require.Equal(t, "holesky", network.Beacon.GetNetwork().String())
+
+	// JSON is parsed as well.
+	const jsonConfig = `{
+		"name": "devnet",
+		"registry_contract_address": "0x38a4794cced47d3baf7370ccc43b560d3a1beefa",
+		"registry_sync_offset": 181612,
+		"domain_type": "0x00000502",
+		"beacon_network": "holesky",
+		"ssv_api_network": "holesky-stage"
+	}`
+	config, err = ParseConfig([]byte(jsonConfig))
+	require.NoError(t, err)
+	require.Equal(t, SSVAPINetwork("holesky-stage"), config.SSVAPINetwork)
+	require.Equal(t, network, config.NetworkConfig())
+
+	for _, invalid := range []string{
+		"name: mainnet\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+		"name: devnet\nregistry_contract_address: '0x38a4'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+		"name: devnet\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x0502'\nbeacon_network: holesky",
+		"name: devnet\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'",
+		"name: Devnet\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+		"name: ../devnet\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+		"name: public\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+		"name: pg_catalog\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+		"name: information_schema\nregistry_contract_address: '0x38a4794cced47d3baf7370ccc43b560d3a1beefa'\ndomain_type: '0x00000502'\nbeacon_network: holesky",
+	} {
+		_, err := ParseConfig([]byte(invalid))
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	network, apiNetwork, err := Resolve("mainnet", "")
+	require.NoError(t, err)
+	require.Equal(t, "mainnet", network.Name)
+	require.Equal(t, SSVAPINetwork("mainnet"), apiNetwork)
+
+	_, _, err = Resolve("devnet", "")
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "devnet.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: devnet
+registry_contract_address: "0x38a4794cced47d3baf7370ccc43b560d3a1beefa"
+domain_type: "0x00000502"
+beacon_network: holesky
+ssv_api_network: holesky-stage
+`), 0o644))
+	network, apiNetwork, err = Resolve("mainnet", path)
+	require.NoError(t, err)
+	require.Equal(t, "devnet", network.Name)
+	require.Equal(t, SSVAPINetwork("holesky-stage"), apiNetwork)
+}
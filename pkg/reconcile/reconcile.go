@@ -0,0 +1,223 @@
+// Package reconcile compares the validator performance of two providers, to find
+// where they disagree on the data that decides rewards.
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+)
+
+// Statuses of a validator-day under the rewards criteria. The exclusion reasons match
+// those reported by calc, with additional statuses for incomplete or missing rows.
+const (
+	StatusActive                = "active"
+	StatusNotRegisteredWholeDay = "not_registered_whole_day"
+	StatusNotEnoughAttestations = "not_enough_attestations"
+	StatusNotEnoughDecideds     = "not_enough_decideds"
+	StatusMissingAttestations   = "missing_attestations"
+	StatusMissingDecideds       = "missing_decideds"
+	StatusMissing               = "missing"
+)
+
+// CriteriaFunc returns the criteria that apply to a day.
+type CriteriaFunc func(day time.Time) (rewards.Criteria, error)
+
+// PlanCriteria returns the criteria of a plan's mechanics.
+func PlanCriteria(plan *rewards.Plan) CriteriaFunc {
+	return func(day time.Time) (rewards.Criteria, error) {
+		mechanics, err := plan.Mechanics.At(rewards.PeriodAt(day))
+		if err != nil {
+			return rewards.Criteria{}, err
+		}
+		return mechanics.Criteria, nil
+	}
+}
+
+// Status returns the status of a validator-day under the given criteria, in the order
+// of precedence of calc's exclusion reasons. Rows without attestations or decideds
+// aren't active, since they don't meet the criteria.
+func Status(p *models.ValidatorPerformance, criteria rewards.Criteria) string {
+	switch {
+	case p == nil:
+		return StatusMissing
+	case !p.SolventWholeDay:
+		return StatusNotRegisteredWholeDay
+	case !p.AttestationsExecuted.Valid:
+		return StatusMissingAttestations
+	case int(p.AttestationsExecuted.Int16) < criteria.MinAttestationsPerDay:
+		return StatusNotEnoughAttestations
+	case !p.Decideds.Valid:
+		return StatusMissingDecideds
+	case p.Decideds.Int < criteria.MinDecidedsPerDay:
+		return StatusNotEnoughDecideds
+	default:
+		return StatusActive
+	}
+}
+
+// Difference is a validator-day in which the providers disagree.
+type Difference struct {
+	Day                   time.Time
+	PublicKey             string
+	OwnerAddress          string
+	AttestationsExecutedA *int16
+	AttestationsExecutedB *int16
+	EndEffectiveBalanceA  *int64
+	EndEffectiveBalanceB  *int64
+	StatusA               string
+	StatusB               string
+}
+
+// StatusDiffers reports whether the validator-day is active for one provider and excluded for the other.
+func (d *Difference) StatusDiffers() bool {
+	return (d.StatusA == StatusActive) != (d.StatusB == StatusActive)
+}
+
+type Report struct {
+	// Days that both providers have performance for, which are the only days compared.
+	Days []time.Time
+
+	// Days that only one of the providers has performance for.
+	OnlyA, OnlyB []time.Time
+
+	// ValidatorDays is the number of validator-days compared.
+	ValidatorDays int
+
+	// Differences are the validator-days with differences in attestations executed,
+	// end effective balance or status, ordered by day and public key.
+	Differences []*Difference
+}
+
+// StatusDifferences returns the differences in which a validator-day is active for one
+// provider and excluded for the other.
+func (r *Report) StatusDifferences() []*Difference {
+	var diffs []*Difference
+	for _, d := range r.Differences {
+		if d.StatusDiffers() {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+// Load returns a provider's validator performance between the given days (inclusive).
+// Zero days aren't limited.
+func Load(
+	ctx context.Context,
+	db *sql.DB,
+	provider models.ProviderType,
+	fromDay, toDay time.Time,
+) (models.ValidatorPerformanceSlice, error) {
+	mods := []qm.QueryMod{
+		models.ValidatorPerformanceWhere.Provider.EQ(provider),
+		qm.OrderBy(models.ValidatorPerformanceColumns.Day + ", " + models.ValidatorPerformanceColumns.PublicKey),
+	}
+	if !fromDay.IsZero() {
+		mods = append(mods, models.ValidatorPerformanceWhere.Day.GTE(fromDay))
+	}
+	if !toDay.IsZero() {
+		mods = append(mods, models.ValidatorPerformanceWhere.Day.LTE(toDay))
+	}
+	performances, err := models.ValidatorPerformances(mods...).All(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator performances of %s: %w", provider, err)
+	}
+	return performances, nil
+}
+
+// Compare compares the validator performance of two providers in the days they
+// both have performance for.
+func Compare(a, b models.ValidatorPerformanceSlice, criteria CriteriaFunc) (*Report, error) {
+	type key struct {
+		day       time.Time
+		publicKey string
+	}
+	index := func(performances models.ValidatorPerformanceSlice) (map[key]*models.ValidatorPerformance, map[time.Time]bool) {
+		byKey := make(map[key]*models.ValidatorPerformance, len(performances))
+		days := map[time.Time]bool{}
+		for _, p := range performances {
+			day := p.Day.UTC()
+			byKey[key{day, p.PublicKey}] = p
+			days[day] = true
+		}
+		return byKey, days
+	}
+	byKeyA, daysA := index(a)
+	byKeyB, daysB := index(b)
+
+	report := &Report{}
+	for day := range daysA {
+		if daysB[day] {
+			report.Days = append(report.Days, day)
+		} else {
+			report.OnlyA = append(report.OnlyA, day)
+		}
+	}
+	for day := range daysB {
+		if !daysA[day] {
+			report.OnlyB = append(report.OnlyB, day)
+		}
+	}
+	for _, days := range [][]time.Time{report.Days, report.OnlyA, report.OnlyB} {
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	}
+
+	// Validator-days of the compared days, from either provider.
+	var keys []key
+	for k := range byKeyA {
+		if daysB[k.day] {
+			keys = append(keys, k)
+		}
+	}
+	for k := range byKeyB {
+		if _, ok := byKeyA[k]; !ok && daysA[k.day] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].day.Equal(keys[j].day) {
+			return keys[i].day.Before(keys[j].day)
+		}
+		return keys[i].publicKey < keys[j].publicKey
+	})
+	report.ValidatorDays = len(keys)
+
+	for _, k := range keys {
+		dayCriteria, err := criteria(k.day)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get criteria of %s: %w", k.day.Format("2006-01-02"), err)
+		}
+		pa, pb := byKeyA[k], byKeyB[k]
+		d := &Difference{
+			Day:       k.day,
+			PublicKey: k.publicKey,
+			StatusA:   Status(pa, dayCriteria),
+			StatusB:   Status(pb, dayCriteria),
+		}
+		if pa != nil {
+			d.OwnerAddress = pa.OwnerAddress
+			d.AttestationsExecutedA = pa.AttestationsExecuted.Ptr()
+			d.EndEffectiveBalanceA = pa.EndEffectiveBalance.Ptr()
+		}
+		if pb != nil {
+			d.OwnerAddress = pb.OwnerAddress
+			d.AttestationsExecutedB = pb.AttestationsExecuted.Ptr()
+			d.EndEffectiveBalanceB = pb.EndEffectiveBalance.Ptr()
+		}
+		if d.StatusA != d.StatusB ||
+			null.Int16FromPtr(d.AttestationsExecutedA) != null.Int16FromPtr(d.AttestationsExecutedB) ||
+			null.Int64FromPtr(d.EndEffectiveBalanceA) != null.Int64FromPtr(d.EndEffectiveBalanceB) {
+			report.Differences = append(report.Differences, d)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,105 @@
+package reconcile
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/rewards"
+)
+
+func TestCompare(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+	performance := func(day time.Time, pubkey string, attestations, decideds null.Int, balance int64) *models.ValidatorPerformance {
+		return &models.ValidatorPerformance{
+			Day:                  day,
+			PublicKey:            pubkey,
+			OwnerAddress:         "owner",
+			SolventWholeDay:      true,
+			AttestationsExecuted: null.NewInt16(int16(attestations.Int), attestations.Valid),
+			Decideds:             decideds,
+			EndEffectiveBalance:  null.Int64From(balance),
+		}
+	}
+	ok, low := null.IntFrom(225), null.IntFrom(100)
+	const eth32 = 32_000_000_000
+
+	a := models.ValidatorPerformanceSlice{
+		performance(day1, "v1", ok, ok, eth32),
+		performance(day1, "v2", ok, ok, eth32),
+		performance(day1, "v3", low, ok, eth32),
+		performance(day1, "v4", ok, ok, eth32),
+		performance(day1, "v5", ok, ok, eth32),
+		performance(day2, "v1", ok, ok, eth32),
+	}
+	b := models.ValidatorPerformanceSlice{
+		performance(day1, "v1", ok, ok, eth32),
+		performance(day1, "v2", low, ok, eth32),          // Excluded only by B.
+		performance(day1, "v3", null.IntFrom(99), ok, 0), // Excluded by both, with different data.
+		performance(day1, "v4", null.Int{}, ok, eth32),   // Missing attestations.
+		performance(day1, "v6", ok, ok, eth32),           // Missing from A.
+		performance(day3, "v1", ok, ok, eth32),
+	}
+
+	criteria := func(day time.Time) (rewards.Criteria, error) {
+		return rewards.Criteria{MinAttestationsPerDay: 200, MinDecidedsPerDay: 10}, nil
+	}
+	report, err := Compare(a, b, criteria)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day1}, report.Days)
+	require.Equal(t, []time.Time{day2}, report.OnlyA)
+	require.Equal(t, []time.Time{day3}, report.OnlyB)
+	require.Equal(t, 6, report.ValidatorDays)
+
+	var differences []string
+	for _, d := range report.Differences {
+		differences = append(differences, fmt.Sprintf("%s %s/%s", d.PublicKey, d.StatusA, d.StatusB))
+	}
+	require.Equal(t, []string{
+		"v2 active/not_enough_attestations",
+		"v3 not_enough_attestations/not_enough_attestations",
+		"v4 active/missing_attestations",
+		"v5 active/missing",
+		"v6 missing/active",
+	}, differences)
+
+	var statusDifferences []string
+	for _, d := range report.StatusDifferences() {
+		statusDifferences = append(statusDifferences, d.PublicKey)
+	}
+	require.Equal(t, []string{"v2", "v4", "v5", "v6"}, statusDifferences)
+
+	// Unknown criteria fail the comparison.
+	_, err = Compare(a, b, func(day time.Time) (rewards.Criteria, error) {
+		return rewards.Criteria{}, fmt.Errorf("no mechanics")
+	})
+	require.ErrorContains(t, err, "no mechanics")
+}
+
+func TestStatus(t *testing.T) {
+	criteria := rewards.Criteria{MinAttestationsPerDay: 200, MinDecidedsPerDay: 10}
+	p := &models.ValidatorPerformance{
+		SolventWholeDay:      true,
+		AttestationsExecuted: null.Int16From(200),
+		Decideds:             null.IntFrom(10),
+	}
+	require.Equal(t, StatusActive, Status(p, criteria))
+
+	decideds := *p
+	decideds.Decideds = null.IntFrom(9)
+	require.Equal(t, StatusNotEnoughDecideds, Status(&decideds, criteria))
+	decideds.Decideds = null.Int{}
+	require.Equal(t, StatusMissingDecideds, Status(&decideds, criteria))
+
+	// Insolvency takes precedence over the other criteria, like in calc.
+	insolvent := decideds
+	insolvent.SolventWholeDay = false
+	require.Equal(t, StatusNotRegisteredWholeDay, Status(&insolvent, criteria))
+	require.Equal(t, StatusMissing, Status(nil, criteria))
+}
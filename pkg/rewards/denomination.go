@@ -0,0 +1,39 @@
+package rewards
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/bloxapp/ssv-rewards/pkg/precise"
+)
+
+// Denomination is the token a reward tree is paid out in.
+type Denomination string
+
+const (
+	DenominationSSV Denomination = "ssv"
+	DenominationETH Denomination = "eth"
+)
+
+func (d Denomination) Validate() error {
+	switch d {
+	case DenominationSSV, DenominationETH:
+		return nil
+	default:
+		return fmt.Errorf("invalid denomination %q (expected %q or %q)", d, DenominationSSV, DenominationETH)
+	}
+}
+
+// SSVToETH converts an amount of SSV (in wei) to ETH (in wei) at the given SSV/ETH price.
+// The result is rounded down to the nearest wei, so converted amounts never exceed
+// their exact value.
+func SSVToETH(ssvWei *big.Int, ssvETH *precise.ETH) (*big.Int, error) {
+	// Convert the price through its decimal representation, so that it's exact
+	// for prices with up to 18 decimals, such as those in the rewards plan.
+	rate, ok := new(big.Rat).SetString(ssvETH.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid ssv_eth %q", ssvETH.String())
+	}
+	product := new(big.Int).Mul(ssvWei, rate.Num())
+	return product.Div(product, rate.Denom()), nil
+}
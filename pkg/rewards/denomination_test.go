@@ -0,0 +1,38 @@
+package rewards
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenominationValidate(t *testing.T) {
+	require.NoError(t, DenominationSSV.Validate())
+	require.NoError(t, DenominationETH.Validate())
+	require.ErrorContains(t, Denomination("usd").Validate(), `invalid denomination "usd"`)
+}
+
+func TestSSVToETH(t *testing.T) {
+	tests := []struct {
+		name     string
+		ssvWei   string
+		ssvETH   string
+		expected string
+	}{
+		{name: "whole amount", ssvWei: "100000000000000000000", ssvETH: "0.0088235294", expected: "882352940000000000"},
+		{name: "rounds down", ssvWei: "1", ssvETH: "0.0088235294", expected: "0"},
+		{name: "rounds down fractional wei", ssvWei: "333", ssvETH: "0.5", expected: "166"},
+		{name: "zero", ssvWei: "0", ssvETH: "0.0088235294", expected: "0"},
+		{name: "exact with 18 decimals", ssvWei: "1000000000000000000", ssvETH: "0.000000000000000001", expected: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ssvWei, ok := new(big.Int).SetString(tt.ssvWei, 10)
+			require.True(t, ok)
+			ethWei, err := SSVToETH(ssvWei, mustParseETH(tt.ssvETH))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ethWei.String())
+		})
+	}
+}
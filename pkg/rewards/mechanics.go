@@ -44,6 +44,53 @@ type Mechanics struct {
 
 	PectraSupport     bool             `yaml:"pectra_support"`
 	NetworkFeeAddress ExecutionAddress `yaml:"network_fee_address"`
+
+	// ETHTree overrides mechanics for validators in ETH-fee clusters.
+	ETHTree *ETHTreeMechanics `yaml:"eth_tree,omitempty"`
+}
+
+// ETHTreeMechanics holds mechanics overrides for the ETH-fee cluster tree.
+type ETHTreeMechanics struct {
+	// APRBoostMultiplier multiplies the tier's APR boost for ETH-tree validators.
+	// Defaults to 1.
+	APRBoostMultiplier *precise.ETH `yaml:"apr_boost_multiplier,omitempty"`
+
+	// NetworkFeeAddress collects the network fees deducted from ETH-tree rewards.
+	// Defaults to the mechanics' network_fee_address.
+	NetworkFeeAddress ExecutionAddress `yaml:"network_fee_address"`
+
+	// Denomination is the token ETH-tree rewards are paid out in. Defaults to SSV.
+	// ETH-denominated rewards are converted from SSV at the round's SSV/ETH price.
+	Denomination Denomination `yaml:"denomination,omitempty"`
+}
+
+// ETHTreeDenomination returns the token ETH-tree rewards are paid out in.
+func (m *Mechanics) ETHTreeDenomination() Denomination {
+	if m.ETHTree == nil || m.ETHTree.Denomination == "" {
+		return DenominationSSV
+	}
+	return m.ETHTree.Denomination
+}
+
+// ETHTreeNetworkFeeAddress returns the address collecting the network fees deducted
+// from ETH-tree rewards, which is zero if there's none.
+func (m *Mechanics) ETHTreeNetworkFeeAddress() ExecutionAddress {
+	if m.ETHTree != nil && m.ETHTree.NetworkFeeAddress != (ExecutionAddress{}) {
+		return m.ETHTree.NetworkFeeAddress
+	}
+	return m.NetworkFeeAddress
+}
+
+// ETHTreeTier returns the tier to apply to ETH-tree validators, which is the given
+// tier with its APR boost multiplied by the ETH tree's APR boost multiplier.
+func (m *Mechanics) ETHTreeTier(tier *Tier) *Tier {
+	if tier == nil || m.ETHTree == nil || m.ETHTree.APRBoostMultiplier == nil {
+		return tier
+	}
+	return &Tier{
+		MaxEffectiveBalance: tier.MaxEffectiveBalance,
+		APRBoost:            precise.NewETH(nil).Mul(tier.APRBoost, m.ETHTree.APRBoostMultiplier),
+	}
 }
 
 type Tier struct {
@@ -105,6 +105,14 @@ func (p *Plan) validate() error {
 			}
 		}
 
+		if mechanics.ETHTree != nil && mechanics.ETHTree.APRBoostMultiplier != nil &&
+			mechanics.ETHTree.APRBoostMultiplier.Wei().Sign() < 0 {
+			return fmt.Errorf("eth_tree.apr_boost_multiplier must be non-negative in mechanics at period %s", mechanics.Since)
+		}
+		if err := mechanics.ETHTreeDenomination().Validate(); err != nil {
+			return fmt.Errorf("invalid eth_tree.denomination in mechanics at period %s: %w", mechanics.Since, err)
+		}
+
 		if err := mechanics.Criteria.Validate(); err != nil {
 			return fmt.Errorf("failed to validate criteria at period %s: %w", mechanics.Since, err)
 		}
@@ -159,6 +167,55 @@ func (p *Plan) validate() error {
 		if round.InflationCap != nil && round.InflationCap.Wei().Sign() <= 0 {
 			return fmt.Errorf("inflation_cap must be positive if specified in round %s", round.Period)
 		}
+		if round.Budget != nil && round.Budget.Wei().Sign() <= 0 {
+			return fmt.Errorf("budget must be positive if specified in round %s", round.Period)
+		}
+		if round.Budget != nil && round.InflationCap != nil {
+			return fmt.Errorf("both budget and inflation_cap specified in round %s", round.Period)
+		}
+		if round.Period.Before(p.LegacyCalculationCutoff) {
+			if round.Budget != nil {
+				return fmt.Errorf("budget is not supported before legacy_calculation_cutoff in round %s", round.Period)
+			}
+			if round.Rollover {
+				return fmt.Errorf("rollover is not supported before legacy_calculation_cutoff in round %s", round.Period)
+			}
+		}
+		if round.ETHTree != nil {
+			if round.ETHTree.NetworkFee != nil && round.ETHTree.NetworkFee.Wei().Sign() < 0 {
+				return fmt.Errorf("eth_tree.network_fee cannot be negative in round %s", round.Period)
+			}
+			if round.ETHTree.NetworkFee != nil && round.ETHTree.NetworkFee.Wei().Sign() > 0 {
+				// Deducted fees must be paid out to someone, or the totals wouldn't add up.
+				mechanics, err := p.Mechanics.At(round.Period)
+				if err != nil {
+					return fmt.Errorf("failed to get mechanics for round %s: %w", round.Period, err)
+				}
+				if mechanics.ETHTreeNetworkFeeAddress() == (ExecutionAddress{}) {
+					return fmt.Errorf("eth_tree.network_fee requires a network_fee_address in mechanics for round %s", round.Period)
+				}
+			}
+			if round.ETHTree.InflationCap != nil && round.ETHTree.InflationCap.Wei().Sign() <= 0 {
+				return fmt.Errorf("eth_tree.inflation_cap must be positive if specified in round %s", round.Period)
+			}
+			if round.ETHTree.Budget != nil && round.ETHTree.Budget.Wei().Sign() <= 0 {
+				return fmt.Errorf("eth_tree.budget must be positive if specified in round %s", round.Period)
+			}
+			if round.ETHTree.Budget != nil && round.ETHTree.InflationCap != nil {
+				return fmt.Errorf("both eth_tree.budget and eth_tree.inflation_cap specified in round %s", round.Period)
+			}
+			if round.ETHTree.SSVETH != nil && round.ETHTree.SSVETH.Wei().Sign() <= 0 {
+				return fmt.Errorf("eth_tree.ssv_eth must be positive if specified in round %s", round.Period)
+			}
+		}
+		if round.Rollover {
+			if round.Limit() == nil {
+				return fmt.Errorf("rollover requires budget or inflation_cap in round %s", round.Period)
+			}
+			if i+1 < len(p.Rounds) && p.Rounds[i+1].Limit() == nil {
+				return fmt.Errorf("rollover from round %s requires budget or inflation_cap in round %s", round.Period, p.Rounds[i+1].Period)
+			}
+		}
 		if i > 0 && p.Rounds[i-1].Period == p.Rounds[i].Period {
 			return fmt.Errorf("duplicate round: %s", p.Rounds[i].Period)
 		}
@@ -242,6 +299,112 @@ type Round struct {
 	SSVETH       *precise.ETH `yaml:"ssv_eth"`
 	NetworkFee   *precise.ETH `yaml:"network_fee,omitempty"`
 	InflationCap *precise.ETH `yaml:"inflation_cap,omitempty"`
+
+	// Budget is a fixed amount of SSV to distribute in the round, split across
+	// eligible validator-days weighted by effective balance. Unlike InflationCap,
+	// rewards are scaled up as well as down to match it.
+	Budget *precise.ETH `yaml:"budget,omitempty"`
+
+	// Rollover carries the unspent part of the round's budget or inflation cap
+	// over to the budget or inflation cap of the next round.
+	Rollover bool `yaml:"rollover,omitempty"`
+
+	// ETHTree overrides round parameters for validators in ETH-fee clusters.
+	ETHTree *ETHTreeRound `yaml:"eth_tree,omitempty"`
+}
+
+// ETHTreeRound holds round overrides for the ETH-fee cluster tree.
+//
+// When neither Budget nor InflationCap is set, the ETH tree shares the round's
+// budget or inflation cap with the SSV tree. Otherwise, each tree is limited
+// separately and the round's budget or inflation cap only applies to the SSV tree.
+type ETHTreeRound struct {
+	// NetworkFee is deducted from ETH-tree rewards. Defaults to no fee.
+	NetworkFee   *precise.ETH `yaml:"network_fee,omitempty"`
+	InflationCap *precise.ETH `yaml:"inflation_cap,omitempty"`
+	Budget       *precise.ETH `yaml:"budget,omitempty"`
+
+	// SSVETH is the SSV/ETH price used to pay ETH-denominated rewards.
+	// Defaults to the round's ssv_eth.
+	SSVETH *precise.ETH `yaml:"ssv_eth,omitempty"`
+}
+
+// Limit returns the ETH tree's own budget or inflation cap, or nil if it has neither.
+func (r *ETHTreeRound) Limit() *precise.ETH {
+	if r == nil {
+		return nil
+	}
+	if r.Budget != nil {
+		return r.Budget
+	}
+	return r.InflationCap
+}
+
+// SeparateETHTreeLimit reports whether the ETH tree has its own budget or inflation cap.
+func (r Round) SeparateETHTreeLimit() bool {
+	return r.ETHTree.Limit() != nil
+}
+
+// ETHTreeNetworkFee returns the network fee for the ETH tree, which is zero unless overridden.
+func (r Round) ETHTreeNetworkFee() *precise.ETH {
+	if r.ETHTree == nil || r.ETHTree.NetworkFee == nil {
+		return precise.NewETH(nil)
+	}
+	return r.ETHTree.NetworkFee
+}
+
+// ETHTreeSSVETH returns the SSV/ETH price to convert ETH-tree rewards at,
+// or nil if the round has none.
+func (r Round) ETHTreeSSVETH() *precise.ETH {
+	if r.ETHTree != nil && r.ETHTree.SSVETH != nil {
+		return r.ETHTree.SSVETH
+	}
+	return r.SSVETH
+}
+
+// Limit returns the round's budget or inflation cap, or nil if it has neither.
+func (r Round) Limit() *precise.ETH {
+	if r.Budget != nil {
+		return r.Budget
+	}
+	return r.InflationCap
+}
+
+// WithRollover returns a copy of the round with the given amount (in wei)
+// added to its budget or inflation cap.
+func (r Round) WithRollover(wei *big.Int) Round {
+	limit := r.Limit()
+	if limit == nil || wei.Sign() <= 0 {
+		return r
+	}
+	increased := precise.NewETH(nil).SetWei(new(big.Int).Add(limit.Wei(), wei))
+	if r.Budget != nil {
+		r.Budget = increased
+	} else {
+		r.InflationCap = increased
+	}
+	return r
+}
+
+// WithETHTreeRollover returns a copy of the round with the given amount (in wei)
+// added to the ETH tree's own budget or inflation cap, or to the round's budget
+// or inflation cap when the ETH tree has no separate limit.
+func (r Round) WithETHTreeRollover(wei *big.Int) Round {
+	if !r.SeparateETHTreeLimit() {
+		return r.WithRollover(wei)
+	}
+	if wei.Sign() <= 0 {
+		return r
+	}
+	ethTree := *r.ETHTree
+	increased := precise.NewETH(nil).SetWei(new(big.Int).Add(ethTree.Limit().Wei(), wei))
+	if ethTree.Budget != nil {
+		ethTree.Budget = increased
+	} else {
+		ethTree.InflationCap = increased
+	}
+	r.ETHTree = &ethTree
+	return r
 }
 
 type Rounds []Round
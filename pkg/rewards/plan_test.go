@@ -309,6 +309,239 @@ func TestPlan_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "zero budget",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{{Period: NewPeriod(2020, 1), Budget: mustParseETH("0")}},
+			},
+			expectedErr: "budget must be positive if specified in round 2020-01",
+		},
+		{
+			name: "budget with inflation cap",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{{Period: NewPeriod(2020, 1), Budget: mustParseETH("1000"), InflationCap: mustParseETH("1000")}},
+			},
+			expectedErr: "both budget and inflation_cap specified in round 2020-01",
+		},
+		{
+			name: "budget before legacy cutoff",
+			plan: &Plan{
+				LegacyCalculationCutoff: NewPeriod(2020, 2),
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{{Period: NewPeriod(2020, 1), Budget: mustParseETH("1000")}},
+			},
+			expectedErr: "budget is not supported before legacy_calculation_cutoff in round 2020-01",
+		},
+		{
+			name: "rollover without limit",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{{Period: NewPeriod(2020, 1), Rollover: true}},
+			},
+			expectedErr: "rollover requires budget or inflation_cap in round 2020-01",
+		},
+		{
+			name: "rollover into round without limit",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), InflationCap: mustParseETH("1000"), Rollover: true},
+					{Period: NewPeriod(2020, 2)},
+				},
+			},
+			expectedErr: "rollover from round 2020-01 requires budget or inflation_cap in round 2020-02",
+		},
+		{
+			name: "valid plan with budget and rollover",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), InflationCap: mustParseETH("1000"), Rollover: true},
+					{Period: NewPeriod(2020, 2), Budget: mustParseETH("1000"), Rollover: true},
+				},
+			},
+		},
+		{
+			name: "negative eth_tree network fee",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), ETHTree: &ETHTreeRound{NetworkFee: mustParseETH("-1")}},
+				},
+			},
+			expectedErr: "eth_tree.network_fee cannot be negative in round 2020-01",
+		},
+		{
+			name: "eth_tree network fee without network fee address",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+						ETHTree:  &ETHTreeMechanics{},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), ETHTree: &ETHTreeRound{NetworkFee: mustParseETH("1")}},
+				},
+			},
+			expectedErr: "eth_tree.network_fee requires a network_fee_address in mechanics for round 2020-01",
+		},
+		{
+			name: "eth_tree network fee with mechanics network fee address",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:             NewPeriod(2020, 1),
+						Criteria:          Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:             Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+						NetworkFeeAddress: ExecutionAddress{1},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), ETHTree: &ETHTreeRound{NetworkFee: mustParseETH("1")}},
+				},
+			},
+		},
+		{
+			name: "zero eth_tree budget",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), ETHTree: &ETHTreeRound{Budget: mustParseETH("0")}},
+				},
+			},
+			expectedErr: "eth_tree.budget must be positive if specified in round 2020-01",
+		},
+		{
+			name: "eth_tree budget with eth_tree inflation cap",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), ETHTree: &ETHTreeRound{Budget: mustParseETH("1000"), InflationCap: mustParseETH("1000")}},
+				},
+			},
+			expectedErr: "both eth_tree.budget and eth_tree.inflation_cap specified in round 2020-01",
+		},
+		{
+			name: "negative eth_tree apr boost multiplier",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+						ETHTree:  &ETHTreeMechanics{APRBoostMultiplier: mustParseETH("-0.5")},
+					},
+				},
+				Rounds: Rounds{{Period: NewPeriod(2020, 1)}},
+			},
+			expectedErr: "eth_tree.apr_boost_multiplier must be non-negative in mechanics at period 2020-01",
+		},
+		{
+			name: "zero eth_tree ssv_eth",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), ETHTree: &ETHTreeRound{SSVETH: mustParseETH("0")}},
+				},
+			},
+			expectedErr: "eth_tree.ssv_eth must be positive if specified in round 2020-01",
+		},
+		{
+			name: "invalid eth_tree denomination",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+						ETHTree:  &ETHTreeMechanics{Denomination: "usd"},
+					},
+				},
+				Rounds: Rounds{{Period: NewPeriod(2020, 1)}},
+			},
+			expectedErr: "invalid eth_tree.denomination in mechanics at period 2020-01",
+		},
+		{
+			name: "valid plan with eth_tree overrides",
+			plan: &Plan{
+				Mechanics: MechanicsList{
+					{
+						Since:    NewPeriod(2020, 1),
+						Criteria: Criteria{MinAttestationsPerDay: 1, MinDecidedsPerDay: 1},
+						Tiers:    Tiers{{MaxEffectiveBalance: precise.NewETH64(32), APRBoost: mustParseETH("0.1")}},
+						ETHTree:  &ETHTreeMechanics{NetworkFeeAddress: ExecutionAddress{1}},
+					},
+				},
+				Rounds: Rounds{
+					{Period: NewPeriod(2020, 1), Budget: mustParseETH("1000"), ETHTree: &ETHTreeRound{NetworkFee: mustParseETH("0.05"), InflationCap: mustParseETH("500")}},
+				},
+			},
+		},
 		{
 			name: "staking_upgrade block must be positive",
 			plan: &Plan{
@@ -395,6 +628,71 @@ func TestPlan_Validate(t *testing.T) {
 	}
 }
 
+func TestRound_WithRollover(t *testing.T) {
+	rollover := mustParseETH("123.456789012345678901").Wei()
+
+	// Rolls over into the budget.
+	round := Round{Period: NewPeriod(2020, 1), Budget: mustParseETH("1000")}
+	require.Equal(t, "1123.456789012345678901", round.WithRollover(rollover).Budget.Display())
+	require.Equal(t, "1000", round.Budget.Display(), "original round must not be modified")
+
+	// Rolls over into the inflation cap.
+	round = Round{Period: NewPeriod(2020, 1), InflationCap: mustParseETH("1000")}
+	require.Equal(t, "1123.456789012345678901", round.WithRollover(rollover).InflationCap.Display())
+
+	// No limit to roll over into.
+	round = Round{Period: NewPeriod(2020, 1)}
+	require.Nil(t, round.WithRollover(rollover).Limit())
+}
+
+func TestRound_WithETHTreeRollover(t *testing.T) {
+	rollover := mustParseETH("10").Wei()
+
+	// Without a separate limit, rolls over into the round's limit.
+	round := Round{Period: NewPeriod(2020, 1), Budget: mustParseETH("1000")}
+	require.Equal(t, "1010", round.WithETHTreeRollover(rollover).Budget.Display())
+
+	// With a separate limit, rolls over into the ETH tree's limit only.
+	round = Round{
+		Period:  NewPeriod(2020, 1),
+		Budget:  mustParseETH("1000"),
+		ETHTree: &ETHTreeRound{InflationCap: mustParseETH("500")},
+	}
+	updated := round.WithETHTreeRollover(rollover)
+	require.Equal(t, "1000", updated.Budget.Display())
+	require.Equal(t, "510", updated.ETHTree.InflationCap.Display())
+	require.Equal(t, "500", round.ETHTree.InflationCap.Display(), "original round must not be modified")
+}
+
+func TestMechanics_ETHTreeTier(t *testing.T) {
+	tier := &Tier{MaxEffectiveBalance: precise.NewETH64(64000), APRBoost: mustParseETH("0.05")}
+
+	// No overrides: same tier.
+	mechanics := &Mechanics{}
+	require.Same(t, tier, mechanics.ETHTreeTier(tier))
+
+	// Multiplier applies to the APR boost only.
+	mechanics = &Mechanics{ETHTree: &ETHTreeMechanics{APRBoostMultiplier: mustParseETH("1.5")}}
+	ethTier := mechanics.ETHTreeTier(tier)
+	require.Equal(t, "0.075", ethTier.APRBoost.Display())
+	require.Equal(t, tier.MaxEffectiveBalance, ethTier.MaxEffectiveBalance)
+	require.Equal(t, "0.05", tier.APRBoost.Display(), "original tier must not be modified")
+}
+
+func TestMechanics_ETHTreeNetworkFeeAddress(t *testing.T) {
+	// No addresses.
+	mechanics := &Mechanics{ETHTree: &ETHTreeMechanics{}}
+	require.Equal(t, ExecutionAddress{}, mechanics.ETHTreeNetworkFeeAddress())
+
+	// Falls back to the mechanics' address.
+	mechanics = &Mechanics{NetworkFeeAddress: ExecutionAddress{1}}
+	require.Equal(t, ExecutionAddress{1}, mechanics.ETHTreeNetworkFeeAddress())
+
+	// The ETH tree's own address takes precedence.
+	mechanics.ETHTree = &ETHTreeMechanics{NetworkFeeAddress: ExecutionAddress{2}}
+	require.Equal(t, ExecutionAddress{2}, mechanics.ETHTreeNetworkFeeAddress())
+}
+
 // Helper function for tests
 func mustParseETH(s string) *precise.ETH {
 	eth, err := precise.ParseETH(s)
@@ -0,0 +1,538 @@
+// Package snapshot exports the synced data of a network to a portable file,
+// and imports it into an empty database, so that rewards can be calculated
+// without syncing.
+//
+// A snapshot is a gzip-compressed tar archive holding a manifest.json followed by
+// a JSON-lines file per table, with a row per line as returned by row_to_json.
+// The manifest records the snapshot's format version, the schema version of the
+// database it was exported from, and the row count and SHA-256 checksum of each
+// table's file.
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// FormatVersion is the version of the snapshot file format.
+const FormatVersion = 2
+
+const (
+	manifestFileName = "manifest.json"
+	dayFormat        = "2006-01-02"
+)
+
+// Manifest describes the contents of a snapshot.
+type Manifest struct {
+	FormatVersion int             `json:"format_version"`
+	SchemaVersion int             `json:"schema_version"`
+	Network       string          `json:"network"`
+	CreatedAt     time.Time       `json:"created_at"`
+	FromDay       string          `json:"from_day,omitempty"`
+	ToDay         string          `json:"to_day,omitempty"`
+	Tables        []TableManifest `json:"tables"`
+}
+
+// TableManifest describes a table's file in a snapshot.
+type TableManifest struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// Options limits the exported data to a range of days. Zero values are unbounded.
+type Options struct {
+	FromDay time.Time
+	ToDay   time.Time
+}
+
+// table is a table included in snapshots, in the order they're imported in.
+type table struct {
+	name string
+
+	// query selects the rows to export as JSON, given the day range as $1 and $2 (NULL if unbounded).
+	query string
+
+	// serial is the table's serial column, whose sequence is advanced after import.
+	serial string
+}
+
+var tables = []table{
+	{
+		name:   "state",
+		query:  "SELECT row_to_json(t) FROM state t ORDER BY id",
+		serial: "id",
+	},
+	{
+		// Events up to the end of the range are needed to know validators' owners and clusters.
+		name:   "contract_events",
+		query:  "SELECT row_to_json(t) FROM contract_events t WHERE $2::date IS NULL OR t.block_time < $2::date + 1 ORDER BY id",
+		serial: "id",
+	},
+	{
+		name:  "validators",
+		query: "SELECT row_to_json(t) FROM validators t ORDER BY public_key",
+	},
+	{
+		name:   "validator_events",
+		query:  "SELECT row_to_json(t) FROM validator_events t WHERE $2::date IS NULL OR t.block_time < $2::date + 1 ORDER BY id",
+		serial: "id",
+	},
+	{
+		name: "validator_performances",
+		query: `SELECT row_to_json(t) FROM validator_performances t
+			WHERE ($1::date IS NULL OR t.day >= $1::date) AND ($2::date IS NULL OR t.day <= $2::date)
+			ORDER BY day, provider, public_key`,
+	},
+	{
+		name: "performance_gaps",
+		query: `SELECT row_to_json(t) FROM performance_gaps t
+			WHERE ($1::date IS NULL OR t.day >= $1::date) AND ($2::date IS NULL OR t.day <= $2::date)
+			ORDER BY day, provider, public_key`,
+	},
+	{
+		name: "quarantined_days",
+		query: `SELECT row_to_json(t) FROM quarantined_days t
+			WHERE ($1::date IS NULL OR t.day >= $1::date) AND ($2::date IS NULL OR t.day <= $2::date)
+			ORDER BY day, provider`,
+	},
+}
+
+// Export writes a snapshot of the database's current schema to w.
+func Export(ctx context.Context, db *sql.DB, w io.Writer, network string, schemaVersion int, opts Options) (*Manifest, error) {
+	if !opts.FromDay.IsZero() && !opts.ToDay.IsZero() && opts.ToDay.Before(opts.FromDay) {
+		return nil, errors.New("to day must not be before from day")
+	}
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		SchemaVersion: schemaVersion,
+		Network:       network,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+		FromDay:       formatDay(opts.FromDay),
+		ToDay:         formatDay(opts.ToDay),
+	}
+
+	// Tables are written to temporary files first, since tar entries must
+	// be preceded by their size, and the manifest by the checksums.
+	tmpDir, err := os.MkdirTemp("", "ssv-rewards-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := make([]*os.File, len(tables))
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+	for i, t := range tables {
+		f, err := os.CreateTemp(tmpDir, t.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		files[i] = f
+
+		tableManifest, err := exportTable(ctx, db, f, t, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", t.name, err)
+		}
+		if t.name == "state" && tableManifest.Rows != 1 {
+			return nil, fmt.Errorf("expected a single state row, got %d: is the network synced?", tableManifest.Rows)
+		}
+		manifest.Tables = append(manifest.Tables, tableManifest)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeTarFile(tw, manifestFileName, int64(len(manifestJSON)), bytes.NewReader(manifestJSON)); err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat temporary file: %w", err)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind temporary file: %w", err)
+		}
+		if err := writeTarFile(tw, manifest.Tables[i].File, info.Size(), f); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return manifest, nil
+}
+
+func exportTable(ctx context.Context, db *sql.DB, w io.Writer, t table, opts Options) (TableManifest, error) {
+	rows, err := db.QueryContext(ctx, t.query, nullDay(opts.FromDay), nullDay(opts.ToDay))
+	if err != nil {
+		return TableManifest{}, err
+	}
+	defer rows.Close()
+
+	hash := sha256.New()
+	buf := bufio.NewWriter(io.MultiWriter(w, hash))
+	count := 0
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return TableManifest{}, err
+		}
+		if t.name == "state" {
+			if row, err = limitState(ctx, db, row, opts); err != nil {
+				return TableManifest{}, err
+			}
+		}
+		if _, err := buf.Write(append(row, '\n')); err != nil {
+			return TableManifest{}, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return TableManifest{}, err
+	}
+	if err := buf.Flush(); err != nil {
+		return TableManifest{}, err
+	}
+	return TableManifest{
+		Name:   t.name,
+		File:   t.name + ".jsonl",
+		Rows:   count,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// limitState adjusts the state of a partial snapshot to the data it holds: the
+// highest synced block becomes the last exported event's, and the range of synced
+// validator performance is limited to the snapshot's days.
+func limitState(ctx context.Context, db *sql.DB, row []byte, opts Options) ([]byte, error) {
+	if opts.FromDay.IsZero() && opts.ToDay.IsZero() {
+		return row, nil
+	}
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal(row, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode state: %w", err)
+	}
+	set := func(key string, value any) error {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		state[key] = encoded
+		return nil
+	}
+
+	if !opts.ToDay.IsZero() {
+		var (
+			lowestBlock, highestBlock int64
+			number                    int64
+			hash                      sql.NullString
+			blockTime                 sql.NullTime
+		)
+		if err := json.Unmarshal(state["lowest_block_number"], &lowestBlock); err != nil {
+			return nil, fmt.Errorf("failed to decode state: %w", err)
+		}
+		if err := json.Unmarshal(state["highest_block_number"], &highestBlock); err != nil {
+			return nil, fmt.Errorf("failed to decode state: %w", err)
+		}
+		err := db.QueryRowContext(
+			ctx,
+			`SELECT block_number, block_hash, block_time FROM contract_events
+			WHERE block_time < $1::date + 1 ORDER BY block_number DESC LIMIT 1`,
+			formatDay(opts.ToDay),
+		).Scan(&number, &hash, &blockTime)
+		if errors.Is(err, sql.ErrNoRows) {
+			number = lowestBlock - 1
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get last exported block: %w", err)
+		}
+		if number < highestBlock {
+			var hashValue, timeValue any
+			if hash.Valid {
+				hashValue = hash.String
+			}
+			if blockTime.Valid {
+				timeValue = blockTime.Time.UTC().Format("2006-01-02T15:04:05")
+			}
+			if err := errors.Join(
+				set("highest_block_number", number),
+				set("highest_block_hash", hashValue),
+				set("highest_block_time", timeValue),
+			); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Dates are compared as strings, since they're in ISO 8601 format.
+	var earliest, latest *string
+	_ = json.Unmarshal(state["earliest_validator_performance"], &earliest)
+	_ = json.Unmarshal(state["latest_validator_performance"], &latest)
+	if from := formatDay(opts.FromDay); from != "" && earliest != nil && *earliest < from {
+		earliest = &from
+	}
+	if to := formatDay(opts.ToDay); to != "" && latest != nil && *latest > to {
+		latest = &to
+	}
+	if err := errors.Join(
+		set("earliest_validator_performance", earliest),
+		set("latest_validator_performance", latest),
+	); err != nil {
+		return nil, err
+	}
+	return json.Marshal(state)
+}
+
+// importBatchSize is the number of rows handled, and inserted, at a time.
+const importBatchSize = 1000
+
+// ReadManifest reads the manifest at the start of a snapshot.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer gz.Close()
+	return readManifest(tar.NewReader(gz))
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if header.Name != manifestFileName {
+		return nil, fmt.Errorf("snapshot must start with %s, got %s", manifestFileName, header.Name)
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d, want %d", manifest.FormatVersion, FormatVersion)
+	}
+	if len(manifest.Tables) != len(tables) {
+		return nil, fmt.Errorf("snapshot has %d tables, want %d", len(manifest.Tables), len(tables))
+	}
+	for i, t := range tables {
+		if manifest.Tables[i].Name != t.name {
+			return nil, fmt.Errorf("snapshot table %d is %s, want %s", i, manifest.Tables[i].Name, t.name)
+		}
+	}
+	return &manifest, nil
+}
+
+// Import loads a snapshot of the given network into the database's current schema,
+// which must be migrated to the snapshot's schema version and hold no synced data.
+// All tables are loaded in a single transaction, which is rolled back if any
+// table's checksum or row count doesn't match the manifest.
+func Import(ctx context.Context, db *sql.DB, r io.Reader, network string, schemaVersion int) (*Manifest, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	check := func(manifest *Manifest) error {
+		if err := CheckManifest(manifest, network, schemaVersion); err != nil {
+			return err
+		}
+		for _, t := range tables {
+			var empty bool
+			if err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT NOT EXISTS (SELECT 1 FROM %s)", t.name)).Scan(&empty); err != nil {
+				return fmt.Errorf("failed to check %s: %w", t.name, err)
+			}
+			if !empty {
+				return fmt.Errorf("table %s is not empty: snapshots can only be imported into an empty database", t.name)
+			}
+		}
+		return nil
+	}
+	insert := func(table string, rows []json.RawMessage) error {
+		data, err := json.Marshal(rows)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(
+			"INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, $1::json)",
+			table,
+		), string(data))
+		return err
+	}
+	manifest, err := Read(r, check, insert)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tables {
+		if t.serial == "" {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE(MAX(%[2]s), 0) + 1, false) FROM %[1]s",
+			t.name, t.serial,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to advance sequence of %s: %w", t.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return manifest, nil
+}
+
+// CheckManifest verifies that a snapshot is of the given network and schema version.
+func CheckManifest(manifest *Manifest, network string, schemaVersion int) error {
+	if manifest.Network != network {
+		return fmt.Errorf("snapshot is of network %s, want %s", manifest.Network, network)
+	}
+	if manifest.SchemaVersion != schemaVersion {
+		return fmt.Errorf(
+			"snapshot was exported at schema version %d, but the database is at version %d: use a binary of the same version",
+			manifest.SchemaVersion, schemaVersion,
+		)
+	}
+	return nil
+}
+
+// Read reads a snapshot, calling check with its manifest before reading any table,
+// and handle with batches of each table's rows, in the order of the manifest.
+// A table's checksum and row count are verified once all of its rows have been
+// handled, so callers should load the rows in a transaction that is only committed
+// once Read succeeds. The slice of rows is reused once handle returns.
+func Read(
+	r io.Reader,
+	check func(manifest *Manifest) error,
+	handle func(table string, rows []json.RawMessage) error,
+) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	manifest, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(manifest); err != nil {
+		return nil, err
+	}
+
+	for _, tableManifest := range manifest.Tables {
+		header, err := tr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", tableManifest.File, err)
+		}
+		if header.Name != tableManifest.File {
+			return nil, fmt.Errorf("expected %s in snapshot, got %s", tableManifest.File, header.Name)
+		}
+		if err := readTable(tr, tableManifest, handle); err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", tableManifest.Name, err)
+		}
+	}
+	return manifest, nil
+}
+
+func readTable(r io.Reader, tableManifest TableManifest, handle func(table string, rows []json.RawMessage) error) error {
+	flush := func(batch []json.RawMessage) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		return handle(tableManifest.Name, batch)
+	}
+
+	hash := sha256.New()
+	scanner := bufio.NewScanner(io.TeeReader(r, hash))
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	var (
+		batch []json.RawMessage
+		count int
+	)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !json.Valid(line) {
+			return fmt.Errorf("invalid row %d", count+1)
+		}
+		batch = append(batch, json.RawMessage(append([]byte(nil), line...)))
+		count++
+		if len(batch) == importBatchSize {
+			if err := flush(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := flush(batch); err != nil {
+		return err
+	}
+
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != tableManifest.SHA256 {
+		return fmt.Errorf("checksum mismatch: got %s, manifest has %s", checksum, tableManifest.SHA256)
+	}
+	if count != tableManifest.Rows {
+		return fmt.Errorf("row count mismatch: got %d, manifest has %d", count, tableManifest.Rows)
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func formatDay(day time.Time) string {
+	if day.IsZero() {
+		return ""
+	}
+	return day.UTC().Format(dayFormat)
+}
+
+func nullDay(day time.Time) sql.NullString {
+	if day.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatDay(day), Valid: true}
+}
@@ -0,0 +1,177 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildSnapshot(t *testing.T, files map[string][]byte, order ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range order {
+		require.NoError(t, writeTarFile(tw, name, int64(len(files[name])), bytes.NewReader(files[name])))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func validManifest() Manifest {
+	manifest := Manifest{FormatVersion: FormatVersion, SchemaVersion: 4, Network: "mainnet"}
+	for _, t := range tables {
+		manifest.Tables = append(manifest.Tables, TableManifest{Name: t.name, File: t.name + ".jsonl"})
+	}
+	return manifest
+}
+
+func TestReadManifest(t *testing.T) {
+	encode := func(manifest Manifest) []byte {
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		data := buildSnapshot(t, map[string][]byte{manifestFileName: encode(validManifest())}, manifestFileName)
+		manifest, err := ReadManifest(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, "mainnet", manifest.Network)
+		require.Len(t, manifest.Tables, len(tables))
+	})
+
+	t.Run("unsupported format version", func(t *testing.T) {
+		manifest := validManifest()
+		manifest.FormatVersion = FormatVersion + 1
+		data := buildSnapshot(t, map[string][]byte{manifestFileName: encode(manifest)}, manifestFileName)
+		_, err := ReadManifest(bytes.NewReader(data))
+		require.ErrorContains(t, err, "unsupported snapshot format version")
+	})
+
+	t.Run("tables out of order", func(t *testing.T) {
+		manifest := validManifest()
+		manifest.Tables[0], manifest.Tables[1] = manifest.Tables[1], manifest.Tables[0]
+		data := buildSnapshot(t, map[string][]byte{manifestFileName: encode(manifest)}, manifestFileName)
+		_, err := ReadManifest(bytes.NewReader(data))
+		require.ErrorContains(t, err, "want state")
+	})
+
+	t.Run("manifest not first", func(t *testing.T) {
+		data := buildSnapshot(t, map[string][]byte{
+			"state.jsonl":    []byte("{}\n"),
+			manifestFileName: encode(validManifest()),
+		}, "state.jsonl", manifestFileName)
+		_, err := ReadManifest(bytes.NewReader(data))
+		require.ErrorContains(t, err, "must start with manifest.json")
+	})
+
+	t.Run("not gzip", func(t *testing.T) {
+		_, err := ReadManifest(bytes.NewReader([]byte("not a snapshot")))
+		require.ErrorContains(t, err, "failed to decompress")
+	})
+}
+
+func TestRead(t *testing.T) {
+	build := func(rows map[string][]string, tamper string) []byte {
+		manifest := validManifest()
+		files := map[string][]byte{}
+		order := []string{manifestFileName}
+		for i, table := range manifest.Tables {
+			var data []byte
+			for _, row := range rows[table.Name] {
+				data = append(data, row+"\n"...)
+			}
+			sum := sha256.Sum256(data)
+			manifest.Tables[i].Rows = len(rows[table.Name])
+			manifest.Tables[i].SHA256 = hex.EncodeToString(sum[:])
+			if table.Name == tamper {
+				data = append(data, "{}\n"...)
+			}
+			files[table.File] = data
+			order = append(order, table.File)
+		}
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		files[manifestFileName] = data
+		return buildSnapshot(t, files, order...)
+	}
+	rows := map[string][]string{
+		"state":                  {`{"id":1,"network_name":"mainnet"}`},
+		"validator_performances": {`{"day":"2024-01-01"}`, `{"day":"2024-01-02"}`},
+		"performance_gaps":       {`{"day":"2024-01-02"}`},
+		"quarantined_days":       {`{"day":"2024-01-02"}`},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		handled := map[string][]string{}
+		manifest, err := Read(
+			bytes.NewReader(build(rows, "")),
+			func(manifest *Manifest) error { return CheckManifest(manifest, "mainnet", 4) },
+			func(table string, batch []json.RawMessage) error {
+				for _, row := range batch {
+					handled[table] = append(handled[table], string(row))
+				}
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, rows, handled)
+		require.Equal(t, 2, manifest.Tables[len(manifest.Tables)-3].Rows)
+	})
+
+	t.Run("wrong network", func(t *testing.T) {
+		_, err := Read(
+			bytes.NewReader(build(rows, "")),
+			func(manifest *Manifest) error { return CheckManifest(manifest, "holesky", 4) },
+			func(string, []json.RawMessage) error { return nil },
+		)
+		require.ErrorContains(t, err, "snapshot is of network mainnet, want holesky")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, err := Read(
+			bytes.NewReader(build(rows, "validator_performances")),
+			func(*Manifest) error { return nil },
+			func(string, []json.RawMessage) error { return nil },
+		)
+		require.ErrorContains(t, err, "failed to import validator_performances: checksum mismatch")
+	})
+}
+
+func TestLimitState(t *testing.T) {
+	row := []byte(`{"id":1,"network_name":"mainnet","earliest_validator_performance":"2023-07-01","latest_validator_performance":"2024-03-31"}`)
+	day := func(s string) time.Time {
+		d, err := time.Parse(dayFormat, s)
+		require.NoError(t, err)
+		return d
+	}
+
+	// Without a range, the state is exported as is.
+	limited, err := limitState(context.Background(), nil, row, Options{})
+	require.NoError(t, err)
+	require.Equal(t, row, limited)
+
+	// The earliest day is only raised, never lowered.
+	limited, err = limitState(context.Background(), nil, row, Options{FromDay: day("2024-01-01")})
+	require.NoError(t, err)
+	var state map[string]any
+	require.NoError(t, json.Unmarshal(limited, &state))
+	require.Equal(t, "2024-01-01", state["earliest_validator_performance"])
+	require.Equal(t, "2024-03-31", state["latest_validator_performance"])
+	require.Equal(t, "mainnet", state["network_name"])
+
+	limited, err = limitState(context.Background(), nil, row, Options{FromDay: day("2023-01-01")})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(limited, &state))
+	require.Equal(t, "2023-07-01", state["earliest_validator_performance"])
+}
@@ -0,0 +1,174 @@
+// Package status reports the health of the synced data, such as days with missing
+// or incomplete validator performance, which are otherwise found with ad-hoc SQL.
+package status
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+)
+
+// PerformanceDay is the validator performance of a provider in a day.
+type PerformanceDay struct {
+	Provider models.ProviderType
+	Day      time.Time
+
+	// Rows is the number of validator_performances rows, of which MissingAttestations
+	// and MissingDecideds have no attestations or decided counts.
+	Rows                int
+	MissingAttestations int
+	MissingDecideds     int
+
+	// Gaps is the number of unresolved performance gaps.
+	Gaps int
+
+	// Active is the number of validators registered in SSV at the end of the day,
+	// if known.
+	Active null.Int
+}
+
+// Anomalies returns what's wrong with the day, if anything.
+func (d *PerformanceDay) Anomalies() []string {
+	if d.Rows == 0 {
+		return []string{"not synced"}
+	}
+	var anomalies []string
+	if d.Active.Valid && d.Rows != d.Active.Int {
+		anomalies = append(anomalies, fmt.Sprintf("%d rows for %d active validators", d.Rows, d.Active.Int))
+	}
+	if d.Gaps > 0 {
+		anomalies = append(anomalies, fmt.Sprintf("%d unresolved performance gaps", d.Gaps))
+	}
+	if d.MissingDecideds == d.Rows {
+		anomalies = append(anomalies, "no decided counts")
+	}
+	return anomalies
+}
+
+// PerformanceDays returns the validator performance of each provider in each day from
+// its earliest to its latest synced day, including days in between that weren't
+// synced, ordered by provider and day. Zero days aren't limited.
+func PerformanceDays(ctx context.Context, db *sql.DB, fromDay, toDay time.Time) ([]*PerformanceDay, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if !fromDay.IsZero() {
+		args = append(args, fromDay)
+		conditions = append(conditions, fmt.Sprintf("days.day >= $%d", len(args)))
+	}
+	if !toDay.IsZero() {
+		args = append(args, toDay)
+		conditions = append(conditions, fmt.Sprintf("days.day <= $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		WITH counts AS (
+			SELECT provider, day,
+				COUNT(*) AS row_count,
+				COUNT(*) FILTER (WHERE attestations_executed IS NULL) AS missing_attestations,
+				COUNT(*) FILTER (WHERE decideds IS NULL) AS missing_decideds
+			FROM validator_performances
+			GROUP BY provider, day
+		), days AS (
+			SELECT provider, generate_series(MIN(day), MAX(day), '1 day')::date AS day
+			FROM counts
+			GROUP BY provider
+		), gaps AS (
+			SELECT provider, day, COUNT(*) AS gaps
+			FROM performance_gaps
+			WHERE resolved_at IS NULL
+			GROUP BY provider, day
+		)
+		SELECT days.provider, days.day,
+			COALESCE(counts.row_count, 0),
+			COALESCE(counts.missing_attestations, 0),
+			COALESCE(counts.missing_decideds, 0),
+			COALESCE(gaps.gaps, 0)
+		FROM days
+		LEFT JOIN counts USING (provider, day)
+		LEFT JOIN gaps USING (provider, day)
+		`+where+`
+		ORDER BY days.provider, days.day`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count validator performances: %w", err)
+	}
+	defer rows.Close()
+	var days []*PerformanceDay
+	for rows.Next() {
+		d := &PerformanceDay{}
+		if err := rows.Scan(
+			&d.Provider, &d.Day, &d.Rows, &d.MissingAttestations, &d.MissingDecideds, &d.Gaps,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan validator performance counts: %w", err)
+		}
+		d.Day = d.Day.UTC()
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// EventErrors is the number of contract events of a name that failed to be handled.
+type EventErrors struct {
+	EventName string
+	Count     int
+}
+
+// ContractEventErrors returns the number of contract events with an error by event
+// name, ordered by event name.
+func ContractEventErrors(ctx context.Context, db *sql.DB) ([]EventErrors, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT event_name, COUNT(*)
+		FROM contract_events
+		WHERE error IS NOT NULL
+		GROUP BY event_name
+		ORDER BY event_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count contract event errors: %w", err)
+	}
+	defer rows.Close()
+	var eventErrors []EventErrors
+	for rows.Next() {
+		var e EventErrors
+		if err := rows.Scan(&e.EventName, &e.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan contract event errors: %w", err)
+		}
+		eventErrors = append(eventErrors, e)
+	}
+	return eventErrors, rows.Err()
+}
+
+// ValidatorIndices is the number of validators without a Beacon chain index.
+type ValidatorIndices struct {
+	Validators         int
+	WithoutIndex       int
+	ActiveWithoutIndex int
+}
+
+// MissingValidatorIndices returns the number of validators without a Beacon chain index.
+func MissingValidatorIndices(ctx context.Context, db *sql.DB) (ValidatorIndices, error) {
+	var v ValidatorIndices
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE index IS NULL),
+			COUNT(*) FILTER (WHERE index IS NULL AND active)
+		FROM validators`,
+	).Scan(&v.Validators, &v.WithoutIndex, &v.ActiveWithoutIndex)
+	if err != nil {
+		return ValidatorIndices{}, fmt.Errorf("failed to count validators without index: %w", err)
+	}
+	return v, nil
+}
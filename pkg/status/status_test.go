@@ -0,0 +1,21 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+)
+
+func TestPerformanceDayAnomalies(t *testing.T) {
+	require.Equal(t, []string{"not synced"}, (&PerformanceDay{Active: null.IntFrom(10)}).Anomalies())
+	require.Empty(t, (&PerformanceDay{Rows: 10, MissingDecideds: 2}).Anomalies())
+	require.Empty(t, (&PerformanceDay{Rows: 10, Active: null.IntFrom(10)}).Anomalies())
+
+	anomalies := (&PerformanceDay{Rows: 8, MissingDecideds: 8, Gaps: 3, Active: null.IntFrom(10)}).Anomalies()
+	require.Equal(t, []string{
+		"8 rows for 10 active validators",
+		"3 unresolved performance gaps",
+		"no decided counts",
+	}, anomalies)
+}
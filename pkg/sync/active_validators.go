@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/ssv/eth/eventparser"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+)
+
+// ActiveValidatorsByDay returns the number of validators registered in SSV at the end
+// of each day between the given days (inclusive), which is the number of rows that
+// SyncValidatorPerformance stores for the day.
+func ActiveValidatorsByDay(
+	ctx context.Context,
+	db *sql.DB,
+	spec beacon.Spec,
+	fromDay, toDay time.Time,
+) (map[time.Time]int, error) {
+	validatorEvents, err := models.ValidatorEvents(
+		models.ValidatorEventWhere.EventName.IN([]string{
+			eventparser.ValidatorAdded,
+			eventparser.ValidatorRemoved,
+		}),
+		qm.OrderBy("block_number, log_index"),
+	).All(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator events: %w", err)
+	}
+
+	active := map[string]bool{}
+	counts := map[time.Time]int{}
+	pos := 0
+	for day := fromDay; !day.After(toDay); day = day.AddDate(0, 0, 1) {
+		_, toEpoch := spec.DayEpochs(day)
+		lastSlot := spec.LastSlot(toEpoch)
+		for ; pos < len(validatorEvents); pos++ {
+			event := validatorEvents[pos]
+			if phase0.Slot(event.Slot) > lastSlot {
+				break
+			}
+			if event.EventName == eventparser.ValidatorAdded {
+				active[event.PublicKey] = true
+			} else {
+				delete(active, event.PublicKey)
+			}
+		}
+		counts[day] = len(active)
+	}
+	return counts, nil
+}
@@ -0,0 +1,137 @@
+// Package cache stores data fetched from external APIs in an embedded key-value store,
+// keyed by provider, index and day, so that a single day can be looked up without
+// loading the rest of an index's history.
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+	"go.uber.org/zap"
+)
+
+// Cache is a key-value cache backed by badger, which compresses its data on disk.
+type Cache struct {
+	db basedb.Database
+}
+
+// New opens the cache at the given directory, creating it if it doesn't exist.
+func New(logger *zap.Logger, path string) (*Cache, error) {
+	db, err := kv.New(logger, basedb.Options{
+		Path:       path,
+		GCInterval: 10 * time.Minute,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache: %w", err)
+	}
+	return &Cache{db: db}, nil
+}
+
+// NewInMemory creates a cache that isn't persisted.
+func NewInMemory(logger *zap.Logger) (*Cache, error) {
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache: %w", err)
+	}
+	return &Cache{db: db}, nil
+}
+
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Entry is the value of an index on a day.
+type Entry struct {
+	Day   time.Time
+	Value any
+}
+
+type item struct {
+	Fetched time.Time       `json:"fetched"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// Get decodes the value of an index on a day into v, unless v is nil, and returns
+// when the value was fetched.
+func (c *Cache) Get(provider string, index uint64, day time.Time, v any) (fetched time.Time, found bool, err error) {
+	obj, found, err := c.db.Get(nil, key(provider, index, day))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get %s cache: %w", provider, err)
+	}
+	if !found {
+		return time.Time{}, false, nil
+	}
+	var it item
+	if err := json.Unmarshal(obj.Value, &it); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to decode %s cache: %w", provider, err)
+	}
+	if v != nil {
+		if err := json.Unmarshal(it.Value, v); err != nil {
+			return time.Time{}, false, fmt.Errorf("failed to decode %s cache: %w", provider, err)
+		}
+	}
+	return it.Fetched, true, nil
+}
+
+// Set stores the values of an index in a single transaction, along with when they
+// were fetched. Index is 0 for values that aren't per index.
+func (c *Cache) Set(provider string, index uint64, fetched time.Time, entries ...Entry) error {
+	txn := c.db.Begin()
+	defer txn.Discard()
+	for _, entry := range entries {
+		value, err := json.Marshal(entry.Value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s cache: %w", provider, err)
+		}
+		data, err := json.Marshal(item{Fetched: fetched, Value: value})
+		if err != nil {
+			return fmt.Errorf("failed to encode %s cache: %w", provider, err)
+		}
+		if err := txn.Set(nil, key(provider, index, entry.Day), data); err != nil {
+			return fmt.Errorf("failed to set %s cache: %w", provider, err)
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s cache: %w", provider, err)
+	}
+	return nil
+}
+
+// Delete removes the values of an index on the given days in a single transaction.
+func (c *Cache) Delete(provider string, index uint64, days ...time.Time) error {
+	txn := c.db.Begin()
+	defer txn.Discard()
+	for _, day := range days {
+		if err := txn.Delete(nil, key(provider, index, day)); err != nil {
+			return fmt.Errorf("failed to delete %s cache: %w", provider, err)
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s cache: %w", provider, err)
+	}
+	return nil
+}
+
+// Count returns the number of values of a provider.
+func (c *Cache) Count(provider string) (int64, error) {
+	n, err := c.db.CountPrefix(append([]byte(provider), '/'))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count %s cache: %w", provider, err)
+	}
+	return n, nil
+}
+
+// key is the provider followed by a separator, the index and the number of days since
+// the Unix epoch, so that an index's days are sorted.
+func key(provider string, index uint64, day time.Time) []byte {
+	k := make([]byte, 0, len(provider)+1+8+8)
+	k = append(k, provider...)
+	k = append(k, '/')
+	k = binary.BigEndian.AppendUint64(k, index)
+	days := day.UTC().Truncate(24*time.Hour).Unix() / (24 * 60 * 60)
+	return binary.BigEndian.AppendUint64(k, uint64(days))
+}
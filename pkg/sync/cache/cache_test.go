@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCache(t *testing.T) {
+	c, err := NewInMemory(zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	fetched := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	type value struct{ Missed int }
+
+	require.NoError(t, c.Set("beaconcha", 1, fetched,
+		Entry{Day: day1, Value: value{Missed: 1}},
+		Entry{Day: day2, Value: value{Missed: 2}},
+		Entry{Day: time.Time{}},
+	))
+
+	// Days are looked up by date, regardless of the time of day.
+	var v value
+	gotFetched, found, err := c.Get("beaconcha", 1, day2.Add(12*time.Hour+23*time.Second), &v)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, fetched.Equal(gotFetched))
+	require.Equal(t, value{Missed: 2}, v)
+
+	_, found, err = c.Get("beaconcha", 1, time.Time{}, nil)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// Keys are scoped by provider, index and day.
+	for _, k := range []struct {
+		provider string
+		index    uint64
+		day      time.Time
+	}{
+		{"ssv", 1, day1},
+		{"beaconcha", 2, day1},
+		{"beaconcha", 1, day2.AddDate(0, 0, 1)},
+	} {
+		_, found, err = c.Get(k.provider, k.index, k.day, &v)
+		require.NoError(t, err)
+		require.False(t, found)
+	}
+
+	// Values are overwritten.
+	require.NoError(t, c.Set("beaconcha", 1, fetched.Add(time.Hour), Entry{Day: day1, Value: value{Missed: 3}}))
+	gotFetched, _, err = c.Get("beaconcha", 1, day1, &v)
+	require.NoError(t, err)
+	require.True(t, fetched.Add(time.Hour).Equal(gotFetched))
+	require.Equal(t, value{Missed: 3}, v)
+
+	// Deleted days are missing, while the index's other days are kept.
+	require.NoError(t, c.Delete("beaconcha", 1, day1, day2.AddDate(0, 0, 1)))
+	_, found, err = c.Get("beaconcha", 1, day1, nil)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = c.Get("beaconcha", 1, day2, nil)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// Values are counted by provider.
+	n, err := c.Count("beaconcha")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	n, err = c.Count("ssv")
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -6,14 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
-	"time"
 
 	"github.com/bloxapp/ssv/eth/eventparser"
-	"github.com/bloxapp/ssv/eth/executionclient"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/schollz/progressbar/v3"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"go.uber.org/zap"
 
@@ -28,7 +27,7 @@ func SyncContractEvents(
 	logger *zap.Logger,
 	spec beacon.Spec,
 	eventParser *eventparser.EventParser,
-	el *executionclient.ExecutionClient,
+	source ContractLogSource,
 	db *sql.DB,
 	fromBlock, toBlock uint64,
 ) error {
@@ -47,25 +46,25 @@ func SyncContractEvents(
 	bar := progressbar.New(int(toBlock-fromBlock) + 1)
 	bar.Describe("Fetching events")
 	defer bar.Clear()
-	logs, errs := el.FetchLogs(ctx, fromBlock, toBlock)
-FetchEvents:
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case blockLogs, ok := <-logs:
-			if !ok {
-				break FetchEvents
+	err := source.Fetch(ctx, fromBlock, toBlock, func(chunk LogChunk) error {
+		// Blocks are inserted in order, each checkpointing the state,
+		// so that an interrupted sync resumes after the last inserted block.
+		for _, block := range chunk.Blocks {
+			if err := insertContractEvents(ctx, logger, spec, db, eventParser, block.Block, block.Logs); err != nil {
+				return fmt.Errorf("failed to insert contract events for block %d: %w", block.Block.Number, err)
 			}
-			if err := insertContractEvents(ctx, logger, spec, db, eventParser, el, blockLogs.BlockNumber, blockLogs.Logs); err != nil {
-				return fmt.Errorf("failed to insert contract events for block %d: %w", blockLogs.BlockNumber, err)
+			totalEvents += len(block.Logs)
+		}
+		if len(chunk.Blocks) == 0 || chunk.Blocks[len(chunk.Blocks)-1].Block.Number != chunk.ToBlock {
+			if err := insertContractEvents(ctx, logger, spec, db, eventParser, chunk.End, nil); err != nil {
+				return fmt.Errorf("failed to update state for block %d: %w", chunk.ToBlock, err)
 			}
-			totalEvents += len(blockLogs.Logs)
-			bar.Set(int(blockLogs.BlockNumber-fromBlock) + 1)
 		}
-	}
-	if err := <-errs; err != nil {
-		return fmt.Errorf("failed to fetch logs: %w", err)
+		bar.Set(int(chunk.ToBlock-fromBlock) + 1)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	bar.Clear()
 	logger.Info("Fetched events",
@@ -76,24 +75,20 @@ FetchEvents:
 	return nil
 }
 
+// insertContractEvents inserts the logs of a single block and advances the
+// state to it. The block's hash is recorded in the state even without logs,
+// so that reorgs of the highest synced block can be detected, along with its
+// time, so that it's known without an execution node.
 func insertContractEvents(
 	ctx context.Context,
 	logger *zap.Logger,
 	spec beacon.Spec,
 	db *sql.DB,
 	eventParser *eventparser.EventParser,
-	el *executionclient.ExecutionClient,
-	blockNumber uint64,
+	block Block,
 	logs []types.Log,
 ) error {
-	var blockTime time.Time
-	if len(logs) > 0 {
-		block, err := el.RPC().BlockByNumber(ctx, big.NewInt(int64(blockNumber)))
-		if err != nil {
-			return fmt.Errorf("failed to get block: %w", err)
-		}
-		blockTime = time.Unix(int64(block.Time()), 0).UTC()
-	}
+	blockTime := block.Time
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -136,7 +131,15 @@ func insertContractEvents(
 		}
 	}
 
-	_, err = models.States().UpdateAll(ctx, tx, models.M{"highest_block_number": int(blockNumber)})
+	var blockHash null.String
+	if block.Hash != (common.Hash{}) {
+		blockHash = null.StringFrom(block.Hash.String())
+	}
+	_, err = models.States().UpdateAll(ctx, tx, models.M{
+		models.StateColumns.HighestBlockNumber: int(block.Number),
+		models.StateColumns.HighestBlockHash:   blockHash,
+		models.StateColumns.HighestBlockTime:   blockTime,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update state: %w", err)
 	}
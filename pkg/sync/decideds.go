@@ -0,0 +1,152 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+)
+
+// DecidedsCheck configures the checks of the decided counts of a source, which are
+// trusted and cached only if they pass.
+type DecidedsCheck struct {
+	// MinCoverage is the minimum share of the expected validators with a decided count.
+	MinCoverage float64
+
+	// MaxDrift is the maximum drop in coverage from the previous day.
+	MaxDrift float64
+
+	// Quarantine leaves the decided counts of a day that fails the checks unset,
+	// instead of failing the sync. The day is recorded in quarantined_days, which
+	// calc refuses, and is fetched again by the next sync.
+	Quarantine bool
+
+	// VerifyCache re-fetches cached decided counts and compares them to the cache.
+	VerifyCache bool
+}
+
+// decidedsCoverage is how much of the expected validators a day's decided counts cover.
+type decidedsCoverage struct {
+	Expected int
+	Covered  int
+	NonZero  int
+}
+
+func (c decidedsCoverage) Share() float64 {
+	if c.Expected == 0 {
+		return 1
+	}
+	return float64(c.Covered) / float64(c.Expected)
+}
+
+// coverage returns the coverage of the given validators, by hex-encoded public key.
+func (c DecidedsCheck) coverage(decideds map[string]int, expected []string) decidedsCoverage {
+	coverage := decidedsCoverage{Expected: len(expected)}
+	for _, pubKey := range expected {
+		if n, ok := decideds[pubKey]; ok {
+			coverage.Covered++
+			if n > 0 {
+				coverage.NonZero++
+			}
+		}
+	}
+	return coverage
+}
+
+// problems returns why a day's coverage can't be trusted, compared to the previous
+// trusted day's coverage if any.
+func (c DecidedsCheck) problems(coverage decidedsCoverage, previous *decidedsCoverage) []string {
+	if coverage.Expected == 0 {
+		return nil
+	}
+	var problems []string
+	if coverage.NonZero == 0 {
+		problems = append(problems, "no validator has a decided count above zero")
+	}
+	if coverage.Share() < c.MinCoverage {
+		problems = append(problems, fmt.Sprintf(
+			"%.2f%% of validators have a decided count, below the minimum of %.2f%%",
+			coverage.Share()*100, c.MinCoverage*100,
+		))
+	}
+	if previous != nil && c.MaxDrift > 0 {
+		if drift := previous.Share() - coverage.Share(); drift > c.MaxDrift {
+			problems = append(problems, fmt.Sprintf(
+				"coverage dropped by %.2f%% from the previous day, more than the maximum of %.2f%%",
+				drift*100, c.MaxDrift*100,
+			))
+		}
+	}
+	return problems
+}
+
+// compareDecideds returns the number of validators whose decided counts differ,
+// including validators missing from either.
+func compareDecideds(a, b map[string]int) int {
+	differences := 0
+	for pubKey, n := range a {
+		if m, ok := b[pubKey]; !ok || m != n {
+			differences++
+		}
+	}
+	for pubKey := range b {
+		if _, ok := a[pubKey]; !ok {
+			differences++
+		}
+	}
+	return differences
+}
+
+// recordQuarantine records a day whose decided counts failed the checks with the given
+// problems, or releases the day from quarantine if there are none.
+func recordQuarantine(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	provider models.ProviderType,
+	day time.Time,
+	source decideds.SourceType,
+	problems []string,
+) error {
+	if len(problems) == 0 {
+		_, err := models.QuarantinedDays(
+			models.QuarantinedDayWhere.Provider.EQ(provider),
+			models.QuarantinedDayWhere.Day.EQ(day),
+		).DeleteAll(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to release quarantined day: %w", err)
+		}
+		return nil
+	}
+	row := models.QuarantinedDay{
+		Provider:       provider,
+		Day:            day,
+		DecidedsSource: models.DecidedsSource(source),
+		Problems:       strings.Join(problems, "; "),
+		QuarantinedAt:  time.Now(),
+	}
+	err := row.Upsert(
+		ctx,
+		tx,
+		true,
+		[]string{
+			models.QuarantinedDayColumns.Provider,
+			models.QuarantinedDayColumns.Day,
+		},
+		boil.Whitelist(
+			models.QuarantinedDayColumns.DecidedsSource,
+			models.QuarantinedDayColumns.Problems,
+			models.QuarantinedDayColumns.QuarantinedAt,
+		),
+		boil.Infer(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record quarantined day: %w", err)
+	}
+	return nil
+}
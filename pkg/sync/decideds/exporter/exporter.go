@@ -0,0 +1,83 @@
+// Package exporter provides decided counts from the HTTP API of a self-hosted
+// SSV node running as an exporter, which records the decided duties of all validators.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/carlmjohnson/requests"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/httpretry"
+)
+
+const (
+	SourceType decideds.SourceType = "exporter"
+
+	// role is the role of the counted duties. Only attestations are counted, like
+	// the SSV API's duty counts that the rewards criteria are calibrated to.
+	role = "ATTESTER"
+)
+
+type Client struct {
+	endpoint string
+}
+
+func New(endpoint string) *Client {
+	return &Client{endpoint: endpoint}
+}
+
+func (c *Client) Type() decideds.SourceType {
+	return SourceType
+}
+
+// Decideds counts the slots in the epoch range that validators decided an attestation in.
+func (c *Client) Decideds(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+) (map[string]int, error) {
+	var resp struct {
+		Data []struct {
+			PublicKey string `json:"public_key"`
+			Role      string `json:"role"`
+			Slot      uint64 `json:"slot"`
+		} `json:"data"`
+	}
+	err := requests.URL(c.endpoint).
+		Client(httpretry.Client).
+		Path("/v1/exporter/decideds").
+		Param("from", strconv.FormatUint(uint64(spec.FirstSlot(fromEpoch)), 10)).
+		Param("to", strconv.FormatUint(uint64(spec.LastSlot(toEpoch)), 10)).
+		Param("roles", role).
+		ToJSON(&resp).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decideds from exporter: %w", err)
+	}
+
+	slots := map[string]map[uint64]struct{}{}
+	for _, d := range resp.Data {
+		if d.Role != role {
+			continue
+		}
+		pubKey := decideds.PublicKey(d.PublicKey)
+		if slots[pubKey] == nil {
+			slots[pubKey] = map[uint64]struct{}{}
+		}
+		slots[pubKey][d.Slot] = struct{}{}
+	}
+	counts := make(map[string]int, len(slots))
+	for pubKey, s := range slots {
+		counts[pubKey] = len(s)
+	}
+	return counts, nil
+}
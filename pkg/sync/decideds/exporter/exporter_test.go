@@ -0,0 +1,38 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+)
+
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/exporter/decideds", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "320", r.URL.Query().Get("from"))
+		require.Equal(t, "383", r.URL.Query().Get("to"))
+		require.Equal(t, "ATTESTER", r.URL.Query().Get("roles"))
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": []any{
+			map[string]any{"public_key": "0xAB", "role": "ATTESTER", "slot": 320},
+			map[string]any{"public_key": "ab", "role": "ATTESTER", "slot": 321},
+			map[string]any{"public_key": "ab", "role": "ATTESTER", "slot": 321}, // Duplicate.
+			map[string]any{"public_key": "cd", "role": "ATTESTER", "slot": 330},
+			map[string]any{"public_key": "cd", "role": "PROPOSER", "slot": 331},
+		}}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second}
+	counts, err := New(server.URL).Decideds(context.Background(), zap.NewNop(), spec, time.Time{}, 10, 11)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"ab": 2, "cd": 1}, counts)
+}
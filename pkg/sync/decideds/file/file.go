@@ -0,0 +1,155 @@
+// Package file provides decided counts from a directory of files per day, such as
+// counts exported from another source or prepared for a backfill.
+//
+// Each day is in a file named after it in YYYY-MM-DD format, either:
+//
+//	YYYY-MM-DD.csv   With a header row of public_key and decideds.
+//	YYYY-MM-DD.json  An object of decided counts by public key.
+//
+// Public keys are hex-encoded, with or without a 0x prefix.
+package file
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+)
+
+const (
+	SourceType decideds.SourceType = "file"
+)
+
+type Client struct {
+	dir string
+}
+
+// New reads decided counts from the given directory.
+func New(dir string) (*Client, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &Client{dir: dir}, nil
+}
+
+func (c *Client) Type() decideds.SourceType {
+	return SourceType
+}
+
+// Local marks the files as local data, which isn't cached.
+func (c *Client) Local() {}
+
+// Decideds reads the decided counts of a day. Days without a file are an error.
+func (c *Client) Decideds(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+) (map[string]int, error) {
+	name := day.Format("2006-01-02")
+	for _, ext := range []string{".csv", ".json"} {
+		path := filepath.Join(c.dir, name+ext)
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		var counts map[string]int
+		if ext == ".csv" {
+			counts, err = readCSV(f)
+		} else {
+			counts, err = readJSON(f)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return counts, nil
+	}
+	return nil, fmt.Errorf("no decided counts for day %s in %s", name, c.dir)
+}
+
+func readCSV(r io.Reader) (map[string]int, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	pubKeyCol, decidedsCol := slices.Index(header, "public_key"), slices.Index(header, "decideds")
+	if pubKeyCol < 0 || decidedsCol < 0 || len(header) != 2 {
+		return nil, fmt.Errorf("header must be public_key and decideds, got %v", header)
+	}
+
+	counts := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return counts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		n, err := strconv.Atoi(strings.TrimSpace(record[decidedsCol]))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("line %d: invalid decideds: %q", line, record[decidedsCol])
+		}
+		if err := add(counts, record[pubKeyCol], n); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+func readJSON(r io.Reader) (map[string]int, error) {
+	var raw map[string]int
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(raw))
+	for pubKey, n := range raw {
+		if n < 0 {
+			return nil, fmt.Errorf("invalid decideds of %s: %d", pubKey, n)
+		}
+		if err := add(counts, pubKey, n); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+func add(counts map[string]int, pubKey string, n int) error {
+	pubKey = decideds.PublicKey(strings.TrimSpace(pubKey))
+	if len(pubKey) != len(phase0.BLSPubKey{})*2 {
+		return fmt.Errorf("invalid public key: %q", pubKey)
+	}
+	if _, ok := counts[pubKey]; ok {
+		return fmt.Errorf("duplicate public key: %s", pubKey)
+	}
+	counts[pubKey] = n
+	return nil
+}
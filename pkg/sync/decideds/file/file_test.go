@@ -0,0 +1,52 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+)
+
+func TestClient(t *testing.T) {
+	pk := func(c string) string { return strings.Repeat(c, 96) }
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("2024-01-01.csv", "public_key,decideds\n0x"+strings.ToUpper(pk("a"))+",225\n"+pk("b")+",0\n")
+	write("2024-01-02.json", `{"`+pk("a")+`": 224}`)
+	write("2024-01-03.csv", "public_key,decideds\n"+pk("a")+",1\n"+pk("a")+",2\n")
+	write("2024-01-04.csv", "index,decideds\n1,1\n")
+
+	client, err := New(dir)
+	require.NoError(t, err)
+	decideds := func(day string) (map[string]int, error) {
+		d, err := time.Parse("2006-01-02", day)
+		require.NoError(t, err)
+		return client.Decideds(context.Background(), zap.NewNop(), beacon.Spec{}, d, 0, 224)
+	}
+
+	counts, err := decideds("2024-01-01")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{pk("a"): 225, pk("b"): 0}, counts)
+	counts, err = decideds("2024-01-02")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{pk("a"): 224}, counts)
+
+	_, err = decideds("2024-01-03")
+	require.ErrorContains(t, err, "duplicate public key")
+	_, err = decideds("2024-01-04")
+	require.ErrorContains(t, err, "header must be")
+	_, err = decideds("2024-01-05")
+	require.ErrorContains(t, err, "no decided counts for day 2024-01-05")
+
+	_, err = New(filepath.Join(dir, "2024-01-01.csv"))
+	require.ErrorContains(t, err, "not a directory")
+}
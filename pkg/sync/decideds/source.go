@@ -0,0 +1,42 @@
+// Package decideds provides the number of duties that SSV validators decided in a day,
+// which rewards require a minimum of, from interchangeable sources.
+package decideds
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+)
+
+type SourceType string
+
+type Source interface {
+	Type() SourceType
+
+	// Decideds returns the decided counts of validators in the given epoch range of a day,
+	// by public key.
+	Decideds(
+		ctx context.Context,
+		logger *zap.Logger,
+		spec beacon.Spec,
+		day time.Time,
+		fromEpoch, toEpoch phase0.Epoch,
+	) (map[string]int, error)
+}
+
+// LocalSource is implemented by sources of local data, which isn't cached.
+type LocalSource interface {
+	Source
+	Local()
+}
+
+// PublicKey normalizes a hex-encoded public key to lowercase without a 0x prefix,
+// which is how public keys are keyed by sources.
+func PublicKey(s string) string {
+	return strings.ToLower(strings.TrimPrefix(s, "0x"))
+}
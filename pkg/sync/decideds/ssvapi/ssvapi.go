@@ -0,0 +1,66 @@
+// Package ssvapi provides decided counts from the hosted SSV API.
+package ssvapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/carlmjohnson/requests"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/httpretry"
+)
+
+const (
+	SourceType decideds.SourceType = "ssv_api"
+)
+
+type Client struct {
+	endpoint string
+}
+
+func New(endpoint string) *Client {
+	return &Client{endpoint: endpoint}
+}
+
+func (c *Client) Type() decideds.SourceType {
+	return SourceType
+}
+
+func (c *Client) Decideds(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+) (map[string]int, error) {
+	var resp struct {
+		Error      string
+		Validators map[string]struct{ Duties int }
+	}
+	url := c.endpoint
+	if url[len(url)-1] != '/' {
+		url += "/"
+	}
+	err := requests.URL(url).
+		Client(httpretry.Client).
+		Pathf("%s/validators/duty_counts/%d/%d", spec.Network, fromEpoch, toEpoch).
+		ToJSON(&resp).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator duties: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("ssv api returned error: %s", resp.Error)
+	}
+
+	counts := make(map[string]int, len(resp.Validators))
+	for pubKey, data := range resp.Validators {
+		counts[decideds.PublicKey(pubKey)] = data.Duties
+	}
+	return counts, nil
+}
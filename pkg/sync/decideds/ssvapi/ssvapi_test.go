@@ -0,0 +1,37 @@
+package ssvapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+)
+
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/mainnet/validators/duty_counts/10/11", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"validators": {"0xAB": {"duties": 225}, "cd": {"duties": 0}}}`))
+		require.NoError(t, err)
+	})
+	mux.HandleFunc("GET /api/v4/mainnet/validators/duty_counts/12/13", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"error": "out of range"}`))
+		require.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := New(server.URL + "/api/v4")
+	spec := beacon.Spec{Network: "mainnet"}
+	counts, err := client.Decideds(context.Background(), zap.NewNop(), spec, time.Time{}, 10, 11)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"ab": 225, "cd": 0}, counts)
+
+	_, err = client.Decideds(context.Background(), zap.NewNop(), spec, time.Time{}, 12, 13)
+	require.ErrorContains(t, err, "out of range")
+}
@@ -0,0 +1,43 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecidedsCheck(t *testing.T) {
+	check := DecidedsCheck{MinCoverage: 0.75, MaxDrift: 0.1}
+	expected := []string{"a", "b", "c", "d"}
+
+	coverage := check.coverage(map[string]int{"a": 10, "b": 0, "c": 5, "d": 7, "e": 1}, expected)
+	require.Equal(t, decidedsCoverage{Expected: 4, Covered: 4, NonZero: 3}, coverage)
+	require.Empty(t, check.problems(coverage, nil))
+
+	// Partial responses fall below the minimum coverage and drift from the previous day.
+	partial := check.coverage(map[string]int{"a": 10, "b": 3}, expected)
+	require.Equal(t, 0.5, partial.Share())
+	problems := check.problems(partial, &coverage)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0], "below the minimum")
+	require.Contains(t, problems[1], "dropped by 50.00%")
+
+	// Drift is checked only with a previous day.
+	check.MinCoverage = 0
+	require.Empty(t, check.problems(partial, nil))
+
+	allZero := check.coverage(map[string]int{"a": 0, "b": 0, "c": 0, "d": 0}, expected)
+	require.Equal(t, []string{"no validator has a decided count above zero"}, check.problems(allZero, nil))
+	require.Len(t, check.problems(check.coverage(nil, expected), nil), 1)
+
+	// Days without expected validators can't be checked.
+	require.Empty(t, check.problems(check.coverage(nil, nil), &coverage))
+}
+
+func TestCompareDecideds(t *testing.T) {
+	require.Zero(t, compareDecideds(map[string]int{"a": 1}, map[string]int{"a": 1}))
+	require.Equal(t, 3, compareDecideds(
+		map[string]int{"a": 1, "b": 2, "c": 3},
+		map[string]int{"a": 1, "b": 1, "d": 4},
+	))
+}
@@ -0,0 +1,154 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+)
+
+// Reasons of performance gaps.
+const (
+	// GapReasonMissing is a validator that neither the provider nor its fallbacks
+	// had performance for.
+	GapReasonMissing = "missing"
+
+	// GapReasonFallbackError is a validator that the provider had no performance for,
+	// and whose last fallback failed.
+	GapReasonFallbackError = "fallback_error"
+
+	// GapReasonProviderError is a validator that the provider failed for, and that
+	// its fallbacks had no performance for.
+	GapReasonProviderError = "provider_error"
+)
+
+// recordPerformanceGaps records the gaps of a day, and resolves the day's earlier gaps
+// of validators whose inserted rows have performance. The gaps are resolved by joining
+// the day's rows rather than listing their validators, which may be more than the
+// bind parameters a query can have.
+func recordPerformanceGaps(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	provider models.ProviderType,
+	day time.Time,
+	gaps []performanceGap,
+) error {
+	now := time.Now()
+	_, err := tx.ExecContext(ctx, `
+		UPDATE performance_gaps AS g SET resolved_at = $3
+		FROM validator_performances AS vp
+		WHERE g.provider = $1 AND g.day = $2 AND g.resolved_at IS NULL
+		  AND vp.provider = g.provider AND vp.day = g.day AND vp.public_key = g.public_key
+		  AND vp.source IS NOT NULL`,
+		provider, day, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve performance gaps: %w", err)
+	}
+	for _, gap := range gaps {
+		if !gap.attesting {
+			continue
+		}
+		row := models.PerformanceGap{
+			Provider:   provider,
+			Day:        day,
+			PublicKey:  gap.performance.PublicKey,
+			Index:      gap.validator.Index.Int,
+			Reason:     gap.reason,
+			DetectedAt: now,
+		}
+		err := row.Upsert(
+			ctx,
+			tx,
+			true,
+			[]string{
+				models.PerformanceGapColumns.Provider,
+				models.PerformanceGapColumns.Day,
+				models.PerformanceGapColumns.PublicKey,
+			},
+			boil.Whitelist(
+				models.PerformanceGapColumns.Reason,
+				models.PerformanceGapColumns.DetectedAt,
+				models.PerformanceGapColumns.ResolvedAt,
+			),
+			boil.Infer(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record performance gap: %w", err)
+		}
+	}
+	return nil
+}
+
+// PerformanceGapSummary is the number of a provider's gaps by reason.
+type PerformanceGapSummary struct {
+	Provider   models.ProviderType
+	Reason     string
+	Unresolved int
+	Resolved   int
+	FirstDay   time.Time
+	LastDay    time.Time
+}
+
+// SummarizePerformanceGaps returns the number of gaps by provider and reason.
+func SummarizePerformanceGaps(ctx context.Context, db *sql.DB) ([]PerformanceGapSummary, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT provider, reason,
+			COUNT(*) FILTER (WHERE resolved_at IS NULL),
+			COUNT(*) FILTER (WHERE resolved_at IS NOT NULL),
+			MIN(day), MAX(day)
+		FROM performance_gaps
+		GROUP BY provider, reason
+		ORDER BY provider, reason`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize performance gaps: %w", err)
+	}
+	defer rows.Close()
+	var summaries []PerformanceGapSummary
+	for rows.Next() {
+		var s PerformanceGapSummary
+		if err := rows.Scan(&s.Provider, &s.Reason, &s.Unresolved, &s.Resolved, &s.FirstDay, &s.LastDay); err != nil {
+			return nil, fmt.Errorf("failed to scan performance gaps: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// PerformanceGapDay is the number of a provider's unresolved gaps in a day.
+type PerformanceGapDay struct {
+	Provider models.ProviderType
+	Day      time.Time
+	Gaps     int
+}
+
+// UnresolvedPerformanceGapDays returns the days with unresolved gaps, ordered by
+// provider and day.
+func UnresolvedPerformanceGapDays(ctx context.Context, db *sql.DB) ([]PerformanceGapDay, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT provider, day, COUNT(*)
+		FROM performance_gaps
+		WHERE resolved_at IS NULL
+		GROUP BY provider, day
+		ORDER BY provider, day`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get performance gaps: %w", err)
+	}
+	defer rows.Close()
+	var days []PerformanceGapDay
+	for rows.Next() {
+		var d PerformanceGapDay
+		if err := rows.Scan(&d.Provider, &d.Day, &d.Gaps); err != nil {
+			return nil, fmt.Errorf("failed to scan performance gaps: %w", err)
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
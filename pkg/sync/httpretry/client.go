@@ -1,21 +1,31 @@
 package httpretry
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/ybbus/httpretry"
 )
 
-var Client = httpretry.NewDefaultClient(
-	httpretry.WithMaxRetryCount(10),
+var Client = newClient(true)
 
-	// Retry on any error, 5xx status codes and 0 status codes.
-	httpretry.WithRetryPolicy(func(statusCode int, err error) bool {
-		return err != nil || statusCode >= 500 || statusCode == 0 || statusCode == 429
-	}),
+// RateLimitAwareClient is like Client, but doesn't retry on 429 status codes,
+// for callers that wait according to the API's rate-limit headers instead.
+var RateLimitAwareClient = newClient(false)
 
-	// Retry with an incremental backoff policy.
-	httpretry.WithBackoffPolicy(func(attemptNum int) time.Duration {
-		return time.Duration(attemptNum+1) * 2 * time.Second
-	}),
-)
+func newClient(retryTooManyRequests bool) *http.Client {
+	return httpretry.NewDefaultClient(
+		httpretry.WithMaxRetryCount(10),
+
+		// Retry on any error, 5xx status codes and 0 status codes.
+		httpretry.WithRetryPolicy(func(statusCode int, err error) bool {
+			return err != nil || statusCode >= 500 || statusCode == 0 ||
+				(statusCode == http.StatusTooManyRequests && retryTooManyRequests)
+		}),
+
+		// Retry with an incremental backoff policy.
+		httpretry.WithBackoffPolicy(func(attemptNum int) time.Duration {
+			return time.Duration(attemptNum+1) * 2 * time.Second
+		}),
+	)
+}
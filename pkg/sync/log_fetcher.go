@@ -0,0 +1,434 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultLogChunkSize is the default number of blocks per eth_getLogs request.
+	DefaultLogChunkSize = 5000
+
+	// DefaultLogWorkers is the default number of concurrent eth_getLogs requests.
+	DefaultLogWorkers = 4
+
+	// headerBatchSize is the maximum number of headers requested in a single batch.
+	headerBatchSize = 100
+
+	// maxRequestRetries is the number of times a rate-limited or failed request is
+	// retried before giving up.
+	maxRequestRetries = 8
+
+	// defaultRetryBackoff is the wait before the first retry, which doubles with
+	// each retry up to maxRetryBackoff.
+	defaultRetryBackoff = time.Second
+	maxRetryBackoff     = time.Minute
+)
+
+// Block is the number, hash and time of a synced block.
+type Block struct {
+	Number uint64
+	Hash   common.Hash
+	Time   time.Time
+}
+
+func blockFromHeader(header *types.Header) Block {
+	return Block{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+		Time:   time.Unix(int64(header.Time), 0).UTC(),
+	}
+}
+
+// BlockLogs are the logs of a single block.
+type BlockLogs struct {
+	Block Block
+	Logs  []types.Log
+}
+
+// LogChunk is the result of fetching a range of blocks. Blocks holds only the
+// blocks with logs, in ascending order, and End is the last block of the range,
+// which is used to checkpoint the sync.
+type LogChunk struct {
+	FromBlock, ToBlock uint64
+	Blocks             []BlockLogs
+	End                Block
+}
+
+// ContractLogSource provides the logs of the SSV registry contract.
+type ContractLogSource interface {
+	// Fetch calls handle with the logs from fromBlock to toBlock (inclusive)
+	// in chunks of ascending block ranges.
+	Fetch(ctx context.Context, fromBlock, toBlock uint64, handle func(chunk LogChunk) error) error
+}
+
+// logSource is the subset of an execution node's API used by LogFetcher.
+type logSource interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	HeadersByNumber(ctx context.Context, numbers []uint64) (map[uint64]*types.Header, error)
+}
+
+// LogFetcher fetches the logs of a contract in chunks of blocks, with several chunks
+// fetched concurrently. Chunks which return too many results are split in halves,
+// and requests which are rate-limited or fail transiently are retried with backoff.
+type LogFetcher struct {
+	logger       *zap.Logger
+	source       logSource
+	contract     common.Address
+	chunkSize    uint64
+	workers      int
+	retryBackoff time.Duration
+}
+
+// NewLogFetcher returns a LogFetcher for the given contract's logs.
+func NewLogFetcher(
+	logger *zap.Logger,
+	client *ethclient.Client,
+	contract common.Address,
+	chunkSize uint64,
+	workers int,
+) (*LogFetcher, error) {
+	return newLogFetcher(logger, &rpcLogSource{client}, contract, chunkSize, workers)
+}
+
+func newLogFetcher(
+	logger *zap.Logger,
+	source logSource,
+	contract common.Address,
+	chunkSize uint64,
+	workers int,
+) (*LogFetcher, error) {
+	if chunkSize == 0 {
+		return nil, errors.New("log chunk size must be positive")
+	}
+	if workers <= 0 {
+		return nil, errors.New("log workers must be positive")
+	}
+	return &LogFetcher{
+		logger:       logger,
+		source:       source,
+		contract:     contract,
+		chunkSize:    chunkSize,
+		workers:      workers,
+		retryBackoff: defaultRetryBackoff,
+	}, nil
+}
+
+// Fetch fetches the logs from fromBlock to toBlock (inclusive) and calls handle with
+// each chunk in ascending order. At most workers chunks are fetched ahead of handle.
+func (f *LogFetcher) Fetch(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	handle func(chunk LogChunk) error,
+) error {
+	if toBlock < fromBlock {
+		return fmt.Errorf("from block (%d) cannot be greater than to block (%d)", fromBlock, toBlock)
+	}
+	ranges := splitBlockRange(fromBlock, toBlock, f.chunkSize)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type chunkResult struct {
+		chunk LogChunk
+		err   error
+	}
+	results := make([]chan chunkResult, len(ranges))
+	for i := range results {
+		results[i] = make(chan chunkResult, 1)
+	}
+
+	// Each worker slot is taken when a chunk starts fetching, and released once it's
+	// handled, so that fetched chunks don't pile up while waiting for earlier ones.
+	slots := make(chan struct{}, f.workers)
+	go func() {
+		for i, r := range ranges {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, from, to uint64) {
+				chunk, err := f.fetchChunk(ctx, from, to)
+				results[i] <- chunkResult{chunk, err}
+			}(i, r[0], r[1])
+		}
+	}()
+
+	for i := range ranges {
+		var result chunkResult
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if result.err != nil {
+			return fmt.Errorf("failed to fetch logs from block %d to %d: %w", ranges[i][0], ranges[i][1], result.err)
+		}
+		if err := handle(result.chunk); err != nil {
+			return err
+		}
+		<-slots
+	}
+	return nil
+}
+
+func (f *LogFetcher) fetchChunk(ctx context.Context, fromBlock, toBlock uint64) (LogChunk, error) {
+	logs, err := f.filterLogs(ctx, fromBlock, toBlock)
+	if err != nil {
+		return LogChunk{}, err
+	}
+
+	byBlock := map[uint64][]types.Log{}
+	for _, log := range logs {
+		if log.Removed {
+			// This shouldn't happen unless there was a reorg during the request.
+			f.logger.Warn("log is removed",
+				zap.String("block_hash", log.BlockHash.Hex()),
+				zap.String("tx_hash", log.TxHash.Hex()),
+				zap.Uint("log_index", log.Index))
+			continue
+		}
+		byBlock[log.BlockNumber] = append(byBlock[log.BlockNumber], log)
+	}
+
+	numbers := make([]uint64, 0, len(byBlock)+1)
+	for number := range byBlock {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	if _, ok := byBlock[toBlock]; !ok {
+		numbers = append(numbers, toBlock)
+	}
+	var headers map[uint64]*types.Header
+	err = f.retry(ctx, "eth_getBlockByNumber", func() (err error) {
+		headers, err = f.source.HeadersByNumber(ctx, numbers)
+		return err
+	})
+	if err != nil {
+		return LogChunk{}, fmt.Errorf("failed to get headers: %w", err)
+	}
+
+	chunk := LogChunk{FromBlock: fromBlock, ToBlock: toBlock, End: blockFromHeader(headers[toBlock])}
+	for _, number := range numbers {
+		blockLogs, ok := byBlock[number]
+		if !ok {
+			continue
+		}
+		sort.Slice(blockLogs, func(i, j int) bool { return blockLogs[i].Index < blockLogs[j].Index })
+		header := headers[number]
+		if header.Hash() != blockLogs[0].BlockHash {
+			return LogChunk{}, fmt.Errorf(
+				"block %d hash %s doesn't match its logs' block hash %s, possibly due to a reorg",
+				number, header.Hash(), blockLogs[0].BlockHash,
+			)
+		}
+		chunk.Blocks = append(chunk.Blocks, BlockLogs{Block: blockFromHeader(header), Logs: blockLogs})
+	}
+	return chunk, nil
+}
+
+// filterLogs fetches the logs of the given block range, splitting it in halves
+// for as long as the node refuses to return that many results.
+func (f *LogFetcher) filterLogs(ctx context.Context, fromBlock, toBlock uint64) ([]types.Log, error) {
+	var logs []types.Log
+	err := f.retry(ctx, "eth_getLogs", func() (err error) {
+		logs, err = f.source.FilterLogs(ctx, ethereum.FilterQuery{
+			Addresses: []common.Address{f.contract},
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+		})
+		return err
+	})
+	if err == nil {
+		return logs, nil
+	}
+	if !isTooManyResults(err) || fromBlock == toBlock {
+		return nil, err
+	}
+
+	mid := fromBlock + (toBlock-fromBlock)/2
+	f.logger.Debug("Splitting log range",
+		zap.Uint64("from", fromBlock),
+		zap.Uint64("to", toBlock),
+		zap.Error(err),
+	)
+	left, err := f.filterLogs(ctx, fromBlock, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := f.filterLogs(ctx, mid+1, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// retry calls request until it succeeds, retrying it with backoff while it's
+// rate-limited or fails transiently, for up to maxRequestRetries times.
+func (f *LogFetcher) retry(ctx context.Context, method string, request func() error) error {
+	backoff := f.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := request()
+		if err == nil || attempt == maxRequestRetries || ctx.Err() != nil ||
+			isTooManyResults(err) || (!isRateLimited(err) && !isTransient(err)) {
+			return err
+		}
+		f.logger.Debug("Retrying request",
+			zap.String("method", method),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+// limitExceededCode is the JSON-RPC error code nodes return when a request exceeds
+// their limits, which is either the size of its result or the rate of requests.
+const limitExceededCode = -32005
+
+// tooManyResultsMessages are the (lowercase) errors that execution nodes and RPC
+// providers return when an eth_getLogs range has too many results or blocks. They're
+// specific to each, so that other errors about block ranges aren't retried by
+// splitting the range.
+var tooManyResultsMessages = []string{
+	"query returned more than",                    // Geth, Erigon, Infura: "query returned more than 10000 results"
+	"query exceeds max results",                   // Erigon
+	"log response size exceeded",                  // Alchemy
+	"exceed maximum block range",                  // Nethermind, Chainstack
+	"requested range exceeds maximum range limit", // Besu
+	"block range is too wide",                     // Ankr
+	"block range is too large",                    // PublicNode
+	"eth_getlogs is limited to a",                 // QuickNode: "eth_getLogs is limited to a 10,000 range"
+}
+
+func isTooManyResults(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, substr := range tooManyResultsMessages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitMessages are substrings of the errors RPC providers return when requests
+// are rate-limited.
+var rateLimitMessages = []string{
+	"rate limit",
+	"too many requests",
+	"request limit",
+}
+
+// isRateLimited returns whether the node refused a request due to the rate of
+// requests, rather than the size of its result.
+func isRateLimited(err error) bool {
+	if isTooManyResults(err) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == limitExceededCode {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, substr := range rateLimitMessages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransient returns whether a request failed due to the connection or a server
+// error, and may succeed if retried.
+func isTransient(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// splitBlockRange splits the inclusive range from fromBlock to toBlock into inclusive
+// ranges of at most size blocks.
+func splitBlockRange(fromBlock, toBlock, size uint64) [][2]uint64 {
+	var ranges [][2]uint64
+	for from := fromBlock; from <= toBlock; from += size {
+		to := from + size - 1
+		if to > toBlock || to < from {
+			to = toBlock
+		}
+		ranges = append(ranges, [2]uint64{from, to})
+		if to == toBlock {
+			break
+		}
+	}
+	return ranges
+}
+
+// rpcLogSource implements logSource with an execution node's JSON-RPC API.
+type rpcLogSource struct {
+	*ethclient.Client
+}
+
+// HeadersByNumber requests the given headers in batches.
+func (s *rpcLogSource) HeadersByNumber(ctx context.Context, numbers []uint64) (map[uint64]*types.Header, error) {
+	headers := make(map[uint64]*types.Header, len(numbers))
+	for start := 0; start < len(numbers); start += headerBatchSize {
+		batchNumbers := numbers[start:min(start+headerBatchSize, len(numbers))]
+		batch := make([]rpc.BatchElem, len(batchNumbers))
+		results := make([]*types.Header, len(batchNumbers))
+		for i, number := range batchNumbers {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getBlockByNumber",
+				Args:   []interface{}{hexutil.EncodeUint64(number), false},
+				Result: &results[i],
+			}
+		}
+		if err := s.Client.Client().BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+		for i, number := range batchNumbers {
+			if batch[i].Error != nil {
+				return nil, fmt.Errorf("failed to get header of block %d: %w", number, batch[i].Error)
+			}
+			if results[i] == nil {
+				return nil, fmt.Errorf("block %d not found", number)
+			}
+			headers[number] = results[i]
+		}
+	}
+	return headers, nil
+}
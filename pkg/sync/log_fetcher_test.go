@@ -0,0 +1,224 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeLogSource serves logs from memory, refusing ranges with more than maxResults logs.
+// The first requests fail with failures, one each.
+type fakeLogSource struct {
+	logs       []types.Log
+	maxResults int
+	failures   []error
+
+	mu       gosync.Mutex
+	requests [][2]uint64
+}
+
+func fakeHeader(number uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Time: 1000 + number*12}
+}
+
+func (s *fakeLogSource) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	s.mu.Lock()
+	s.requests = append(s.requests, [2]uint64{from, to})
+	var failure error
+	if len(s.failures) > 0 {
+		failure, s.failures = s.failures[0], s.failures[1:]
+	}
+	s.mu.Unlock()
+	if failure != nil {
+		return nil, failure
+	}
+
+	// Respond out of order to exercise ordering.
+	time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+
+	var logs []types.Log
+	for _, log := range s.logs {
+		if log.BlockNumber >= from && log.BlockNumber <= to {
+			logs = append(logs, log)
+		}
+	}
+	if s.maxResults > 0 && len(logs) > s.maxResults {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+	return logs, nil
+}
+
+func (s *fakeLogSource) HeadersByNumber(ctx context.Context, numbers []uint64) (map[uint64]*types.Header, error) {
+	headers := map[uint64]*types.Header{}
+	for _, number := range numbers {
+		headers[number] = fakeHeader(number)
+	}
+	return headers, nil
+}
+
+// fakeRPCError is a JSON-RPC error response.
+type fakeRPCError struct {
+	code    int
+	message string
+}
+
+func (e fakeRPCError) Error() string  { return e.message }
+func (e fakeRPCError) ErrorCode() int { return e.code }
+
+func fakeLog(block uint64, index uint) types.Log {
+	return types.Log{BlockNumber: block, BlockHash: fakeHeader(block).Hash(), Index: index}
+}
+
+func TestLogFetcher_Fetch(t *testing.T) {
+	source := &fakeLogSource{
+		logs: []types.Log{
+			fakeLog(3, 1), fakeLog(3, 0), fakeLog(12, 0), fakeLog(25, 0),
+			fakeLog(26, 0), fakeLog(27, 0), fakeLog(28, 0), fakeLog(30, 0),
+		},
+		maxResults: 2,
+	}
+	fetcher, err := newLogFetcher(zap.NewNop(), source, common.Address{}, 10, 3)
+	require.NoError(t, err)
+
+	var chunks []LogChunk
+	err = fetcher.Fetch(context.Background(), 1, 30, func(chunk LogChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, chunks, 3)
+	var (
+		blocks []uint64
+		total  int
+	)
+	for i, chunk := range chunks {
+		require.Equal(t, uint64(i*10+1), chunk.FromBlock)
+		require.Equal(t, uint64(i*10+10), chunk.ToBlock)
+		require.Equal(t, chunk.ToBlock, chunk.End.Number)
+		for _, block := range chunk.Blocks {
+			blocks = append(blocks, block.Block.Number)
+			for j, log := range block.Logs {
+				require.Equal(t, block.Block.Number, log.BlockNumber)
+				require.Equal(t, uint(j), log.Index)
+			}
+			total += len(block.Logs)
+		}
+	}
+	require.Equal(t, []uint64{3, 12, 25, 26, 27, 28, 30}, blocks)
+	require.Equal(t, len(source.logs), total)
+
+	// The last chunk has 5 logs, so it must have been split.
+	require.Contains(t, source.requests, [2]uint64{21, 25})
+	require.Contains(t, source.requests, [2]uint64{26, 30})
+}
+
+func TestLogFetcher_FetchErrors(t *testing.T) {
+	t.Run("handler error stops fetching", func(t *testing.T) {
+		source := &fakeLogSource{}
+		fetcher, err := newLogFetcher(zap.NewNop(), source, common.Address{}, 10, 2)
+		require.NoError(t, err)
+
+		handled := 0
+		err = fetcher.Fetch(context.Background(), 1, 1000, func(chunk LogChunk) error {
+			handled++
+			return errors.New("insert failed")
+		})
+		require.EqualError(t, err, "insert failed")
+		require.Equal(t, 1, handled)
+	})
+
+	t.Run("single block with too many results", func(t *testing.T) {
+		source := &fakeLogSource{
+			logs:       []types.Log{fakeLog(5, 0), fakeLog(5, 1), fakeLog(5, 2)},
+			maxResults: 2,
+		}
+		fetcher, err := newLogFetcher(zap.NewNop(), source, common.Address{}, 10, 2)
+		require.NoError(t, err)
+
+		err = fetcher.Fetch(context.Background(), 1, 10, func(chunk LogChunk) error { return nil })
+		require.ErrorContains(t, err, "query returned more than")
+	})
+
+	t.Run("rate-limited and transient errors are retried", func(t *testing.T) {
+		source := &fakeLogSource{
+			logs: []types.Log{fakeLog(5, 0)},
+			failures: []error{
+				fakeRPCError{code: -32005, message: "request rate exceeded"},
+				rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"},
+				rpc.HTTPError{StatusCode: 502, Status: "502 Bad Gateway"},
+			},
+		}
+		fetcher, err := newLogFetcher(zap.NewNop(), source, common.Address{}, 10, 1)
+		require.NoError(t, err)
+		fetcher.retryBackoff = time.Millisecond
+
+		var chunks []LogChunk
+		err = fetcher.Fetch(context.Background(), 1, 10, func(chunk LogChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, chunks, 1)
+		require.Len(t, chunks[0].Blocks, 1)
+
+		// Rate limits aren't mistaken for too many results, so the range isn't split.
+		require.Equal(t, [][2]uint64{{1, 10}, {1, 10}, {1, 10}, {1, 10}}, source.requests)
+	})
+
+	t.Run("other errors aren't retried", func(t *testing.T) {
+		source := &fakeLogSource{failures: []error{fakeRPCError{code: -32602, message: "invalid params"}}}
+		fetcher, err := newLogFetcher(zap.NewNop(), source, common.Address{}, 10, 1)
+		require.NoError(t, err)
+		fetcher.retryBackoff = time.Millisecond
+
+		err = fetcher.Fetch(context.Background(), 1, 10, func(chunk LogChunk) error { return nil })
+		require.ErrorContains(t, err, "invalid params")
+		require.Len(t, source.requests, 1)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newLogFetcher(zap.NewNop(), &fakeLogSource{}, common.Address{}, 0, 1)
+		require.Error(t, err)
+		_, err = newLogFetcher(zap.NewNop(), &fakeLogSource{}, common.Address{}, 1, 0)
+		require.Error(t, err)
+	})
+}
+
+func TestSplitBlockRange(t *testing.T) {
+	require.Equal(t, [][2]uint64{{1, 10}}, splitBlockRange(1, 10, 10))
+	require.Equal(t, [][2]uint64{{1, 4}, {5, 8}, {9, 10}}, splitBlockRange(1, 10, 4))
+	require.Equal(t, [][2]uint64{{7, 7}}, splitBlockRange(7, 7, 100))
+}
+
+func TestIsTooManyResults(t *testing.T) {
+	require.True(t, isTooManyResults(errors.New("query returned more than 10000 results")))
+	require.True(t, isTooManyResults(errors.New("Log response size exceeded")))
+	require.True(t, isTooManyResults(fakeRPCError{code: -32005, message: "query returned more than 10000 results"}))
+	require.False(t, isTooManyResults(fakeRPCError{code: -32005, message: "limit exceeded"}))
+	require.False(t, isTooManyResults(errors.New("connection refused")))
+
+	// Other errors about block ranges aren't mistaken for too many results.
+	require.False(t, isTooManyResults(errors.New("invalid block range params")))
+	require.False(t, isTooManyResults(errors.New("block range out of bounds")))
+}
+
+func TestIsRateLimited(t *testing.T) {
+	require.True(t, isRateLimited(fakeRPCError{code: -32005, message: "limit exceeded"}))
+	require.True(t, isRateLimited(rpc.HTTPError{StatusCode: 429}))
+	require.True(t, isRateLimited(errors.New("Your app has exceeded its compute units per second capacity, rate limit")))
+	require.False(t, isRateLimited(fakeRPCError{code: -32005, message: "query returned more than 10000 results"}))
+	require.False(t, isRateLimited(rpc.HTTPError{StatusCode: 500}))
+}
@@ -0,0 +1,333 @@
+package sync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+)
+
+// blockRecord is a line of a blocks file.
+type blockRecord struct {
+	Number    quantity    `json:"number"`
+	Hash      common.Hash `json:"hash"`
+	Timestamp quantity    `json:"timestamp"`
+}
+
+// quantity is a JSON number, or a hex-encoded number as returned by the execution
+// node's JSON-RPC API (such as in eth_getBlockByNumber results).
+type quantity uint64
+
+func (q *quantity) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		v, err := hexutil.DecodeUint64(s)
+		if err != nil {
+			return err
+		}
+		*q = quantity(v)
+		return nil
+	}
+	v, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*q = quantity(v)
+	return nil
+}
+
+func (q quantity) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(q), 10)), nil
+}
+
+// FileLogSource provides contract logs from local files instead of an execution node:
+//   - A logs file, holding JSON-encoded logs (as returned by eth_getLogs), either one
+//     per line, as JSON arrays, or as eth_getLogs JSON-RPC responses.
+//   - A blocks file, holding a JSON object per line with the number, hash and
+//     timestamp of every block with logs, and of the block to sync up to.
+type FileLogSource struct {
+	blocks       map[uint64]Block
+	logsByBlock  map[uint64][]types.Log
+	highestBlock Block
+}
+
+// NewFileLogSource reads the given contract's logs and their blocks from the given files.
+func NewFileLogSource(logger *zap.Logger, logsPath, blocksPath string, contract common.Address) (*FileLogSource, error) {
+	blocksFile, err := os.Open(blocksPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocks file: %w", err)
+	}
+	defer blocksFile.Close()
+	blocks, err := readBlocks(blocksFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blocks file: %w", err)
+	}
+
+	logsFile, err := os.Open(logsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open logs file: %w", err)
+	}
+	defer logsFile.Close()
+	logs, err := readLogs(logsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs file: %w", err)
+	}
+
+	return newFileLogSource(logger, logs, blocks, contract)
+}
+
+func newFileLogSource(logger *zap.Logger, logs []types.Log, blocks []Block, contract common.Address) (*FileLogSource, error) {
+	if len(blocks) == 0 {
+		return nil, errors.New("blocks file is empty")
+	}
+	source := &FileLogSource{
+		blocks:      make(map[uint64]Block, len(blocks)),
+		logsByBlock: map[uint64][]types.Log{},
+	}
+	for _, block := range blocks {
+		if existing, ok := source.blocks[block.Number]; ok && existing != block {
+			return nil, fmt.Errorf("conflicting records for block %d", block.Number)
+		}
+		source.blocks[block.Number] = block
+		if block.Number >= source.highestBlock.Number {
+			source.highestBlock = block
+		}
+	}
+
+	skipped := 0
+	seen := map[[2]uint64]struct{}{}
+	for _, log := range logs {
+		if log.Address != contract || log.Removed {
+			skipped++
+			continue
+		}
+		key := [2]uint64{log.BlockNumber, uint64(log.Index)}
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("duplicate log %d in block %d", log.Index, log.BlockNumber)
+		}
+		seen[key] = struct{}{}
+
+		block, ok := source.blocks[log.BlockNumber]
+		if !ok {
+			return nil, fmt.Errorf("block %d of log %d is missing from blocks file", log.BlockNumber, log.Index)
+		}
+		if block.Hash != log.BlockHash {
+			return nil, fmt.Errorf(
+				"block %d hash %s in blocks file doesn't match its logs' block hash %s",
+				log.BlockNumber, block.Hash, log.BlockHash,
+			)
+		}
+		source.logsByBlock[log.BlockNumber] = append(source.logsByBlock[log.BlockNumber], log)
+	}
+	for _, blockLogs := range source.logsByBlock {
+		sort.Slice(blockLogs, func(i, j int) bool { return blockLogs[i].Index < blockLogs[j].Index })
+	}
+	if skipped > 0 {
+		logger.Info("Skipped logs of other contracts or removed logs", zap.Int("count", skipped))
+	}
+	return source, nil
+}
+
+// HighestBlock returns the highest block in the blocks file.
+func (s *FileLogSource) HighestBlock() Block {
+	return s.highestBlock
+}
+
+// Block returns the given block from the blocks file.
+func (s *FileLogSource) Block(number uint64) (Block, bool) {
+	block, ok := s.blocks[number]
+	return block, ok
+}
+
+// Fetch calls handle with a single chunk holding all logs from fromBlock to toBlock.
+func (s *FileLogSource) Fetch(
+	ctx context.Context,
+	fromBlock, toBlock uint64,
+	handle func(chunk LogChunk) error,
+) error {
+	if toBlock < fromBlock {
+		return fmt.Errorf("from block (%d) cannot be greater than to block (%d)", fromBlock, toBlock)
+	}
+	end, ok := s.blocks[toBlock]
+	if !ok {
+		return fmt.Errorf("block %d is missing from blocks file", toBlock)
+	}
+
+	numbers := make([]uint64, 0, len(s.logsByBlock))
+	for number := range s.logsByBlock {
+		if number >= fromBlock && number <= toBlock {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	chunk := LogChunk{FromBlock: fromBlock, ToBlock: toBlock, End: end}
+	for _, number := range numbers {
+		chunk.Blocks = append(chunk.Blocks, BlockLogs{Block: s.blocks[number], Logs: s.logsByBlock[number]})
+	}
+	return handle(chunk)
+}
+
+// readLogs reads a sequence of JSON values, each of which is either a log,
+// an array of logs, or a JSON-RPC response with an array of logs as its result.
+func readLogs(r io.Reader) ([]types.Log, error) {
+	var logs []types.Log
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == io.EOF {
+			return logs, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		value = bytes.TrimSpace(value)
+		if len(value) > 0 && value[0] == '[' {
+			var batch []types.Log
+			if err := json.Unmarshal(value, &batch); err != nil {
+				return nil, err
+			}
+			logs = append(logs, batch...)
+			continue
+		}
+
+		var response struct {
+			Result *[]types.Log `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(value, &response); err == nil && (response.Result != nil || response.Error != nil) {
+			if response.Error != nil {
+				return nil, fmt.Errorf("logs file holds a JSON-RPC error: %s", response.Error.Message)
+			}
+			logs = append(logs, *response.Result...)
+			continue
+		}
+
+		var log types.Log
+		if err := json.Unmarshal(value, &log); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+}
+
+func readBlocks(r io.Reader) ([]Block, error) {
+	var blocks []Block
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var record blockRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if record.Hash == (common.Hash{}) {
+			return nil, fmt.Errorf("block %d has no hash", record.Number)
+		}
+		if record.Timestamp == 0 {
+			return nil, fmt.Errorf("block %d has no timestamp", record.Number)
+		}
+		blocks = append(blocks, Block{
+			Number: uint64(record.Number),
+			Hash:   record.Hash,
+			Time:   time.Unix(int64(record.Timestamp), 0).UTC(),
+		})
+	}
+}
+
+// ExportContractLogs writes the stored contract events to a logs file and their blocks,
+// along with the highest synced block, to a blocks file, in the format read by FileLogSource.
+func ExportContractLogs(ctx context.Context, db *sql.DB, logsWriter, blocksWriter io.Writer) (events int, blocks int, err error) {
+	rows, err := db.QueryContext(
+		ctx,
+		"SELECT block_number, block_hash, block_time, raw_log FROM contract_events ORDER BY block_number, log_index",
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query contract events: %w", err)
+	}
+	defer rows.Close()
+
+	logsBuf := bufio.NewWriter(logsWriter)
+	blocksEncoder := json.NewEncoder(blocksWriter)
+	lastBlock := int64(-1)
+	for rows.Next() {
+		var (
+			blockNumber int64
+			blockHash   string
+			blockTime   time.Time
+			rawLog      []byte
+		)
+		if err := rows.Scan(&blockNumber, &blockHash, &blockTime, &rawLog); err != nil {
+			return 0, 0, fmt.Errorf("failed to scan contract event: %w", err)
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, rawLog); err != nil {
+			return 0, 0, fmt.Errorf("invalid raw log in block %d: %w", blockNumber, err)
+		}
+		compacted.WriteByte('\n')
+		if _, err := logsBuf.Write(compacted.Bytes()); err != nil {
+			return 0, 0, fmt.Errorf("failed to write log: %w", err)
+		}
+		events++
+
+		if blockNumber != lastBlock {
+			err := blocksEncoder.Encode(blockRecord{
+				Number:    quantity(blockNumber),
+				Hash:      common.HexToHash(blockHash),
+				Timestamp: quantity(blockTime.Unix()),
+			})
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to write block: %w", err)
+			}
+			lastBlock = blockNumber
+			blocks++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to iterate contract events: %w", err)
+	}
+	if err := logsBuf.Flush(); err != nil {
+		return 0, 0, fmt.Errorf("failed to write logs: %w", err)
+	}
+
+	// Add the highest synced block, so that the import syncs up to it.
+	state, err := models.States().One(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get state: %w", err)
+	}
+	if highestNumber := int64(state.HighestBlockNumber); highestNumber != lastBlock {
+		if !state.HighestBlockHash.Valid || !state.HighestBlockTime.Valid {
+			return 0, 0, fmt.Errorf("hash and time of the highest synced block %d are unknown: sync again to record them", highestNumber)
+		}
+		err := blocksEncoder.Encode(blockRecord{
+			Number:    quantity(highestNumber),
+			Hash:      common.HexToHash(state.HighestBlockHash.String),
+			Timestamp: quantity(state.HighestBlockTime.Time.Unix()),
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to write block: %w", err)
+		}
+		blocks++
+	}
+	return events, blocks, nil
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	testContract = common.HexToAddress("0xDD9BC35aE942eF0cFa76930954a156B3fF30a4E1")
+	testHash5    = common.HexToHash("0x05")
+	testHash9    = common.HexToHash("0x09")
+)
+
+func testLog(t *testing.T, block uint64, hash common.Hash, index uint) types.Log {
+	t.Helper()
+	return types.Log{
+		Address:     testContract,
+		Topics:      []common.Hash{common.HexToHash("0x01")},
+		Data:        []byte{},
+		BlockNumber: block,
+		BlockHash:   hash,
+		TxHash:      common.HexToHash("0xaa"),
+		Index:       index,
+	}
+}
+
+func TestReadLogs(t *testing.T) {
+	log0 := testLog(t, 5, testHash5, 0)
+	log1 := testLog(t, 5, testHash5, 1)
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"jsonl", encode(log0) + "\n" + encode(log1) + "\n"},
+		{"array", encode([]types.Log{log0, log1})},
+		{"json-rpc response", `{"jsonrpc":"2.0","id":1,"result":` + encode([]types.Log{log0, log1}) + `}`},
+		{"mixed", encode([]types.Log{log0}) + "\n" + encode(log1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, err := readLogs(strings.NewReader(tt.input))
+			require.NoError(t, err)
+			require.Len(t, logs, 2)
+			require.Equal(t, log0.Index, logs[0].Index)
+			require.Equal(t, log1.Index, logs[1].Index)
+			require.Equal(t, testHash5, logs[1].BlockHash)
+		})
+	}
+
+	t.Run("json-rpc error", func(t *testing.T) {
+		_, err := readLogs(strings.NewReader(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`))
+		require.ErrorContains(t, err, "limit exceeded")
+	})
+}
+
+func TestReadBlocks(t *testing.T) {
+	blocks, err := readBlocks(strings.NewReader(
+		`{"number":5,"hash":"` + testHash5.Hex() + `","timestamp":1700000000}` + "\n" +
+			`{"number":"0x9","hash":"` + testHash9.Hex() + `","timestamp":"0x6553f124"}`,
+	))
+	require.NoError(t, err)
+	require.Equal(t, []Block{
+		{Number: 5, Hash: testHash5, Time: time.Unix(1700000000, 0).UTC()},
+		{Number: 9, Hash: testHash9, Time: time.Unix(0x6553f124, 0).UTC()},
+	}, blocks)
+
+	_, err = readBlocks(strings.NewReader(`{"number":5,"timestamp":1700000000}`))
+	require.ErrorContains(t, err, "no hash")
+	_, err = readBlocks(strings.NewReader(`{"number":5,"hash":"` + testHash5.Hex() + `"}`))
+	require.ErrorContains(t, err, "no timestamp")
+}
+
+func TestFileLogSource(t *testing.T) {
+	blocks := []Block{
+		{Number: 5, Hash: testHash5, Time: time.Unix(1700000000, 0).UTC()},
+		{Number: 9, Hash: testHash9, Time: time.Unix(1700000048, 0).UTC()},
+	}
+	otherContract := testLog(t, 5, testHash5, 2)
+	otherContract.Address = common.HexToAddress("0x01")
+	logs := []types.Log{testLog(t, 5, testHash5, 1), testLog(t, 5, testHash5, 0), otherContract}
+
+	source, err := newFileLogSource(zap.NewNop(), logs, blocks, testContract)
+	require.NoError(t, err)
+	require.Equal(t, blocks[1], source.HighestBlock())
+
+	var chunks []LogChunk
+	err = source.Fetch(context.Background(), 1, 9, func(chunk LogChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	require.Equal(t, blocks[1], chunks[0].End)
+	require.Len(t, chunks[0].Blocks, 1)
+	require.Equal(t, blocks[0], chunks[0].Blocks[0].Block)
+	require.Len(t, chunks[0].Blocks[0].Logs, 2)
+	require.Equal(t, uint(0), chunks[0].Blocks[0].Logs[0].Index)
+
+	// The end block must be in the blocks file.
+	err = source.Fetch(context.Background(), 1, 8, func(chunk LogChunk) error { return nil })
+	require.ErrorContains(t, err, "block 8 is missing")
+
+	t.Run("missing block", func(t *testing.T) {
+		_, err := newFileLogSource(zap.NewNop(), []types.Log{testLog(t, 7, testHash5, 0)}, blocks, testContract)
+		require.ErrorContains(t, err, "block 7 of log 0 is missing")
+	})
+	t.Run("mismatching hash", func(t *testing.T) {
+		_, err := newFileLogSource(zap.NewNop(), []types.Log{testLog(t, 5, testHash9, 0)}, blocks, testContract)
+		require.ErrorContains(t, err, "doesn't match")
+	})
+	t.Run("duplicate log", func(t *testing.T) {
+		log := testLog(t, 5, testHash5, 0)
+		_, err := newFileLogSource(zap.NewNop(), []types.Log{log, log}, blocks, testContract)
+		require.ErrorContains(t, err, "duplicate log")
+	})
+}
@@ -3,10 +3,15 @@ package beaconcha
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
-	"sync"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -14,6 +19,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
 	"github.com/bloxapp/ssv-rewards/pkg/sync/httpretry"
 	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
 )
@@ -23,31 +29,52 @@ const (
 	// First day of the Incentivized Mainnet Program (IMP), corresponds to 2023-07-01
 	// Used as the start_day parameter when querying the Beaconcha.in API for validator stats.
 	startDay = "941"
+
+	// APIv1 fetches a validator's whole history since startDay.
+	APIv1 APIVersion = "v1"
+	// APIv2 fetches a validator's days from the requested day onwards.
+	APIv2 APIVersion = "v2"
+
+	// maxRateLimitRetries is how many times a request is retried after exceeding
+	// the rate limit, possibly with other API keys.
+	maxRateLimitRetries = 10
 )
 
+// APIVersion is the version of the beaconcha.in API to fetch validator stats from.
+type APIVersion string
+
 type Client struct {
-	endpoint string
-	apiKey   string
-	ticker   *time.Ticker
-	cacheDir string
+	endpoint  string
+	version   APIVersion
+	limiter   *limiter
+	cache     *cache.Cache
+	batchSize atomic.Int64
 
-	memCache map[phase0.ValidatorIndex]map[time.Time]dailyData
-	cacheMu  sync.RWMutex
+	// batched is whether a batched request succeeded, which proves that the API
+	// supports them.
+	batched atomic.Bool
 }
 
-func New(endpoint string, apiKey string, requestsPerMinute float64, cacheDir string) (*Client, error) {
-	err := os.MkdirAll(cacheDir, 0755)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create cache directory: %w", err)
-	} // ensure cache directory exists
-
-	return &Client{
+// New creates a beaconcha.in client. Requests are spread across the given API keys,
+// each limited to requestsPerMinute or the lower limit that the API reports for it.
+// Validators are fetched batchSize at a time when prepared for a day, or one at a time
+// if batchSize is 1.
+func New(
+	endpoint string,
+	version APIVersion,
+	apiKeys []string,
+	requestsPerMinute float64,
+	batchSize int,
+	cache *cache.Cache,
+) *Client {
+	c := &Client{
 		endpoint: endpoint,
-		apiKey:   apiKey,
-		ticker:   time.NewTicker(time.Duration(float64(time.Minute) / requestsPerMinute)),
-		cacheDir: cacheDir,
-		memCache: make(map[phase0.ValidatorIndex]map[time.Time]dailyData),
-	}, nil
+		version:  version,
+		limiter:  newLimiter(apiKeys, requestsPerMinute),
+		cache:    cache,
+	}
+	c.batchSize.Store(int64(max(batchSize, 1)))
+	return c
 }
 
 func (m *Client) Type() performance.ProviderType {
@@ -64,123 +91,291 @@ func (m *Client) ValidatorPerformance(
 ) (*performance.ValidatorPerformance, error) {
 	dayKey := day.UTC().Truncate(24 * time.Hour)
 
-	// 1. Try memory cache
-	m.cacheMu.RLock()
-	if byDay, ok := m.memCache[index]; ok {
-		if d, ok := byDay[dayKey]; ok {
-			m.cacheMu.RUnlock()
-			return convertToPerformance(spec, d, fromEpoch, toEpoch, activationEpoch, exitEpoch), nil
-		}
+	// 1. Try cache
+	d, ok, err := m.cached(logger, index, dayKey)
+	if err != nil {
+		return nil, err
 	}
-	m.cacheMu.RUnlock()
-
-	// 2. Try file cache
-	cachedItem, err := m.loadCache(index)
-	if err == nil {
-		// Use cache only if it was fetched *after* the requested day + 48h
-		if cachedItem.Time.After(dayKey.Add(48 * time.Hour)) {
-			// Load into memory cache AND check for target dayKey in a single pass
-			var found *dailyData
-
-			m.cacheMu.Lock()
-			if _, exists := m.memCache[index]; !exists {
-				m.memCache[index] = make(map[time.Time]dailyData)
-			}
-			for _, d := range cachedItem.Data {
-				k := d.DayStart.UTC().Truncate(24 * time.Hour)
-				m.memCache[index][k] = d
-				if k.Equal(dayKey) {
-					dCopy := d
-					found = &dCopy
-				}
+	if !ok {
+		// 2. Fetch fresh from API
+		fetched, err := m.fetch(ctx, logger, spec, dayKey, []phase0.ValidatorIndex{index})
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range fetched[index] {
+			if data.DayStart.UTC().Truncate(24 * time.Hour).Equal(dayKey) {
+				d = &data
+				break
 			}
-			m.cacheMu.Unlock()
+		}
+	}
+	if d == nil {
+		// Requested day not found
+		return nil, nil
+	}
+	return convertToPerformance(spec, *d, fromEpoch, toEpoch, activationEpoch, exitEpoch), nil
+}
 
-			if found != nil {
-				return convertToPerformance(spec, *found, fromEpoch, toEpoch, activationEpoch, exitEpoch), nil
-			} else {
-				return nil, nil
-			}
-		} else {
-			logger.Info("BEACONCHA cache is stale", zap.String("index", fmt.Sprintf("%d", index)))
+// Prepare fetches the validators that aren't cached for a day in batches, so that
+// their performance is then served from the cache.
+func (m *Client) Prepare(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) error {
+	dayKey := day.UTC().Truncate(24 * time.Hour)
+	var missing []phase0.ValidatorIndex
+	for _, index := range indices {
+		_, ok, err := m.cached(logger, index, dayKey)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			missing = append(missing, index)
 		}
 	}
+	if len(missing) == 0 || m.batchSize.Load() == 1 {
+		return nil
+	}
+	slices.Sort(missing)
 
-	// 3. Fetch fresh from API
-	ctx, cancel := context.WithTimeout(ctx, 90*time.Second)
-	defer cancel()
+	start := time.Now()
+	for len(missing) > 0 {
+		batchSize := int(m.batchSize.Load())
+		if batchSize == 1 {
+			// Batching was disabled, so the rest is fetched per validator.
+			return nil
+		}
+		batch := missing[:min(batchSize, len(missing))]
+		missing = missing[len(batch):]
+		if _, err := m.fetch(ctx, logger, spec, dayKey, batch); err != nil {
+			return err
+		}
+	}
+	logger.Debug("Prefetched validator performance from beaconcha.in",
+		zap.Int("validators", len(indices)),
+		zap.Duration("took", time.Since(start)),
+	)
+	return nil
+}
 
-	// Rate-limiting
-	select {
-	case <-m.ticker.C:
-	case <-ctx.Done():
-		return nil, ctx.Err()
+// cached returns a validator's data of a day from the cache. ok is false if the
+// validator isn't cached or its cache is stale, and d is nil if the validator is
+// cached without data for the day.
+func (m *Client) cached(logger *zap.Logger, index phase0.ValidatorIndex, dayKey time.Time) (d *dailyData, ok bool, err error) {
+	// Use cache only if it was fetched *after* the requested day + 48h
+	fresh := func(fetched time.Time) bool {
+		return fetched.After(dayKey.Add(48 * time.Hour))
 	}
 
-	var resp response
-	err = requests.URL(m.endpoint).
-		Client(httpretry.Client).
-		Pathf("/api/v1/validator/stats/%d", index).
-		Param("apikey", m.apiKey).
-		Param("start_day", startDay).
-		ToJSON(&resp).
-		Fetch(ctx)
+	var data dailyData
+	fetched, found, err := m.cache.Get(string(ProviderType), uint64(index), dayKey, &data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch validator performance: %w", err)
+		return nil, false, err
 	}
-	if resp.Status != "OK" {
-		return nil, fmt.Errorf("failed to fetch validator performance: %s", resp.Status)
+	if found && fresh(fetched) {
+		return &data, true, nil
 	}
 
-	// Save fresh data to cache immediately
-	newCache := cacheItem{
-		Time: time.Now(),
-		Data: resp.Data,
+	// The validator may have been fetched without data for the day.
+	var fetchedDays fetchedRange
+	fetched, found, err = m.cache.Get(string(ProviderType), uint64(index), fetchedDay, &fetchedDays)
+	if err != nil {
+		return nil, false, err
 	}
-	if saveErr := m.saveCache(index, newCache); saveErr != nil {
-		logger.Error("failed to save cache", zap.Error(saveErr))
+	if !found || dayKey.Before(fetchedDays.From) {
+		return nil, false, nil
 	}
+	if !fresh(fetched) {
+		logger.Info("BEACONCHA cache is stale", zap.String("index", fmt.Sprintf("%d", index)))
+		return nil, false, nil
+	}
+	return nil, true, nil
+}
 
-	// Update in-memory cache
-	m.cacheMu.Lock()
-	if _, exists := m.memCache[index]; !exists {
-		m.memCache[index] = make(map[time.Time]dailyData)
+// Invalidate removes a day of the given validators from the cache, along with when
+// they were fetched, so that they're fetched again.
+func (m *Client) Invalidate(day time.Time, indices []phase0.ValidatorIndex) error {
+	dayKey := day.UTC().Truncate(24 * time.Hour)
+	for _, index := range indices {
+		if err := m.cache.Delete(string(ProviderType), uint64(index), dayKey, fetchedDay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch fetches the stats of the given validators in a single request, and caches them.
+// With APIv2, only the days from the given day onwards are fetched.
+// If the API rejects the first batch as unsupported, batching is disabled and the
+// validators are left to be fetched individually.
+func (m *Client) fetch(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	fromDay time.Time,
+	indices []phase0.ValidatorIndex,
+) (map[phase0.ValidatorIndex][]dailyData, error) {
+	var fetchedDays fetchedRange
+	if m.version == APIv2 {
+		fetchedDays.From = fromDay
+	}
+	formatted := make([]string, len(indices))
+	for i, index := range indices {
+		formatted[i] = strconv.FormatUint(uint64(index), 10)
+	}
+	var (
+		resp response
+		err  error
+	)
+	for attempt := 0; ; attempt++ {
+		// Rate-limiting
+		key, acquireErr := m.limiter.acquire(ctx, logger)
+		if acquireErr != nil {
+			return nil, acquireErr
+		}
+
+		header := http.Header{}
+		reqCtx, cancel := context.WithTimeout(ctx, 90*time.Second)
+		rb := requests.URL(m.endpoint).
+			Client(httpretry.RateLimitAwareClient)
+		switch m.version {
+		case APIv2:
+			// The day before is included, as beaconcha.in's days start at the genesis time.
+			rb.Pathf("/api/v2/validator/stats/%s", strings.Join(formatted, ",")).
+				Param("start_day", strconv.Itoa(max(dayNumber(spec, fromDay)-1, 0))).
+				Param("end_day", strconv.Itoa(dayNumber(spec, time.Now())))
+			if key.key != "" {
+				rb.Bearer(key.key)
+			}
+		default:
+			rb.Pathf("/api/v1/validator/stats/%s", strings.Join(formatted, ",")).
+				Param("apikey", key.key).
+				Param("start_day", startDay)
+		}
+		err = rb.
+			CopyHeaders(header).
+			CheckStatus(http.StatusOK).
+			ToJSON(&resp).
+			Fetch(reqCtx)
+		cancel()
+
+		statusCode := http.StatusOK
+		var respErr *requests.ResponseError
+		if errors.As(err, &respErr) {
+			statusCode = respErr.StatusCode
+		}
+		m.limiter.update(logger, key, statusCode, header)
+		if statusCode != http.StatusTooManyRequests || attempt == maxRateLimitRetries {
+			break
+		}
+	}
+	if err == nil && resp.Status != "OK" {
+		err = fmt.Errorf("%s", resp.Status)
+	}
+	if err != nil {
+		if len(indices) > 1 && !m.batched.Load() &&
+			(requests.HasStatusErr(err, http.StatusBadRequest) || requests.HasStatusErr(err, http.StatusNotFound)) {
+			logger.Warn("beaconcha.in rejected batched request, fetching validators individually",
+				zap.Int("batch_size", len(indices)),
+				zap.Error(err),
+			)
+			m.batchSize.Store(1)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch validator performance: %w", err)
+	}
+
+	if len(indices) > 1 {
+		m.batched.Store(true)
+	}
+
+	// Group the data by validator. A validator fetched on its own without data is
+	// cached as such, so that it's not fetched again. Validators missing from a batch
+	// aren't cached, since the response may have been cut short, and are fetched
+	// on their own instead.
+	byIndex := make(map[phase0.ValidatorIndex][]dailyData, len(indices))
+	if len(indices) == 1 {
+		byIndex[indices[0]] = nil
 	}
 	for _, d := range resp.Data {
-		k := d.DayStart.UTC().Truncate(24 * time.Hour)
-		m.memCache[index][k] = d
+		index := phase0.ValidatorIndex(d.ValidatorIndex)
+		if len(indices) == 1 {
+			index = indices[0]
+		} else if !slices.Contains(indices, index) {
+			continue
+		}
+		byIndex[index] = append(byIndex[index], d)
 	}
-	d, ok := m.memCache[index][dayKey]
-	m.cacheMu.Unlock()
 
-	if ok {
-		return convertToPerformance(spec, d, fromEpoch, toEpoch, activationEpoch, exitEpoch), nil
+	now := time.Now()
+	for index, data := range byIndex {
+		if err := m.store(index, now, fetchedDays, data); err != nil {
+			logger.Error("failed to save cache", zap.Error(err))
+		}
 	}
+	return byIndex, nil
+}
 
-	// Requested day not found even after fetching fresh data
-	return nil, nil
+// fetchedDay is the day under which the time a validator was fetched is cached,
+// along with the fetchedRange.
+var fetchedDay = time.Time{}
+
+// fetchedRange is the range of days that was fetched of a validator. From is zero
+// if its whole history was fetched.
+type fetchedRange struct {
+	From time.Time `json:"from,omitempty"`
 }
 
-func (m *Client) cacheFilePath(index phase0.ValidatorIndex) string {
-	return filepath.Join(m.cacheDir, fmt.Sprintf("%d.json", index))
+// dayNumber returns beaconcha.in's number of the day at the given time, counting
+// days from the genesis.
+func dayNumber(spec beacon.Spec, t time.Time) int {
+	return int(t.Sub(spec.GenesisTime) / (24 * time.Hour))
 }
 
-func (m *Client) loadCache(index phase0.ValidatorIndex) (*cacheItem, error) {
-	data, err := os.ReadFile(m.cacheFilePath(index))
-	if err != nil {
-		return nil, err
+// store caches the days of a validator, along with the time it was fetched.
+func (m *Client) store(
+	index phase0.ValidatorIndex,
+	fetched time.Time,
+	fetchedDays fetchedRange,
+	data []dailyData,
+) error {
+	entries := make([]cache.Entry, 0, len(data)+1)
+	for _, d := range data {
+		entries = append(entries, cache.Entry{Day: d.DayStart, Value: d})
 	}
-	var item cacheItem
-	err = json.Unmarshal(data, &item)
-	return &item, err
+	entries = append(entries, cache.Entry{Day: fetchedDay, Value: fetchedDays})
+	return m.cache.Set(string(ProviderType), uint64(index), fetched, entries...)
 }
 
-func (m *Client) saveCache(index phase0.ValidatorIndex, item cacheItem) error {
-	data, err := json.MarshalIndent(item, "", "  ")
+// ImportCacheDir imports the JSON files of validators that were cached before the
+// cache was a key-value store, and returns the number of imported validators.
+func (m *Client) ImportCacheDir(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
 	if err != nil {
-		return err
+		return 0, err
+	}
+	for _, file := range files {
+		index, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(file), ".json"), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cache file name %q: %w", file, err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		var item cacheItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return 0, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		if err := m.store(phase0.ValidatorIndex(index), item.Time, fetchedRange{}, item.Data); err != nil {
+			return 0, err
+		}
 	}
-	return os.WriteFile(m.cacheFilePath(index), data, 0644)
+	return len(files), nil
 }
 
 type cacheItem struct {
@@ -193,7 +388,7 @@ func convertToPerformance(
 	d dailyData,
 	fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
 ) *performance.ValidatorPerformance {
-	activeEpochs := deriveActiveEpochs(spec, fromEpoch, toEpoch, activationEpoch, exitEpoch)
+	activeEpochs := performance.ActiveEpochs(spec, fromEpoch, toEpoch, activationEpoch, exitEpoch)
 
 	p := &performance.ValidatorPerformance{
 		Attestations: performance.DutyPerformance{
@@ -252,24 +447,3 @@ type dailyData struct {
 	Withdrawals           uint64    `json:"withdrawals"`
 	WithdrawalsAmount     uint64    `json:"withdrawals_amount"`
 }
-
-func deriveActiveEpochs(
-	spec beacon.Spec, fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
-) phase0.Epoch {
-	activeEpochs := toEpoch - fromEpoch + 1
-	if activationEpoch > fromEpoch {
-		if activationEpoch > toEpoch {
-			activeEpochs = 0
-		} else {
-			activeEpochs -= activationEpoch - fromEpoch
-		}
-	}
-	if exitEpoch != spec.FarFutureEpoch && exitEpoch <= toEpoch {
-		if exitEpoch <= fromEpoch {
-			activeEpochs = 0
-		} else {
-			activeEpochs -= toEpoch - exitEpoch + 1
-		}
-	}
-	return activeEpochs
-}
@@ -1,100 +1,269 @@
 package beaconcha
 
 import (
+	"context"
+	"encoding/json"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
-	"github.com/bloxapp/ssv-rewards/pkg/beacon"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
 )
 
-func TestDeriveActiveEpochs(t *testing.T) {
+// mockBeaconchaAPI serves the stats of validators 1 and 2 on the given day, and
+// records the requested indices. Validator 3 has no stats. Requests for multiple
+// validators are rejected unless batched is true.
+func mockBeaconchaAPI(t *testing.T, day time.Time, batched bool) (*httptest.Server, func() []string) {
+	t.Helper()
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/validator/stats/{indices}", func(w http.ResponseWriter, r *http.Request) {
+		indices := r.PathValue("indices")
+		mu.Lock()
+		requested = append(requested, indices)
+		mu.Unlock()
+		if !batched && strings.Contains(indices, ",") {
+			http.Error(w, "invalid validator index", http.StatusBadRequest)
+			return
+		}
+		var data []dailyData
+		for _, s := range strings.Split(indices, ",") {
+			index, err := strconv.Atoi(s)
+			require.NoError(t, err)
+			if index > 2 {
+				continue
+			}
+			data = append(data,
+				dailyData{ValidatorIndex: index, DayStart: day.AddDate(0, 0, -1), MissedAttestations: 100},
+				dailyData{
+					ValidatorIndex:      index,
+					DayStart:            day,
+					MissedAttestations:  index,
+					ProposedBlocks:      1,
+					EndEffectiveBalance: 32_000_000_000,
+				},
+			)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response{Status: "OK", Data: data}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func TestClientPrepare(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	indices := []phase0.ValidatorIndex{3, 1, 2}
+
+	for _, batched := range []bool{true, false} {
+		t.Run("batched="+strconv.FormatBool(batched), func(t *testing.T) {
+			server, requested := mockBeaconchaAPI(t, day, batched)
+			kvCache, err := cache.NewInMemory(logger)
+			require.NoError(t, err)
+			t.Cleanup(func() { kvCache.Close() })
+			client := New(server.URL, APIv1, nil, 60_000, 2, kvCache)
+
+			require.NoError(t, client.Prepare(ctx, logger, spec, day, 0, 224, indices))
+			for _, index := range indices {
+				p, err := client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, 0, spec.FarFutureEpoch, index)
+				require.NoError(t, err)
+				if index == 3 {
+					require.Nil(t, p)
+					continue
+				}
+				require.NotNil(t, p)
+				require.Equal(t, int16(225), p.Attestations.Assigned)
+				require.Equal(t, int16(index), p.Attestations.Missed)
+				require.Equal(t, int16(1), p.Proposals.Executed)
+				require.Equal(t, int64(32_000_000_000), p.EndEffectiveBalance)
+			}
+			if batched {
+				require.Equal(t, []string{"1,2", "3"}, requested())
+			} else {
+				// The rejected batch disables batching for the rest of the run.
+				require.Equal(t, []string{"1,2", "3", "1", "2"}, requested())
+			}
+
+			// Prepared validators are cached.
+			require.NoError(t, client.Prepare(ctx, logger, spec, day, 0, 224, indices))
+			require.Len(t, requested(), map[bool]int{true: 2, false: 4}[batched])
+
+			// Invalidated validators are fetched again.
+			require.NoError(t, client.Invalidate(day, []phase0.ValidatorIndex{1}))
+			p, err := client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, 0, spec.FarFutureEpoch, 1)
+			require.NoError(t, err)
+			require.Equal(t, int16(1), p.Attestations.Missed)
+			require.Equal(t, "1", requested()[len(requested())-1])
+			require.Len(t, requested(), map[bool]int{true: 3, false: 5}[batched])
+		})
+	}
+}
+
+func TestClientPrepareMissingFromBatch(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	server, requested := mockBeaconchaAPI(t, day, true)
+	kvCache, err := cache.NewInMemory(logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { kvCache.Close() })
+	client := New(server.URL, APIv1, nil, 60_000, 3, kvCache)
+
+	// Validator 3 is missing from the batch, so it isn't cached as without data.
+	require.NoError(t, client.Prepare(ctx, logger, spec, day, 0, 224, []phase0.ValidatorIndex{1, 2, 3}))
+	require.Equal(t, []string{"1,2,3"}, requested())
+	_, ok, err := client.cached(logger, 3, day)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// It's fetched on its own instead, and then cached as without data.
+	p, err := client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, 0, spec.FarFutureEpoch, 3)
+	require.NoError(t, err)
+	require.Nil(t, p)
+	require.NoError(t, client.Prepare(ctx, logger, spec, day, 0, 224, []phase0.ValidatorIndex{1, 2, 3}))
+	p, err = client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, 0, spec.FarFutureEpoch, 3)
+	require.NoError(t, err)
+	require.Nil(t, p)
+	require.Equal(t, []string{"1,2,3", "3"}, requested())
+}
+
+func TestClientPrepareBatchErrors(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	newClient := func(t *testing.T, handler http.HandlerFunc) *Client {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		kvCache, err := cache.NewInMemory(logger)
+		require.NoError(t, err)
+		t.Cleanup(func() { kvCache.Close() })
+		return New(server.URL, APIv1, nil, 60_000, 2, kvCache)
+	}
+
+	// Failures other than a rejected first batch don't disable batching.
+	client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(response{Status: "ERROR: internal error"}))
+	})
+	require.Error(t, client.Prepare(ctx, logger, spec, day, 0, 224, []phase0.ValidatorIndex{1, 2}))
+	require.Equal(t, int64(2), client.batchSize.Load())
+
+	// Once a batch succeeded, later rejected batches are errors.
+	var requests int
+	client = newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			http.Error(w, "invalid validator index", http.StatusBadRequest)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response{Status: "OK"}))
+	})
+	require.Error(t, client.Prepare(ctx, logger, spec, day, 0, 224, []phase0.ValidatorIndex{1, 2, 3, 4}))
+	require.Equal(t, 2, requests)
+	require.Equal(t, int64(2), client.batchSize.Load())
+}
+
+func TestClientImportCacheDir(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	item := cacheItem{
+		Time: day.AddDate(0, 0, 3),
+		Data: []dailyData{{ValidatorIndex: 7, DayStart: day, MissedAttestations: 5}},
+	}
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "7.json"), data, 0644))
+
+	kvCache, err := cache.NewInMemory(logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { kvCache.Close() })
+	client := New("http://invalid", APIv1, nil, 60_000, 1, kvCache)
+	n, err := client.ImportCacheDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// Imported validators are served from the cache, with or without data for the day.
+	p, err := client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, 0, spec.FarFutureEpoch, 7)
+	require.NoError(t, err)
+	require.Equal(t, int16(5), p.Attestations.Missed)
+	p, err = client.ValidatorPerformance(ctx, logger, spec, day.AddDate(0, 0, -1), 0, 224, 0, spec.FarFutureEpoch, 7)
+	require.NoError(t, err)
+	require.Nil(t, p)
+}
+
+func TestClientAPIv2(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
 	spec := beacon.Spec{
+		GenesisTime:    time.Date(2020, 12, 1, 12, 0, 23, 0, time.UTC),
+		SlotsPerEpoch:  32,
+		SlotDuration:   12 * time.Second,
 		FarFutureEpoch: math.MaxUint64,
 	}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var startDays []int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/validator/stats/{indices}", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer key-a", r.Header.Get("Authorization"))
+		require.Equal(t, "1", r.PathValue("indices"))
+		startDay, err := strconv.Atoi(r.URL.Query().Get("start_day"))
+		require.NoError(t, err)
+		startDays = append(startDays, startDay)
+		require.NoError(t, json.NewEncoder(w).Encode(response{Status: "OK", Data: []dailyData{
+			{ValidatorIndex: 1, DayStart: day, MissedAttestations: 1},
+		}}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	kvCache, err := cache.NewInMemory(logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { kvCache.Close() })
+	client := New(server.URL, APIv2, []string{"key-a"}, 60_000, 1, kvCache)
+	validatorPerformance := func(day time.Time) *performance.ValidatorPerformance {
+		p, err := client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, 0, spec.FarFutureEpoch, 1)
+		require.NoError(t, err)
+		return p
+	}
+
+	// Only the days from the requested day onwards are fetched.
+	require.Equal(t, int16(1), validatorPerformance(day).Attestations.Missed)
+	require.Equal(t, []int{dayNumber(spec, day) - 1}, startDays)
 
-	// Activated at exactly the start of the period.
-	require.Equal(t, phase0.Epoch(225), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(400), phase0.Epoch(math.MaxUint64), // activation/exit
-	))
-
-	// Activated at exactly the end of the period.
-	require.Equal(t, phase0.Epoch(1), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(624), phase0.Epoch(math.MaxUint64), // activation/exit
-	))
-
-	// Activated before the period.
-	require.Equal(t, phase0.Epoch(225), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(320), phase0.Epoch(math.MaxUint64), // activation/exit
-	))
-
-	// Activated during the period.
-	require.Equal(t, phase0.Epoch(200), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(425), phase0.Epoch(math.MaxUint64), // activation/exit
-	))
-
-	// Activated after the period.
-	require.Equal(t, phase0.Epoch(0), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(700), phase0.Epoch(math.MaxUint64), // activation/exit
-	))
-
-	// Activated during the period, exited during the period.
-	require.Equal(t, phase0.Epoch(175), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(425), phase0.Epoch(600), // activation/exit
-	))
-
-	// Activated before the period, exited during the period.
-	require.Equal(t, phase0.Epoch(200), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(320), phase0.Epoch(600), // activation/exit
-	))
-
-	// Activated during the period, exited after the period.
-	require.Equal(t, phase0.Epoch(200), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(425), phase0.Epoch(700), // activation/exit
-	))
-
-	// Activated before the period, exited right after the period.
-	require.Equal(t, phase0.Epoch(225), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(320), phase0.Epoch(625), // activation/exit
-	))
-
-	// Activated before the period, exited long after the period.
-	require.Equal(t, phase0.Epoch(225), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(320), phase0.Epoch(700), // activation/exit
-	))
-
-	// Activated before the period, exited before the period.
-	require.Equal(t, phase0.Epoch(0), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(320), phase0.Epoch(350), // activation/exit
-	))
-
-	// Activated during the period, exited at exactly the end of the period.
-	require.Equal(t, phase0.Epoch(199), deriveActiveEpochs(
-		spec,
-		phase0.Epoch(400), phase0.Epoch(624), // from/to
-		phase0.Epoch(425), phase0.Epoch(624), // activation/exit
-	))
+	// Later days are covered by the fetched range, earlier days aren't.
+	require.Nil(t, validatorPerformance(day.AddDate(0, 0, 1)))
+	require.Len(t, startDays, 1)
+	require.Nil(t, validatorPerformance(day.AddDate(0, 0, -10)))
+	require.Equal(t, []int{dayNumber(spec, day) - 1, dayNumber(spec, day) - 11}, startDays)
 }
@@ -0,0 +1,185 @@
+package beaconcha
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// rateLimitWindows are the windows of beaconcha.in's X-Ratelimit-Remaining-* headers,
+// with how long to wait when one is exhausted and X-Ratelimit-Reset is missing.
+var rateLimitWindows = []struct {
+	name  string
+	reset time.Duration
+	quota bool
+}{
+	{"Second", time.Second, false},
+	{"Minute", time.Minute, false},
+	{"Hour", time.Hour, true},
+	{"Day", 24 * time.Hour, true},
+	{"Month", 24 * time.Hour, true},
+}
+
+// limiter is a token bucket per API key, which spreads requests across the keys and
+// follows the rate limits that beaconcha.in reports in its response headers.
+type limiter struct {
+	mu   sync.Mutex
+	keys []*apiKey
+	next int
+}
+
+// apiKey is the token bucket of an API key.
+type apiKey struct {
+	key    string
+	rate   float64 // Tokens per second.
+	tokens float64
+	last   time.Time
+
+	// blockedUntil is when the rate limit or quota that the API reported as reached resets.
+	blockedUntil time.Time
+}
+
+func newLimiter(keys []string, requestsPerMinute float64) *limiter {
+	if len(keys) == 0 {
+		keys = []string{""}
+	}
+	l := &limiter{}
+	now := time.Now()
+	for _, key := range keys {
+		l.keys = append(l.keys, &apiKey{
+			key:    key,
+			rate:   requestsPerMinute / 60,
+			tokens: 1,
+			last:   now,
+		})
+	}
+	return l
+}
+
+// String identifies the key in logs without revealing it.
+func (k *apiKey) String() string {
+	if len(k.key) <= 4 {
+		return "anonymous"
+	}
+	return "..." + k.key[len(k.key)-4:]
+}
+
+func (k *apiKey) refill(now time.Time) {
+	k.tokens = min(1, k.tokens+now.Sub(k.last).Seconds()*k.rate)
+	k.last = now
+}
+
+// acquire waits for a key with a token, trying the keys in turn.
+func (l *limiter) acquire(ctx context.Context, logger *zap.Logger) (*apiKey, error) {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		wait := time.Duration(-1)
+		for i := range l.keys {
+			pos := (l.next + i) % len(l.keys)
+			k := l.keys[pos]
+			k.refill(now)
+			var keyWait time.Duration
+			switch {
+			case now.Before(k.blockedUntil):
+				keyWait = k.blockedUntil.Sub(now)
+			case k.tokens >= 1:
+				k.tokens--
+				l.next = (pos + 1) % len(l.keys)
+				l.mu.Unlock()
+				return k, nil
+			default:
+				keyWait = time.Duration((1 - k.tokens) / k.rate * float64(time.Second))
+			}
+			if wait < 0 || keyWait < wait {
+				wait = keyWait
+			}
+		}
+		l.mu.Unlock()
+
+		if wait >= time.Minute {
+			logger.Warn("All beaconcha.in API keys are rate limited, waiting",
+				zap.Int("keys", len(l.keys)),
+				zap.Duration("wait", wait),
+				zap.Time("until", now.Add(wait)),
+			)
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// update adapts a key's bucket to the rate limits in the headers of its response.
+func (l *limiter) update(logger *zap.Logger, k *apiKey, statusCode int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	if limit, ok := headerInt(header, "X-Ratelimit-Limit-Minute"); ok && limit > 0 {
+		if rate := float64(limit) / 60; rate < k.rate {
+			logger.Info("Lowering beaconcha.in request rate to the API key's limit",
+				zap.Stringer("key", k),
+				zap.Int("requests_per_minute", limit),
+			)
+			k.rate = rate
+		}
+	}
+
+	block := func(until time.Time) {
+		if until.After(k.blockedUntil) {
+			k.blockedUntil = until
+		}
+		k.tokens = 0
+	}
+	reset, hasReset := headerInt(header, "X-Ratelimit-Reset")
+	for _, window := range rateLimitWindows {
+		remaining, ok := headerInt(header, "X-Ratelimit-Remaining-"+window.name)
+		if !ok || remaining > 0 {
+			continue
+		}
+		until := now.Add(window.reset)
+		if hasReset {
+			until = now.Add(time.Duration(reset) * time.Second)
+		}
+		if window.quota && until.After(k.blockedUntil) {
+			logger.Warn("beaconcha.in API key quota exhausted",
+				zap.Stringer("key", k),
+				zap.String("window", window.name),
+				zap.Time("until", until),
+			)
+		}
+		block(until)
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		retryAfter := time.Minute
+		if v := header.Get("Retry-After"); v != "" {
+			if seconds, err := strconv.Atoi(v); err == nil {
+				retryAfter = time.Duration(seconds) * time.Second
+			} else if t, err := http.ParseTime(v); err == nil {
+				retryAfter = t.Sub(now)
+			}
+		}
+		logger.Warn("beaconcha.in rate limit exceeded",
+			zap.Stringer("key", k),
+			zap.Duration("retry_after", retryAfter),
+		)
+		block(now.Add(retryAfter))
+	}
+}
+
+func headerInt(header http.Header, key string) (int, bool) {
+	v := header.Get(key)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
@@ -0,0 +1,76 @@
+package beaconcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	l := newLimiter([]string{"key-a", "key-b"}, 60_000)
+
+	// Requests are spread across the keys.
+	acquire := func() string {
+		k, err := l.acquire(ctx, logger)
+		require.NoError(t, err)
+		return k.key
+	}
+	require.Equal(t, "key-a", acquire())
+	require.Equal(t, "key-b", acquire())
+
+	// A key that exceeded its rate limit is skipped until Retry-After.
+	l.update(logger, l.keys[0], http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}})
+	require.Equal(t, "key-b", acquire())
+	require.Equal(t, "key-b", acquire())
+
+	// The API's limit lowers the key's rate.
+	l.update(logger, l.keys[1], http.StatusOK, http.Header{"X-Ratelimit-Limit-Minute": {"30"}})
+	require.Equal(t, 0.5, l.keys[1].rate)
+	l.update(logger, l.keys[1], http.StatusOK, http.Header{"X-Ratelimit-Limit-Minute": {"600"}})
+	require.Equal(t, 0.5, l.keys[1].rate)
+
+	// A key with an exhausted quota is skipped until it resets.
+	l.update(logger, l.keys[1], http.StatusOK, http.Header{
+		"X-Ratelimit-Remaining-Day": {"0"},
+		"X-Ratelimit-Reset":         {"7200"},
+	})
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), l.keys[1].blockedUntil, time.Minute)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := l.acquire(timeoutCtx, logger)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClientRateLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/validator/stats/{indices}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") == "key-a" {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response{Status: "OK"}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	kvCache, err := cache.NewInMemory(zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { kvCache.Close() })
+	client := New(server.URL, APIv1, []string{"key-a", "key-b"}, 60_000, 1, kvCache)
+	_, err = client.fetch(context.Background(), zap.NewNop(), beacon.Spec{}, time.Time{}, []phase0.ValidatorIndex{1})
+	require.NoError(t, err)
+	require.True(t, client.limiter.keys[0].blockedUntil.After(time.Now().Add(time.Hour-time.Minute)))
+}
@@ -0,0 +1,640 @@
+// Package beaconnode provides validator performance from a beacon node's standard API,
+// without depending on external monitoring services.
+//
+// Attestations are derived from the attestation rewards of each epoch, falling back
+// to validator liveness for epochs the node can't compute rewards for. Proposals and
+// sync committee participation are derived from the proposer and sync committee duties,
+// and the blocks and sync committee rewards of the assigned slots. The end effective
+// balance is read from the state at the day's last slot.
+//
+// Historical days require an archive node, since rewards and duties are computed from
+// the state at each epoch.
+package beaconnode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/carlmjohnson/requests"
+	"github.com/sourcegraph/conc/pool"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/httpretry"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
+)
+
+const (
+	ProviderType performance.ProviderType = "beaconnode"
+
+	// indicesPerRequest is the maximum number of validator indices per request.
+	indicesPerRequest = 1000
+
+	// workers is the number of epochs fetched concurrently.
+	workers = 8
+)
+
+type Client struct {
+	endpoint string
+	client   *http.Client
+	cache    *cache.Cache
+	fetching singleflight.Group
+}
+
+// New creates a beacon node client, which caches the performance of each validator
+// in a day.
+func New(endpoint string, cache *cache.Cache) *Client {
+	return &Client{
+		endpoint: endpoint,
+		client:   httpretry.Client,
+		cache:    cache,
+	}
+}
+
+func (c *Client) Type() performance.ProviderType {
+	return ProviderType
+}
+
+// cacheItem is a validator's performance in a day, along with the day's epochs it
+// was fetched for.
+type cacheItem struct {
+	FromEpoch phase0.Epoch   `json:"from_epoch"`
+	ToEpoch   phase0.Epoch   `json:"to_epoch"`
+	Validator *validatorData `json:"validator"`
+}
+
+// validatorData is a validator's performance in a day. Missed attestations are
+// recorded by epoch, so that epochs in which the validator wasn't active can be
+// told apart when it's converted.
+type validatorData struct {
+	Exists              bool           `json:"exists"`
+	MissedAttestations  []phase0.Epoch `json:"missed_attestations,omitempty"`
+	ProposedBlocks      int16          `json:"proposed_blocks,omitempty"`
+	MissedBlocks        int16          `json:"missed_blocks,omitempty"`
+	ParticipatedSync    int16          `json:"participated_sync,omitempty"`
+	MissedSync          int16          `json:"missed_sync,omitempty"`
+	EndEffectiveBalance int64          `json:"end_effective_balance,omitempty"`
+}
+
+func (c *Client) ValidatorPerformance(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
+	index phase0.ValidatorIndex,
+) (*performance.ValidatorPerformance, error) {
+	err := c.Prepare(ctx, logger, spec, day, fromEpoch, toEpoch, []phase0.ValidatorIndex{index})
+	if err != nil {
+		return nil, err
+	}
+	v, _, err := c.cached(dayKey(day), fromEpoch, toEpoch, index)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || !v.Exists {
+		return nil, nil
+	}
+	return convertToPerformance(spec, v, fromEpoch, toEpoch, activationEpoch, exitEpoch), nil
+}
+
+// Prepare fetches the performance of the given validators in a day, unless it's cached.
+func (c *Client) Prepare(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) error {
+	key := dayKey(day)
+	var missing []phase0.ValidatorIndex
+	for _, index := range indices {
+		_, ok, err := c.cached(key, fromEpoch, toEpoch, index)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			missing = append(missing, index)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+
+	// Concurrent requests for the same validators are fetched once.
+	flight := fmt.Sprintf("%s/%d-%d/%v", key.Format(time.DateOnly), fromEpoch, toEpoch, missing)
+	_, err, _ := c.fetching.Do(flight, func() (any, error) {
+		return nil, c.fetch(ctx, logger, spec, key, fromEpoch, toEpoch, missing)
+	})
+	return err
+}
+
+// fetch fetches the performance of the given validators in a day, and caches it.
+func (c *Client) fetch(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	key time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) error {
+	start := time.Now()
+	fetched, err := c.fetchDay(ctx, spec, fromEpoch, toEpoch, indices)
+	if err != nil {
+		return fmt.Errorf("failed to fetch validator performance from beacon node: %w", err)
+	}
+	logger.Debug("Fetched validator performance from beacon node",
+		zap.Time("day", key),
+		zap.Int("validators", len(indices)),
+		zap.Duration("took", time.Since(start)),
+	)
+
+	now := time.Now()
+	for index, v := range fetched {
+		if err := c.store(index, now, key, fromEpoch, toEpoch, v); err != nil {
+			logger.Error("failed to save cache", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// cached returns a validator's performance in a day from the cache. ok is false if it
+// isn't cached, or was cached for other epochs of the day.
+func (c *Client) cached(
+	key time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	index phase0.ValidatorIndex,
+) (v *validatorData, ok bool, err error) {
+	var item cacheItem
+	_, found, err := c.cache.Get(string(ProviderType), uint64(index), key, &item)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found || item.FromEpoch != fromEpoch || item.ToEpoch != toEpoch {
+		return nil, false, nil
+	}
+	return item.Validator, true, nil
+}
+
+// store caches a validator's performance in a day.
+func (c *Client) store(
+	index phase0.ValidatorIndex,
+	fetched, key time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	v *validatorData,
+) error {
+	return c.cache.Set(string(ProviderType), uint64(index), fetched, cache.Entry{
+		Day:   key,
+		Value: cacheItem{FromEpoch: fromEpoch, ToEpoch: toEpoch, Validator: v},
+	})
+}
+
+// fetchDay fetches the performance of the given validators in the given epochs.
+func (c *Client) fetchDay(
+	ctx context.Context,
+	spec beacon.Spec,
+	fromEpoch, toEpoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) (map[phase0.ValidatorIndex]*validatorData, error) {
+	validators := make(map[phase0.ValidatorIndex]*validatorData, len(indices))
+	for _, index := range indices {
+		validators[index] = &validatorData{}
+	}
+	var mu sync.Mutex
+
+	// Effective balances at the end of the day, which also tell which validators exist.
+	balances, err := c.effectiveBalances(ctx, spec.LastSlot(toEpoch), indices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get effective balances: %w", err)
+	}
+	for index, balance := range balances {
+		if _, ok := validators[index]; !ok {
+			continue
+		}
+		validators[index].Exists = true
+		validators[index].EndEffectiveBalance = balance
+	}
+
+	// Attestations and proposals, by epoch.
+	p := pool.New().WithContext(ctx).WithCancelOnError().WithFirstError().WithMaxGoroutines(workers)
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		epoch := epoch
+		p.Go(func(ctx context.Context) error {
+			missed, err := c.missedAttestations(ctx, epoch, indices)
+			if err != nil {
+				return fmt.Errorf("failed to get attestations of epoch %d: %w", epoch, err)
+			}
+			proposed, missedBlocks, err := c.proposals(ctx, epoch, validators)
+			if err != nil {
+				return fmt.Errorf("failed to get proposals of epoch %d: %w", epoch, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, index := range missed {
+				if _, ok := validators[index]; !ok {
+					continue
+				}
+				validators[index].MissedAttestations = append(validators[index].MissedAttestations, epoch)
+			}
+			for _, index := range proposed {
+				validators[index].ProposedBlocks++
+			}
+			for _, index := range missedBlocks {
+				validators[index].MissedBlocks++
+			}
+			return nil
+		})
+	}
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+	for _, v := range validators {
+		sort.Slice(v.MissedAttestations, func(i, j int) bool { return v.MissedAttestations[i] < v.MissedAttestations[j] })
+	}
+
+	// Sync committees, which span 256 epochs, so a day is in at most two of them.
+	members := map[phase0.ValidatorIndex]bool{}
+	for _, epoch := range []phase0.Epoch{fromEpoch, toEpoch} {
+		duties, err := c.syncCommitteeMembers(ctx, epoch, indices)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sync committee duties of epoch %d: %w", epoch, err)
+		}
+		for _, index := range duties {
+			members[index] = true
+		}
+	}
+	if len(members) > 0 {
+		memberIndices := make([]phase0.ValidatorIndex, 0, len(members))
+		for index := range members {
+			memberIndices = append(memberIndices, index)
+		}
+		sort.Slice(memberIndices, func(i, j int) bool { return memberIndices[i] < memberIndices[j] })
+
+		p := pool.New().WithContext(ctx).WithCancelOnError().WithFirstError().WithMaxGoroutines(workers)
+		for slot := spec.FirstSlot(fromEpoch); slot <= spec.LastSlot(toEpoch); slot++ {
+			slot := slot
+			p.Go(func(ctx context.Context) error {
+				rewards, err := c.syncCommitteeRewards(ctx, slot, memberIndices)
+				if err != nil {
+					return fmt.Errorf("failed to get sync committee rewards of slot %d: %w", slot, err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for index, reward := range rewards {
+					if _, ok := validators[index]; !ok {
+						continue
+					}
+					if reward < 0 {
+						validators[index].MissedSync++
+					} else {
+						validators[index].ParticipatedSync++
+					}
+				}
+				return nil
+			})
+		}
+		if err := p.Wait(); err != nil {
+			return nil, err
+		}
+	}
+
+	return validators, nil
+}
+
+// effectiveBalances returns the effective balances of the given validators at a slot.
+// Validators that don't exist at the slot are omitted.
+func (c *Client) effectiveBalances(
+	ctx context.Context,
+	slot phase0.Slot,
+	indices []phase0.ValidatorIndex,
+) (map[phase0.ValidatorIndex]int64, error) {
+	balances := make(map[phase0.ValidatorIndex]int64, len(indices))
+	for _, chunk := range chunks(indices) {
+		var resp struct {
+			Data []struct {
+				Index     phase0.ValidatorIndex `json:"index,string"`
+				Validator struct {
+					EffectiveBalance int64 `json:"effective_balance,string"`
+				} `json:"validator"`
+			} `json:"data"`
+		}
+		err := requests.URL(c.endpoint).
+			Client(c.client).
+			Pathf("/eth/v1/beacon/states/%d/validators", slot).
+			BodyJSON(map[string][]string{"ids": formatIndices(chunk)}).
+			ToJSON(&resp).
+			Fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Data {
+			balances[v.Index] = v.Validator.EffectiveBalance
+		}
+	}
+	return balances, nil
+}
+
+// missedAttestations returns the validators that missed their attestation in an epoch,
+// judged by a negative reward for the attestation's source. Epochs that the node can't
+// compute rewards for fall back to validator liveness.
+func (c *Client) missedAttestations(
+	ctx context.Context,
+	epoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) ([]phase0.ValidatorIndex, error) {
+	var missed []phase0.ValidatorIndex
+	for _, chunk := range chunks(indices) {
+		var resp struct {
+			Data struct {
+				TotalRewards []struct {
+					ValidatorIndex phase0.ValidatorIndex `json:"validator_index,string"`
+					Source         int64                 `json:"source,string"`
+				} `json:"total_rewards"`
+			} `json:"data"`
+		}
+		err := requests.URL(c.endpoint).
+			Client(c.client).
+			Pathf("/eth/v1/beacon/rewards/attestations/%d", epoch).
+			BodyJSON(formatIndices(chunk)).
+			ToJSON(&resp).
+			Fetch(ctx)
+		if requests.HasStatusErr(err, 404) {
+			chunkMissed, err := c.notLive(ctx, epoch, chunk)
+			if err != nil {
+				return nil, err
+			}
+			missed = append(missed, chunkMissed...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, reward := range resp.Data.TotalRewards {
+			// Timely attestations are rewarded, or at least not penalized during an inactivity leak.
+			if reward.Source < 0 {
+				missed = append(missed, reward.ValidatorIndex)
+			}
+		}
+	}
+	return missed, nil
+}
+
+// notLive returns the validators that weren't live in an epoch.
+func (c *Client) notLive(
+	ctx context.Context,
+	epoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) ([]phase0.ValidatorIndex, error) {
+	var resp struct {
+		Data []struct {
+			Index  phase0.ValidatorIndex `json:"index,string"`
+			IsLive bool                  `json:"is_live"`
+		} `json:"data"`
+	}
+	err := requests.URL(c.endpoint).
+		Client(c.client).
+		Pathf("/eth/v1/validator/liveness/%d", epoch).
+		BodyJSON(formatIndices(indices)).
+		ToJSON(&resp).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator liveness: %w", err)
+	}
+	var notLive []phase0.ValidatorIndex
+	for _, v := range resp.Data {
+		if !v.IsLive {
+			notLive = append(notLive, v.Index)
+		}
+	}
+	return notLive, nil
+}
+
+// proposals returns which of the given validators proposed, or missed, their blocks in an epoch.
+func (c *Client) proposals(
+	ctx context.Context,
+	epoch phase0.Epoch,
+	validators map[phase0.ValidatorIndex]*validatorData,
+) (proposed, missed []phase0.ValidatorIndex, err error) {
+	var resp struct {
+		Data []struct {
+			ValidatorIndex phase0.ValidatorIndex `json:"validator_index,string"`
+			Slot           phase0.Slot           `json:"slot,string"`
+		} `json:"data"`
+	}
+	err = requests.URL(c.endpoint).
+		Client(c.client).
+		Pathf("/eth/v1/validator/duties/proposer/%d", epoch).
+		ToJSON(&resp).
+		Fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, duty := range resp.Data {
+		if _, ok := validators[duty.ValidatorIndex]; !ok {
+			continue
+		}
+		err := requests.URL(c.endpoint).
+			Client(c.client).
+			Pathf("/eth/v1/beacon/headers/%d", duty.Slot).
+			Fetch(ctx)
+		switch {
+		case requests.HasStatusErr(err, 404):
+			missed = append(missed, duty.ValidatorIndex)
+		case err != nil:
+			return nil, nil, fmt.Errorf("failed to get block header of slot %d: %w", duty.Slot, err)
+		default:
+			proposed = append(proposed, duty.ValidatorIndex)
+		}
+	}
+	return proposed, missed, nil
+}
+
+// syncCommitteeMembers returns which of the given validators are in the sync committee of an epoch.
+func (c *Client) syncCommitteeMembers(
+	ctx context.Context,
+	epoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) ([]phase0.ValidatorIndex, error) {
+	var members []phase0.ValidatorIndex
+	for _, chunk := range chunks(indices) {
+		var resp struct {
+			Data []struct {
+				ValidatorIndex phase0.ValidatorIndex `json:"validator_index,string"`
+			} `json:"data"`
+		}
+		err := requests.URL(c.endpoint).
+			Client(c.client).
+			Pathf("/eth/v1/validator/duties/sync/%d", epoch).
+			BodyJSON(formatIndices(chunk)).
+			ToJSON(&resp).
+			Fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, duty := range resp.Data {
+			members = append(members, duty.ValidatorIndex)
+		}
+	}
+	return members, nil
+}
+
+// syncCommitteeRewards returns the sync committee rewards of the given validators in a
+// slot's block, which are negative for validators that didn't participate. Slots without
+// a block have no rewards.
+func (c *Client) syncCommitteeRewards(
+	ctx context.Context,
+	slot phase0.Slot,
+	indices []phase0.ValidatorIndex,
+) (map[phase0.ValidatorIndex]int64, error) {
+	var resp struct {
+		Data []struct {
+			ValidatorIndex phase0.ValidatorIndex `json:"validator_index,string"`
+			Reward         int64                 `json:"reward,string"`
+		} `json:"data"`
+	}
+	err := requests.URL(c.endpoint).
+		Client(c.client).
+		Pathf("/eth/v1/beacon/rewards/sync_committee/%d", slot).
+		BodyJSON(formatIndices(indices)).
+		ToJSON(&resp).
+		Fetch(ctx)
+	if requests.HasStatusErr(err, 404) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rewards := make(map[phase0.ValidatorIndex]int64, len(resp.Data))
+	for _, r := range resp.Data {
+		rewards[r.ValidatorIndex] = r.Reward
+	}
+	return rewards, nil
+}
+
+func convertToPerformance(
+	spec beacon.Spec,
+	v *validatorData,
+	fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
+) *performance.ValidatorPerformance {
+	activeEpochs := performance.ActiveEpochs(spec, fromEpoch, toEpoch, activationEpoch, exitEpoch)
+
+	// Only count missed attestations of epochs in which the validator was active.
+	var missed int16
+	for _, epoch := range v.MissedAttestations {
+		if epoch >= activationEpoch && (exitEpoch == spec.FarFutureEpoch || epoch < exitEpoch) {
+			missed++
+		}
+	}
+
+	p := &performance.ValidatorPerformance{
+		Attestations: performance.DutyPerformance{
+			Assigned: int16(activeEpochs),
+			Executed: int16(activeEpochs) - missed,
+			Missed:   missed,
+		},
+		Proposals: performance.DutyPerformance{
+			Assigned: v.ProposedBlocks + v.MissedBlocks,
+			Executed: v.ProposedBlocks,
+			Missed:   v.MissedBlocks,
+		},
+		SyncCommittee: performance.DutyPerformance{
+			Assigned: v.ParticipatedSync + v.MissedSync,
+			Executed: v.ParticipatedSync,
+			Missed:   v.MissedSync,
+		},
+		EndEffectiveBalance: v.EndEffectiveBalance,
+	}
+	if p.Attestations.Assigned > 0 {
+		p.AttestationRate = float32(p.Attestations.Executed) / float32(p.Attestations.Assigned)
+	}
+	return p
+}
+
+// Invalidate removes the given validators from the cache of a day, so that their
+// performance is fetched again.
+func (c *Client) Invalidate(day time.Time, indices []phase0.ValidatorIndex) error {
+	key := dayKey(day)
+	for _, index := range indices {
+		if err := c.cache.Delete(string(ProviderType), uint64(index), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportCacheDir imports the JSON files of days that were cached before the cache was
+// a key-value store, and returns the number of imported days.
+func (c *Client) ImportCacheDir(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		key, err := time.Parse("2006-01-02", strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid cache file name %q: %w", file, err)
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return 0, err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		var day dayCacheFile
+		if err := json.Unmarshal(data, &day); err != nil {
+			return 0, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		for index, v := range day.Validators {
+			if err := c.store(index, info.ModTime(), key, day.FromEpoch, day.ToEpoch, v); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(files), nil
+}
+
+// dayCacheFile is the performance of the validators fetched for a day, as it was
+// cached in a JSON file per day.
+type dayCacheFile struct {
+	FromEpoch  phase0.Epoch                             `json:"from_epoch"`
+	ToEpoch    phase0.Epoch                             `json:"to_epoch"`
+	Validators map[phase0.ValidatorIndex]*validatorData `json:"validators"`
+}
+
+func dayKey(day time.Time) time.Time {
+	return day.UTC().Truncate(24 * time.Hour)
+}
+
+func chunks(indices []phase0.ValidatorIndex) [][]phase0.ValidatorIndex {
+	var chunks [][]phase0.ValidatorIndex
+	for start := 0; start < len(indices); start += indicesPerRequest {
+		end := min(start+indicesPerRequest, len(indices))
+		chunks = append(chunks, indices[start:end])
+	}
+	return chunks
+}
+
+func formatIndices(indices []phase0.ValidatorIndex) []string {
+	formatted := make([]string, len(indices))
+	for i, index := range indices {
+		formatted[i] = strconv.FormatUint(uint64(index), 10)
+	}
+	return formatted
+}
@@ -0,0 +1,221 @@
+package beaconnode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	gosync "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
+)
+
+// mockBeaconAPI serves epochs 10 and 11 of a chain with the following validators:
+//   - 1: missed its attestation in epoch 11 (which has no rewards, only liveness)
+//     and proposed the block of slot 321.
+//   - 2: missed its attestation in epoch 10, missed the block of slot 353, and is in
+//     the sync committee, participating in slot 321 but not in slot 320.
+//   - 3: doesn't exist.
+func mockBeaconAPI(t *testing.T, requests *atomic.Int64) *httptest.Server {
+	t.Helper()
+	respond := func(w http.ResponseWriter, data any) {
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /eth/v1/beacon/states/383/validators", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, []any{
+			map[string]any{"index": "1", "validator": map[string]any{"effective_balance": "32000000000"}},
+			map[string]any{"index": "2", "validator": map[string]any{"effective_balance": "31000000000"}},
+		})
+	})
+	mux.HandleFunc("POST /eth/v1/beacon/rewards/attestations/10", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, map[string]any{"total_rewards": []any{
+			map[string]any{"validator_index": "1", "source": "100"},
+			map[string]any{"validator_index": "2", "source": "-100"},
+		}})
+	})
+	mux.HandleFunc("POST /eth/v1/validator/liveness/11", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, []any{
+			map[string]any{"index": "1", "is_live": false},
+			map[string]any{"index": "2", "is_live": true},
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/validator/duties/proposer/{epoch}", func(w http.ResponseWriter, r *http.Request) {
+		duties := map[string][]any{
+			"10": {map[string]any{"validator_index": "1", "slot": "321"}},
+			"11": {
+				map[string]any{"validator_index": "2", "slot": "353"},
+				map[string]any{"validator_index": "99", "slot": "354"},
+			},
+		}
+		respond(w, duties[r.PathValue("epoch")])
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/headers/321", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, map[string]any{})
+	})
+	mux.HandleFunc("POST /eth/v1/validator/duties/sync/{epoch}", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, []any{map[string]any{"validator_index": "2"}})
+	})
+	mux.HandleFunc("POST /eth/v1/beacon/rewards/sync_committee/{slot}", func(w http.ResponseWriter, r *http.Request) {
+		rewards := map[string]string{"320": "-10", "321": "10"}
+		reward, ok := rewards[r.PathValue("slot")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		respond(w, []any{map[string]any{"validator_index": "2", "reward": reward}})
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{
+		SlotsPerEpoch:  32,
+		SlotDuration:   12 * time.Second,
+		FarFutureEpoch: math.MaxUint64,
+	}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const fromEpoch, toEpoch = 10, 11
+	indices := []phase0.ValidatorIndex{1, 2, 3}
+
+	var requests atomic.Int64
+	server := mockBeaconAPI(t, &requests)
+	newCache := func(t *testing.T) *cache.Cache {
+		kvCache, err := cache.NewInMemory(logger)
+		require.NoError(t, err)
+		t.Cleanup(func() { kvCache.Close() })
+		return kvCache
+	}
+	kvCache := newCache(t)
+	client := New(server.URL, kvCache)
+	require.NoError(t, client.Prepare(ctx, logger, spec, day, fromEpoch, toEpoch, indices))
+	require.NotZero(t, requests.Load())
+
+	expected := map[phase0.ValidatorIndex]*performance.ValidatorPerformance{
+		1: {
+			AttestationRate:     0.5,
+			EndEffectiveBalance: 32_000_000_000,
+			Attestations:        performance.DutyPerformance{Assigned: 2, Executed: 1, Missed: 1},
+			Proposals:           performance.DutyPerformance{Assigned: 1, Executed: 1},
+		},
+		2: {
+			AttestationRate:     0.5,
+			EndEffectiveBalance: 31_000_000_000,
+			Attestations:        performance.DutyPerformance{Assigned: 2, Executed: 1, Missed: 1},
+			Proposals:           performance.DutyPerformance{Assigned: 1, Missed: 1},
+			SyncCommittee:       performance.DutyPerformance{Assigned: 2, Executed: 1, Missed: 1},
+		},
+		3: nil,
+	}
+	verify := func(t *testing.T, client *Client) {
+		for index, want := range expected {
+			got, err := client.ValidatorPerformance(ctx, logger, spec, day, fromEpoch, toEpoch, 0, spec.FarFutureEpoch, index)
+			require.NoError(t, err)
+			require.Equal(t, want, got, fmt.Sprintf("validator %d", index))
+		}
+	}
+
+	t.Run("performance", func(t *testing.T) {
+		verify(t, client)
+
+		// Missed attestations before activation aren't counted.
+		got, err := client.ValidatorPerformance(ctx, logger, spec, day, fromEpoch, toEpoch, 11, spec.FarFutureEpoch, 2)
+		require.NoError(t, err)
+		require.Equal(t, performance.DutyPerformance{Assigned: 1, Executed: 1}, got.Attestations)
+		require.Equal(t, float32(1), got.AttestationRate)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		// Validators fetched one at a time concurrently don't wait on each other's
+		// lock, and each is fetched once.
+		client := New(server.URL, newCache(t))
+		var wg gosync.WaitGroup
+		for index, want := range expected {
+			for range 2 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					got, err := client.ValidatorPerformance(ctx, logger, spec, day, fromEpoch, toEpoch, 0, spec.FarFutureEpoch, index)
+					require.NoError(t, err)
+					require.Equal(t, want, got, fmt.Sprintf("validator %d", index))
+				}()
+			}
+		}
+		wg.Wait()
+		verify(t, client)
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		before := requests.Load()
+		verify(t, client)
+
+		// A new client reads the day from the cache.
+		cached := New(server.URL, kvCache)
+		verify(t, cached)
+		require.Equal(t, before, requests.Load())
+
+		// Validators that weren't fetched yet are fetched.
+		require.NoError(t, cached.Prepare(ctx, logger, spec, day, fromEpoch, toEpoch, []phase0.ValidatorIndex{4}))
+		require.Greater(t, requests.Load(), before)
+
+		// Invalidated validators are fetched again.
+		before = requests.Load()
+		require.NoError(t, cached.Invalidate(day, []phase0.ValidatorIndex{1}))
+		verify(t, cached)
+		require.Greater(t, requests.Load(), before)
+	})
+
+	t.Run("import cache dir", func(t *testing.T) {
+		// Days cached in JSON files are imported by validator.
+		dir := t.TempDir()
+		data, err := json.Marshal(dayCacheFile{
+			FromEpoch: fromEpoch,
+			ToEpoch:   toEpoch,
+			Validators: map[phase0.ValidatorIndex]*validatorData{
+				1: {Exists: true, EndEffectiveBalance: 32_000_000_000},
+				3: {},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "2024-01-01.json"), data, 0644))
+
+		imported := New(server.URL, newCache(t))
+		n, err := imported.ImportCacheDir(dir)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		before := requests.Load()
+		require.NoError(t, imported.Prepare(ctx, logger, spec, day, fromEpoch, toEpoch, []phase0.ValidatorIndex{1, 3}))
+		require.Equal(t, before, requests.Load())
+		got, err := imported.ValidatorPerformance(ctx, logger, spec, day, fromEpoch, toEpoch, 0, spec.FarFutureEpoch, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(32_000_000_000), got.EndEffectiveBalance)
+		got, err = imported.ValidatorPerformance(ctx, logger, spec, day, fromEpoch, toEpoch, 0, spec.FarFutureEpoch, 3)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		// Other epochs of the day aren't served from the cache.
+		require.NoError(t, imported.Prepare(ctx, logger, spec, day, fromEpoch+1, toEpoch, []phase0.ValidatorIndex{1}))
+		require.Greater(t, requests.Load(), before)
+	})
+}
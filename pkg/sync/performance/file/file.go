@@ -0,0 +1,329 @@
+// Package file provides validator performance from CSV or JSONL files, such as
+// statistics exported by another indexer or prepared for a backfill.
+//
+// Each row is the performance of a validator in a day, with the following fields:
+//
+//	day                      Day in YYYY-MM-DD format.
+//	index                    Validator index.
+//	attestations_assigned    Attestation duties.
+//	attestations_executed
+//	attestations_missed
+//	proposals_assigned       Block proposal duties.
+//	proposals_executed
+//	proposals_missed
+//	sync_committee_assigned  Sync committee duties.
+//	sync_committee_executed
+//	sync_committee_missed
+//	end_effective_balance    Effective balance at the end of the day, in Gwei.
+//	attestation_rate         Optional, defaults to executed/assigned attestations.
+//	effectiveness            Optional.
+//
+// CSV files must have a header row with the field names, and JSONL files have an object
+// per line with the fields as keys, either as numbers or strings.
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
+)
+
+const (
+	ProviderType performance.ProviderType = "file"
+)
+
+var (
+	requiredFields = []string{
+		"day",
+		"index",
+		"attestations_assigned",
+		"attestations_executed",
+		"attestations_missed",
+		"proposals_assigned",
+		"proposals_executed",
+		"proposals_missed",
+		"sync_committee_assigned",
+		"sync_committee_executed",
+		"sync_committee_missed",
+		"end_effective_balance",
+	}
+	optionalFields = []string{
+		"attestation_rate",
+		"effectiveness",
+	}
+)
+
+type Client struct {
+	days map[time.Time]map[phase0.ValidatorIndex]*performance.ValidatorPerformance
+}
+
+// New reads the performance from a CSV (.csv) or JSONL (.jsonl) file, or from
+// all such files in a directory. Rows are validated, and a validator can't have
+// more than one row per day.
+func New(path string) (*Client, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if !entry.IsDir() && (ext == ".csv" || ext == ".jsonl") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no .csv or .jsonl files in %s", path)
+		}
+	}
+
+	c := &Client{
+		days: make(map[time.Time]map[phase0.ValidatorIndex]*performance.ValidatorPerformance),
+	}
+	for _, file := range files {
+		if err := c.load(file); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	if len(c.days) == 0 {
+		return nil, fmt.Errorf("no performance in %s", path)
+	}
+	return c, nil
+}
+
+func (c *Client) Type() performance.ProviderType {
+	return ProviderType
+}
+
+// Days returns the days with performance, in order.
+func (c *Client) Days() []time.Time {
+	days := make([]time.Time, 0, len(c.days))
+	for day := range c.days {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// ValidatorPerformance returns the performance of a validator in a day. Days without any
+// rows are an error, as are validators without a row in a day they were active in.
+func (c *Client) ValidatorPerformance(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
+	index phase0.ValidatorIndex,
+) (*performance.ValidatorPerformance, error) {
+	validators, ok := c.days[day.UTC().Truncate(24*time.Hour)]
+	if !ok {
+		return nil, fmt.Errorf("no performance for day %s", day.Format("2006-01-02"))
+	}
+	p, ok := validators[index]
+	if !ok {
+		if performance.ActiveEpochs(spec, fromEpoch, toEpoch, activationEpoch, exitEpoch) > 0 {
+			return nil, fmt.Errorf("no performance for validator %d in day %s", index, day.Format("2006-01-02"))
+		}
+		return nil, nil
+	}
+	return p, nil
+}
+
+func (c *Client) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	handle := func(line int, fields map[string]string) error {
+		day, index, p, err := parseRow(fields)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		validators, ok := c.days[day]
+		if !ok {
+			validators = make(map[phase0.ValidatorIndex]*performance.ValidatorPerformance)
+			c.days[day] = validators
+		}
+		if _, ok := validators[index]; ok {
+			return fmt.Errorf("line %d: duplicate row for validator %d in day %s", line, index, day.Format("2006-01-02"))
+		}
+		validators[index] = p
+		return nil
+	}
+	switch filepath.Ext(path) {
+	case ".csv":
+		return readCSV(f, handle)
+	case ".jsonl":
+		return readJSONL(f, handle)
+	default:
+		return fmt.Errorf("unsupported file extension (expected .csv or .jsonl)")
+	}
+}
+
+func readCSV(r io.Reader, handle func(line int, fields map[string]string) error) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+		fields := make(map[string]string, len(record))
+		for i, value := range record {
+			// Empty values are treated as missing, so that optional fields can be left blank.
+			if value = strings.TrimSpace(value); value != "" {
+				fields[header[i]] = value
+			}
+		}
+		if err := handle(line, fields); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONL(r io.Reader, handle func(line int, fields map[string]string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		fields := make(map[string]string, len(raw))
+		for key, value := range raw {
+			var s string
+			switch {
+			case string(value) == "null":
+				continue
+			case json.Unmarshal(value, &s) == nil:
+				fields[key] = s
+			default:
+				fields[key] = string(value)
+			}
+		}
+		if err := handle(line, fields); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// parseRow validates a row's fields and converts them into performance.
+func parseRow(fields map[string]string) (time.Time, phase0.ValidatorIndex, *performance.ValidatorPerformance, error) {
+	for _, name := range requiredFields {
+		if _, ok := fields[name]; !ok {
+			return time.Time{}, 0, nil, fmt.Errorf("missing field %q", name)
+		}
+	}
+	for name := range fields {
+		if !slices.Contains(requiredFields, name) && !slices.Contains(optionalFields, name) {
+			return time.Time{}, 0, nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	day, err := time.Parse("2006-01-02", fields["day"])
+	if err != nil {
+		return time.Time{}, 0, nil, fmt.Errorf("invalid day: %w", err)
+	}
+	index, err := strconv.ParseUint(fields["index"], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, nil, fmt.Errorf("invalid index: %w", err)
+	}
+
+	var p performance.ValidatorPerformance
+	duties := []struct {
+		name string
+		duty *performance.DutyPerformance
+	}{
+		{"attestations", &p.Attestations},
+		{"proposals", &p.Proposals},
+		{"sync_committee", &p.SyncCommittee},
+	}
+	for _, d := range duties {
+		for _, field := range []struct {
+			suffix string
+			value  *int16
+		}{
+			{"assigned", &d.duty.Assigned},
+			{"executed", &d.duty.Executed},
+			{"missed", &d.duty.Missed},
+		} {
+			name := d.name + "_" + field.suffix
+			v, err := strconv.ParseUint(fields[name], 10, 15)
+			if err != nil {
+				return time.Time{}, 0, nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*field.value = int16(v)
+		}
+		if d.duty.Executed+d.duty.Missed > d.duty.Assigned {
+			return time.Time{}, 0, nil, fmt.Errorf(
+				"%s executed (%d) and missed (%d) exceed assigned (%d)",
+				d.name, d.duty.Executed, d.duty.Missed, d.duty.Assigned,
+			)
+		}
+	}
+
+	p.EndEffectiveBalance, err = strconv.ParseInt(fields["end_effective_balance"], 10, 64)
+	if err != nil || p.EndEffectiveBalance < 0 {
+		return time.Time{}, 0, nil, fmt.Errorf("invalid end_effective_balance: %q", fields["end_effective_balance"])
+	}
+
+	if v, ok := fields["attestation_rate"]; ok {
+		rate, err := strconv.ParseFloat(v, 32)
+		if err != nil || rate < 0 || rate > 1 {
+			return time.Time{}, 0, nil, fmt.Errorf("invalid attestation_rate: %q", v)
+		}
+		p.AttestationRate = float32(rate)
+	} else if p.Attestations.Assigned > 0 {
+		p.AttestationRate = float32(p.Attestations.Executed) / float32(p.Attestations.Assigned)
+	}
+	if v, ok := fields["effectiveness"]; ok {
+		effectiveness, err := strconv.ParseFloat(v, 32)
+		if err != nil || effectiveness < 0 {
+			return time.Time{}, 0, nil, fmt.Errorf("invalid effectiveness: %q", v)
+		}
+		e := float32(effectiveness)
+		p.Effectiveness = &e
+	}
+
+	return day, phase0.ValidatorIndex(index), &p, nil
+}
@@ -0,0 +1,108 @@
+package file
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
+)
+
+const csvHeader = "day,index,attestations_assigned,attestations_executed,attestations_missed," +
+	"proposals_assigned,proposals_executed,proposals_missed," +
+	"sync_committee_assigned,sync_committee_executed,sync_committee_missed,end_effective_balance,effectiveness\n"
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	writeFile(t, dir, "a.csv", csvHeader+
+		"2024-01-01,1,225,220,5,1,1,0,0,0,0,32000000000,0.98\n"+
+		"2024-01-01,2,225,225,0,0,0,0,7200,7100,100,31000000000,\n")
+	writeFile(t, dir, "b.jsonl", `{"day":"2024-01-02","index":"1","attestations_assigned":225,"attestations_executed":225,"attestations_missed":0,`+
+		`"proposals_assigned":0,"proposals_executed":0,"proposals_missed":0,`+
+		`"sync_committee_assigned":0,"sync_committee_executed":0,"sync_committee_missed":0,`+
+		`"end_effective_balance":32000000000,"attestation_rate":0.99,"effectiveness":null}`+"\n")
+	writeFile(t, dir, "ignored.txt", "not performance")
+
+	client, err := New(dir)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day, day.AddDate(0, 0, 1)}, client.Days())
+
+	get := func(day time.Time, index phase0.ValidatorIndex, activationEpoch phase0.Epoch) (*performance.ValidatorPerformance, error) {
+		return client.ValidatorPerformance(ctx, logger, spec, day, 0, 224, activationEpoch, spec.FarFutureEpoch, index)
+	}
+
+	p, err := get(day, 1, 0)
+	require.NoError(t, err)
+	effectiveness := float32(0.98)
+	require.Equal(t, &performance.ValidatorPerformance{
+		Effectiveness:       &effectiveness,
+		AttestationRate:     float32(220) / 225,
+		EndEffectiveBalance: 32_000_000_000,
+		Attestations:        performance.DutyPerformance{Assigned: 225, Executed: 220, Missed: 5},
+		Proposals:           performance.DutyPerformance{Assigned: 1, Executed: 1},
+	}, p)
+
+	p, err = get(day, 2, 0)
+	require.NoError(t, err)
+	require.Nil(t, p.Effectiveness)
+	require.Equal(t, performance.DutyPerformance{Assigned: 7200, Executed: 7100, Missed: 100}, p.SyncCommittee)
+
+	p, err = get(day.AddDate(0, 0, 1), 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, float32(0.99), p.AttestationRate)
+
+	// Missing rows are an error for active validators only.
+	_, err = get(day, 3, 0)
+	require.ErrorContains(t, err, "no performance for validator 3 in day 2024-01-01")
+	p, err = get(day, 3, 1000)
+	require.NoError(t, err)
+	require.Nil(t, p)
+
+	_, err = get(day.AddDate(0, 0, 2), 1, 0)
+	require.ErrorContains(t, err, "no performance for day 2024-01-03")
+}
+
+func TestNewValidation(t *testing.T) {
+	const row = "2024-01-01,1,225,220,5,1,1,0,0,0,0,32000000000,\n"
+	for _, tt := range []struct {
+		name    string
+		file    string
+		content string
+		err     string
+	}{
+		{"duplicate", "a.csv", csvHeader + row + row, "line 3: duplicate row for validator 1 in day 2024-01-01"},
+		{"missing field", "a.jsonl", `{"day":"2024-01-01","index":1}`, `line 1: missing field "attestations_assigned"`},
+		{"unknown field", "a.csv", "foo," + csvHeader + "1," + row, `line 2: unknown field "foo"`},
+		{"blank required field", "a.csv", csvHeader + "2024-01-01,1,225,220,5,1,1,0,0,0,,32000000000,\n", `missing field "sync_committee_missed"`},
+		{"exceeds assigned", "a.csv", csvHeader + "2024-01-01,1,225,225,5,1,1,0,0,0,0,32000000000,\n", "attestations executed (225) and missed (5) exceed assigned (225)"},
+		{"invalid day", "a.csv", csvHeader + "01/01/2024,1,225,220,5,1,1,0,0,0,0,32000000000,\n", "invalid day"},
+		{"empty", "a.csv", csvHeader, "no performance in"},
+		{"unsupported", "a.json", "{}", "unsupported file extension"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), tt.file, tt.content)
+			_, err := New(path)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
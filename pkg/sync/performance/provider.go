@@ -41,3 +41,50 @@ type DutyPerformance struct {
 	Executed int16
 	Missed   int16
 }
+
+// BatchProvider is a Provider that fetches the performance of many validators at once.
+// Prepare is called with the indices of a day's validators before their performance
+// is requested, so that it can be fetched in bulk.
+type BatchProvider interface {
+	Provider
+
+	Prepare(
+		ctx context.Context,
+		logger *zap.Logger,
+		spec beacon.Spec,
+		day time.Time,
+		fromEpoch, toEpoch phase0.Epoch,
+		indices []phase0.ValidatorIndex,
+	) error
+}
+
+// ActiveEpochs returns the number of epochs in the given range in which a validator
+// with the given activation and exit epochs was active.
+func ActiveEpochs(
+	spec beacon.Spec, fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
+) phase0.Epoch {
+	activeEpochs := toEpoch - fromEpoch + 1
+	if activationEpoch > fromEpoch {
+		if activationEpoch > toEpoch {
+			activeEpochs = 0
+		} else {
+			activeEpochs -= activationEpoch - fromEpoch
+		}
+	}
+	if exitEpoch != spec.FarFutureEpoch && exitEpoch <= toEpoch {
+		if exitEpoch <= fromEpoch {
+			activeEpochs = 0
+		} else {
+			activeEpochs -= toEpoch - exitEpoch + 1
+		}
+	}
+	return activeEpochs
+}
+
+// Invalidator is a Provider that caches performance, and can invalidate the cache of
+// a day's validators so that their performance is fetched again.
+type Invalidator interface {
+	Provider
+
+	Invalidate(day time.Time, indices []phase0.ValidatorIndex) error
+}
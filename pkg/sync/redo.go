@@ -0,0 +1,176 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/reconcile"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
+)
+
+// Redo is a range of days whose validator performance is fetched again, such as days
+// that were synced with bad data. A day's rows are replaced in the transaction that
+// inserts the fetched ones, so a failed redo leaves the day as it was.
+type Redo struct {
+	FromDay, ToDay time.Time
+
+	// PublicKeys limits the redo to the given validators, by hex-encoded public key.
+	// All validators are redone if it's empty.
+	PublicKeys []string
+}
+
+// ParseDays parses a day (YYYY-MM-DD) or an inclusive range of days
+// (YYYY-MM-DD..YYYY-MM-DD).
+func ParseDays(s string) (fromDay, toDay time.Time, err error) {
+	from, to, isRange := strings.Cut(s, "..")
+	fromDay, err = time.Parse("2006-01-02", strings.TrimSpace(from))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid day: %w", err)
+	}
+	if !isRange {
+		return fromDay, fromDay, nil
+	}
+	toDay, err = time.Parse("2006-01-02", strings.TrimSpace(to))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid day: %w", err)
+	}
+	if toDay.Before(fromDay) {
+		return time.Time{}, time.Time{}, fmt.Errorf("day range %s ends before it starts", s)
+	}
+	return fromDay, toDay, nil
+}
+
+// Days returns the days of the redo, in order.
+func (r *Redo) Days() []time.Time {
+	var days []time.Time
+	for day := r.FromDay; !day.After(r.ToDay); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// includes reports whether the day is redone. A nil Redo includes no days.
+func (r *Redo) includes(day time.Time) bool {
+	return r != nil && !day.Before(r.FromDay) && !day.After(r.ToDay)
+}
+
+// includesValidator reports whether the validator is redone.
+func (r *Redo) includesValidator(pubKey string) bool {
+	return len(r.PublicKeys) == 0 || slices.Contains(r.PublicKeys, pubKey)
+}
+
+func (r *Redo) performanceMods(provider models.ProviderType) []qm.QueryMod {
+	mods := []qm.QueryMod{
+		models.ValidatorPerformanceWhere.Provider.EQ(provider),
+		models.ValidatorPerformanceWhere.Day.GTE(r.FromDay),
+		models.ValidatorPerformanceWhere.Day.LTE(r.ToDay),
+	}
+	if len(r.PublicKeys) > 0 {
+		mods = append(mods, models.ValidatorPerformanceWhere.PublicKey.IN(r.PublicKeys))
+	}
+	return mods
+}
+
+// deleteDay deletes the provider's validator performance of the given validators in a
+// day, or of all validators if none are given, and returns the number of deleted rows.
+func deleteDay(
+	ctx context.Context,
+	exec boil.ContextExecutor,
+	provider models.ProviderType,
+	day time.Time,
+	publicKeys []string,
+) (int64, error) {
+	mods := []qm.QueryMod{
+		models.ValidatorPerformanceWhere.Provider.EQ(provider),
+		models.ValidatorPerformanceWhere.Day.EQ(day),
+	}
+	if len(publicKeys) > 0 {
+		mods = append(mods, models.ValidatorPerformanceWhere.PublicKey.IN(publicKeys))
+	}
+	n, err := models.ValidatorPerformances(mods...).DeleteAll(ctx, exec)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete validator performances: %w", err)
+	}
+	return n, nil
+}
+
+// ActiveDays returns the number of the redo's validators that are active under the
+// given criteria in each of its days.
+func (r *Redo) ActiveDays(
+	ctx context.Context,
+	db *sql.DB,
+	provider models.ProviderType,
+	criteria reconcile.CriteriaFunc,
+) (map[time.Time]int, error) {
+	performances, err := models.ValidatorPerformances(r.performanceMods(provider)...).All(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator performances: %w", err)
+	}
+	active := map[time.Time]int{}
+	for _, day := range r.Days() {
+		active[day] = 0
+	}
+	for _, p := range performances {
+		dayCriteria, err := criteria(p.Day)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get criteria of %s: %w", p.Day.Format("2006-01-02"), err)
+		}
+		if reconcile.Status(p, dayCriteria) == reconcile.StatusActive {
+			active[p.Day.UTC()]++
+		}
+	}
+	return active, nil
+}
+
+// Invalidate removes the redo's days from the cache of the decided counts, and its
+// validators' days from the cache of the given performance providers.
+func (r *Redo) Invalidate(
+	ctx context.Context,
+	db *sql.DB,
+	kvCache *cache.Cache,
+	decidedsSource decideds.Source,
+	providers []performance.Provider,
+) error {
+	days := r.Days()
+	if cacheProvider, ok := decidedsCacheProvider(decidedsSource); ok {
+		if err := kvCache.Delete(cacheProvider, 0, days...); err != nil {
+			return err
+		}
+	}
+
+	mods := []qm.QueryMod{models.ValidatorWhere.Index.IsNotNull()}
+	if len(r.PublicKeys) > 0 {
+		mods = append(mods, models.ValidatorWhere.PublicKey.IN(r.PublicKeys))
+	}
+	validators, err := models.Validators(mods...).All(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to get validators: %w", err)
+	}
+	indices := make([]phase0.ValidatorIndex, len(validators))
+	for i, validator := range validators {
+		indices[i] = phase0.ValidatorIndex(validator.Index.Int)
+	}
+	for _, provider := range providers {
+		invalidator, ok := provider.(performance.Invalidator)
+		if !ok {
+			continue
+		}
+		for _, day := range days {
+			if err := invalidator.Invalidate(day, indices); err != nil {
+				return fmt.Errorf("failed to invalidate %s cache: %w", provider.Type(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDays(t *testing.T) {
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	fromDay, toDay, err := ParseDays("2025-09-01")
+	require.NoError(t, err)
+	require.Equal(t, day, fromDay)
+	require.Equal(t, day, toDay)
+
+	fromDay, toDay, err = ParseDays("2025-09-01..2025-09-05")
+	require.NoError(t, err)
+	require.Equal(t, day, fromDay)
+	require.Equal(t, day.AddDate(0, 0, 4), toDay)
+
+	for _, s := range []string{"", "2025-09", "2025-09-01..", "2025-09-05..2025-09-01"} {
+		_, _, err := ParseDays(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestRedo(t *testing.T) {
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	redo := &Redo{FromDay: day, ToDay: day.AddDate(0, 0, 2)}
+	require.Len(t, redo.Days(), 3)
+	require.True(t, redo.includes(day.AddDate(0, 0, 2)))
+	require.False(t, redo.includes(day.AddDate(0, 0, 3)))
+	require.False(t, (*Redo)(nil).includes(day))
+
+	// Without public keys, all validators are redone.
+	require.True(t, redo.includesValidator("a"))
+	redo.PublicKeys = []string{"a"}
+	require.True(t, redo.includesValidator("a"))
+	require.False(t, redo.includesValidator("b"))
+}
@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/volatiletech/null/v8"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+)
+
+// HeaderReader retrieves block headers from an execution node.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// storedBlock is a block number and hash as recorded during sync.
+type storedBlock struct {
+	Number int
+	Hash   string
+}
+
+// DetectReorg verifies the hashes of the most recently synced blocks against the
+// execution node, and returns the highest synced block which is still canonical
+// (the fork point) if they no longer match.
+//
+// The highest synced block is verified first. If it's canonical, so are all blocks
+// before it. Otherwise, stored blocks are walked back, up to maxDepth of them, until
+// a canonical one is found. If none is found, the fork point is the block before
+// the lowest synced block.
+func DetectReorg(
+	ctx context.Context,
+	logger *zap.Logger,
+	db *sql.DB,
+	el HeaderReader,
+	maxDepth int,
+) (forkBlock int, reorged bool, err error) {
+	state, err := models.States().One(ctx, db)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get state: %w", err)
+	}
+	lowestBlock, highestBlock := state.LowestBlockNumber, state.HighestBlockNumber
+
+	var blocks []storedBlock
+	if state.HighestBlockHash.Valid {
+		blocks = append(blocks, storedBlock{Number: highestBlock, Hash: state.HighestBlockHash.String})
+	}
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT DISTINCT block_number, block_hash FROM contract_events
+		WHERE block_number <= $1
+		ORDER BY block_number DESC
+		LIMIT $2`,
+		highestBlock, maxDepth,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get stored blocks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var block storedBlock
+		if err := rows.Scan(&block.Number, &block.Hash); err != nil {
+			return 0, false, fmt.Errorf("failed to scan stored block: %w", err)
+		}
+		if len(blocks) > 0 && blocks[len(blocks)-1].Number == block.Number {
+			// The highest synced block has events.
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, false, fmt.Errorf("failed to iterate stored blocks: %w", err)
+	}
+
+	canonicalHash := func(number int) (string, error) {
+		header, err := el.HeaderByNumber(ctx, big.NewInt(int64(number)))
+		if err != nil {
+			return "", fmt.Errorf("failed to get header of block %d: %w", number, err)
+		}
+		return header.Hash().String(), nil
+	}
+	forkBlock, reorged, err = findForkPoint(blocks, canonicalHash)
+	if err != nil {
+		return 0, false, err
+	}
+	if !reorged {
+		return 0, false, nil
+	}
+	if forkBlock < 0 {
+		if len(blocks) >= maxDepth {
+			return 0, false, fmt.Errorf(
+				"reorg is deeper than the %d most recent stored blocks: increase the reorg check depth or sync with --fresh",
+				maxDepth,
+			)
+		}
+		forkBlock = lowestBlock - 1
+	}
+	logger.Warn("Detected reorg",
+		zap.Int("highest_block", highestBlock),
+		zap.Int("fork_block", forkBlock),
+	)
+	return forkBlock, true, nil
+}
+
+// findForkPoint walks the given blocks (sorted by descending number) and returns
+// the number of the first one that's canonical, or -1 if none is. reorged is false
+// if the first block is canonical.
+func findForkPoint(
+	blocks []storedBlock,
+	canonicalHash func(number int) (string, error),
+) (forkBlock int, reorged bool, err error) {
+	for i, block := range blocks {
+		hash, err := canonicalHash(block.Number)
+		if err != nil {
+			return 0, false, err
+		}
+		if common.HexToHash(hash) == common.HexToHash(block.Hash) {
+			return block.Number, i > 0, nil
+		}
+	}
+	return -1, len(blocks) > 0, nil
+}
+
+// RollbackToBlock removes contract events after forkBlock, and resets the data
+// derived from contract events so that the next SyncValidatorEvents rebuilds it
+// from the remaining ones:
+//   - validator_events are deleted, since the SSV node storage is dropped and
+//     all events are replayed from the database (without fetching them again).
+//   - validators are deactivated and their migration_day is unset, to be set
+//     again by the replayed events.
+//
+// Validator performance is kept, since it doesn't depend on contract events.
+func RollbackToBlock(
+	ctx context.Context,
+	logger *zap.Logger,
+	db *sql.DB,
+	el HeaderReader,
+	nodeStorage operatorstorage.Storage,
+	forkBlock int,
+) error {
+	var forkHash null.String
+	state, err := models.States().One(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to get state: %w", err)
+	}
+	if forkBlock >= state.LowestBlockNumber {
+		header, err := el.HeaderByNumber(ctx, big.NewInt(int64(forkBlock)))
+		if err != nil {
+			return fmt.Errorf("failed to get header of block %d: %w", forkBlock, err)
+		}
+		forkHash = null.StringFrom(header.Hash().String())
+	}
+
+	// Drop the SSV node storage first: if the transaction below fails, the
+	// reorg is detected again on the next sync, which retries the rollback.
+	if err := nodeStorage.DropRegistryData(); err != nil {
+		return fmt.Errorf("failed to drop SSV node storage: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM validator_events"); err != nil {
+		return fmt.Errorf("failed to delete validator events: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM contract_events WHERE block_number > $1", forkBlock)
+	if err != nil {
+		return fmt.Errorf("failed to delete contract events: %w", err)
+	}
+	deletedEvents, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to count deleted contract events: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE contract_events SET error = NULL"); err != nil {
+		return fmt.Errorf("failed to unset contract_events.error: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE validators SET active = FALSE, migration_day = NULL"); err != nil {
+		return fmt.Errorf("failed to reset validators: %w", err)
+	}
+	_, err = models.States().UpdateAll(ctx, tx, models.M{
+		models.StateColumns.HighestBlockNumber: forkBlock,
+		models.StateColumns.HighestBlockHash:   forkHash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rollback: %w", err)
+	}
+
+	logger.Info("Rolled back to fork block",
+		zap.Int("fork_block", forkBlock),
+		zap.Int64("deleted_contract_events", deletedEvents),
+	)
+	return nil
+}
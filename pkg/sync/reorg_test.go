@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindForkPoint(t *testing.T) {
+	const (
+		hashA = "0x000000000000000000000000000000000000000000000000000000000000000a"
+		hashB = "0x000000000000000000000000000000000000000000000000000000000000000b"
+		hashC = "0x000000000000000000000000000000000000000000000000000000000000000c"
+	)
+	stored := []storedBlock{
+		{Number: 300, Hash: hashC},
+		{Number: 200, Hash: hashB},
+		{Number: 100, Hash: hashA},
+	}
+
+	tests := []struct {
+		name          string
+		blocks        []storedBlock
+		canonical     map[int]string
+		wantForkBlock int
+		wantReorged   bool
+	}{
+		{
+			name:          "no stored blocks",
+			blocks:        nil,
+			wantForkBlock: -1,
+			wantReorged:   false,
+		},
+		{
+			name:          "highest block canonical",
+			blocks:        stored,
+			canonical:     map[int]string{300: hashC, 200: hashB, 100: hashA},
+			wantForkBlock: 300,
+			wantReorged:   false,
+		},
+		{
+			name:          "highest block reorged",
+			blocks:        stored,
+			canonical:     map[int]string{300: hashA, 200: hashB, 100: hashA},
+			wantForkBlock: 200,
+			wantReorged:   true,
+		},
+		{
+			name:          "two blocks reorged",
+			blocks:        stored,
+			canonical:     map[int]string{300: hashA, 200: hashA, 100: hashA},
+			wantForkBlock: 100,
+			wantReorged:   true,
+		},
+		{
+			name:          "all blocks reorged",
+			blocks:        stored,
+			canonical:     map[int]string{300: hashA, 200: hashA, 100: hashB},
+			wantForkBlock: -1,
+			wantReorged:   true,
+		},
+		{
+			name:          "hashes compared case-insensitively",
+			blocks:        []storedBlock{{Number: 100, Hash: "0x00000000000000000000000000000000000000000000000000000000000000AB"}},
+			canonical:     map[int]string{100: "0x00000000000000000000000000000000000000000000000000000000000000ab"},
+			wantForkBlock: 100,
+			wantReorged:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forkBlock, reorged, err := findForkPoint(tt.blocks, func(number int) (string, error) {
+				return tt.canonical[number], nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantForkBlock, forkBlock)
+			require.Equal(t, tt.wantReorged, reorged)
+		})
+	}
+
+	t.Run("lookup error", func(t *testing.T) {
+		_, _, err := findForkPoint(stored, func(number int) (string, error) {
+			return "", errors.New("unavailable")
+		})
+		require.Error(t, err)
+	})
+}
@@ -173,11 +173,7 @@ func SyncValidatorEvents(
 
 	// Try to reconstruct active validator set from ValidatorEvents.
 	validatorEvents, err := models.ValidatorEvents(
-		qm.OrderBy(
-			"?, ?",
-			models.ValidatorEventColumns.BlockNumber,
-			models.ValidatorEventColumns.LogIndex,
-		),
+		qm.OrderBy("block_number, log_index"),
 	).All(ctx, db)
 	if err != nil {
 		return fmt.Errorf("failed to get validator events: %w", err)
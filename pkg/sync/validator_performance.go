@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,8 +16,6 @@ import (
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv/eth/eventparser"
-	"github.com/carlmjohnson/requests"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/jmoiron/sqlx"
 	"github.com/schollz/progressbar/v3"
 	"github.com/sourcegraph/conc/pool"
@@ -29,7 +26,9 @@ import (
 	"github.com/bloxapp/ssv-rewards/pkg/beacon"
 	"github.com/bloxapp/ssv-rewards/pkg/models"
 	"github.com/bloxapp/ssv-rewards/pkg/rewards"
-	"github.com/bloxapp/ssv-rewards/pkg/sync/httpretry"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/cache"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/decideds/ssvapi"
 	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
 )
 
@@ -37,14 +36,16 @@ func SyncValidatorPerformance(
 	ctx context.Context,
 	logger *zap.Logger,
 	spec beacon.Spec,
-	ethClient *ethclient.Client,
 	db *sql.DB,
-	ssvAPIEndpoint string,
+	decidedsSource decideds.Source,
 	provider performance.Provider,
+	fallbacks []performance.Provider,
 	fromDay time.Time,
 	toDay time.Time,
-	highestBlockNumber uint64,
-	cacheDir string,
+	highestBlockTime time.Time,
+	kvCache *cache.Cache,
+	decidedsCheck DecidedsCheck,
+	redo *Redo,
 ) error {
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
@@ -52,15 +53,16 @@ func SyncValidatorPerformance(
 	if err := providerType.IsValid(); err != nil {
 		return fmt.Errorf("invalid provider type (%q): %w", providerType, err)
 	}
+	for _, fallback := range fallbacks {
+		if err := models.ProviderType(fallback.Type()).IsValid(); err != nil {
+			return fmt.Errorf("invalid fallback provider type (%q): %w", fallback.Type(), err)
+		}
+	}
 
 	// Fetch ValidatorEvents from the database to determine earliest and latest blocks
 	// with validator activity and active validators at each day.
 	validatorEvents, err := models.ValidatorEvents(
-		qm.OrderBy(
-			"?, ?",
-			models.ValidatorEventColumns.BlockNumber,
-			models.ValidatorEventColumns.LogIndex,
-		),
+		qm.OrderBy("block_number, log_index"),
 	).All(ctx, db)
 	if err != nil {
 		return fmt.Errorf("failed to get validator events: %w", err)
@@ -70,11 +72,7 @@ func SyncValidatorPerformance(
 	var earliestActiveDay time.Time
 	for _, event := range validatorEvents {
 		if event.EventName == eventparser.ValidatorAdded {
-			block, err := ethClient.BlockByNumber(ctx, big.NewInt(int64(event.BlockNumber)))
-			if err != nil {
-				return fmt.Errorf("failed to get earliest block time: %w", err)
-			}
-			earliestActiveDay = time.Unix(int64(block.Time()), 0).UTC().Truncate(24 * time.Hour)
+			earliestActiveDay = event.BlockTime.UTC().Truncate(24 * time.Hour)
 			break
 		}
 	}
@@ -85,12 +83,8 @@ func SyncValidatorPerformance(
 		fromDay = earliestActiveDay
 	}
 
-	// Adjust toDay based on highestBlockNumber and cutoff
-	highestBlock, err := ethClient.BlockByNumber(ctx, big.NewInt(int64(highestBlockNumber)))
-	if err != nil {
-		return fmt.Errorf("failed to get latest block time: %w", err)
-	}
-	highestDay := time.Unix(int64(highestBlock.Time()), 0).
+	// Adjust toDay based on the highest synced block and cutoff
+	highestDay := highestBlockTime.
 		UTC().
 		Truncate(24*time.Hour).
 		AddDate(0, 0, -1)
@@ -118,6 +112,13 @@ func SyncValidatorPerformance(
 	defer bar.Clear()
 	fetchedDays := 0
 	totalDays := 0
+	totalSources := map[string]int{}
+	// previousCoverage is the coverage of the previous day, if it was fetched and
+	// trusted, which the drift of the next day is checked against.
+	var previousCoverage *decidedsCoverage
+
+	decidedsSourceType := decidedsSource.Type()
+	decidedsCacheProvider, cacheDecideds := decidedsCacheProvider(decidedsSource)
 
 	type activeValidator struct {
 		Since        phase0.Epoch
@@ -142,19 +143,13 @@ func SyncValidatorPerformance(
 		validatorsByPubKey[pk] = validator
 	}
 
-	var (
-		inMemorySSVCache   = make(map[string]*ssvCacheItem)
-		inMemorySSVCacheMu sync.Mutex
-	)
-
 	for day := fromDay; !day.After(toDay); day = day.AddDate(0, 0, 1) {
 		bar.Describe(day.Format("2006-01-02"))
 		totalDays++
 
 		// Determine the epoch range for the day.
-		beaconDay := beaconDay(spec, day.Year(), day.Month(), day.Day())
-		fromEpoch := spec.EpochAt(spec.SlotAt(beaconDay))
-		toEpoch := spec.EpochAt(spec.SlotAt(beaconDay.AddDate(0, 0, 1))) - 1
+		beaconDay := spec.DayStart(day)
+		fromEpoch, toEpoch := spec.DayEpochs(day)
 		logger := logger.With(
 			zap.String("day", day.Format("2006-01-02")),
 			zap.Time("beacon_day", beaconDay),
@@ -163,9 +158,8 @@ func SyncValidatorPerformance(
 		)
 
 		// Sanity check.
-		epochsPerDay := time.Hour * 24 / spec.SlotDuration / time.Duration(spec.SlotsPerEpoch)
-		if toEpoch-fromEpoch+1 != phase0.Epoch(epochsPerDay) {
-			return errors.New("epoch range is not exactly a day")
+		if toEpoch-fromEpoch+1 != spec.EpochsPerDay() {
+			return fmt.Errorf("epoch range is not exactly a day (%d epochs of %d)", toEpoch-fromEpoch+1, spec.EpochsPerDay())
 		}
 
 		// Keep track of active validators in this day.
@@ -197,7 +191,9 @@ func SyncValidatorPerformance(
 			}
 		}
 
-		// Skip if already fetched.
+		// Skip if already fetched, unless the day is redone or quarantined. Quarantined
+		// days are fetched again in full, until their decided counts pass the checks.
+		replace, partial := false, false
 		existing, err := models.ValidatorPerformances(
 			models.ValidatorPerformanceWhere.Provider.EQ(providerType),
 			models.ValidatorPerformanceWhere.Day.EQ(day),
@@ -210,8 +206,27 @@ func SyncValidatorPerformance(
 			if existing.FromEpoch != int(fromEpoch) || existing.ToEpoch != int(toEpoch) {
 				return fmt.Errorf("validator performance mismatch: %d-%d != %d-%d", existing.FromEpoch, existing.ToEpoch, fromEpoch, toEpoch)
 			}
-			bar.Add(1)
-			continue
+			quarantined, err := models.QuarantinedDayExists(ctx, db, providerType, day)
+			if err != nil {
+				return fmt.Errorf("failed to check quarantined day: %w", err)
+			}
+			switch {
+			case quarantined:
+				logger.Info("Fetching validator performance of quarantined day")
+			case redo.includes(day):
+				partial = len(redo.PublicKeys) > 0
+				logger.Info("Fetching validator performance to redo", zap.Int("validators", len(redo.PublicKeys)))
+			default:
+				// The day's decided counts aren't fetched, so the next day has no
+				// baseline to check its drift against.
+				previousCoverage = nil
+				bar.Add(1)
+				continue
+			}
+			replace = true
+		}
+		includes := func(pubKey phase0.BLSPubKey) bool {
+			return !partial || redo.includesValidator(hex.EncodeToString(pubKey[:]))
 		}
 
 		var decideds map[string]int
@@ -219,70 +234,70 @@ func SyncValidatorPerformance(
 
 		dutyCountsStart := time.Now()
 
-		// Check memory cache first (no time check needed)
-		inMemorySSVCacheMu.Lock()
-		memItem, found := inMemorySSVCache[dayKey]
-		inMemorySSVCacheMu.Unlock()
-
-		if found {
-			logger.Info("Using SSV data from memory cache", zap.String("day", dayKey))
-			decideds = make(map[string]int, len(memItem.Validators))
-			for k, v := range memItem.Validators {
-				decideds[k] = v
+		// Check cache first
+		var (
+			fetched time.Time
+			found   bool
+		)
+		if cacheDecideds {
+			fetched, found, err = kvCache.Get(decidedsCacheProvider, 0, day, &decideds)
+			if err != nil {
+				return err
 			}
+		}
+		cached := found && fetched.After(day.Add(48*time.Hour))
+		if cached && !decidedsCheck.VerifyCache {
+			logger.Info("Using SSV data from cache", zap.String("day", dayKey))
 		} else {
-			// Check file cache
-			cachedItem, err := loadSSVCache(cacheDir, dayKey)
-			if err == nil && cachedItem.Time.After(day.Add(48*time.Hour)) {
-				logger.Info("Using SSV data from file cache", zap.String("day", dayKey))
-				decideds = make(map[string]int, len(cachedItem.Validators))
-
-				inMemorySSVCacheMu.Lock()
-				inMemorySSVCache[dayKey] = cachedItem // Populate memory cache
-				inMemorySSVCacheMu.Unlock()
-
-				for k, v := range cachedItem.Validators {
-					decideds[k] = v
-				}
-			} else {
-				// Fetch fresh
-				logger.Info("Fetching fresh SSV data", zap.String("day", dayKey))
-
-				var resp struct {
-					Error      string
-					Validators map[string]struct{ Duties int }
-				}
-				url := ssvAPIEndpoint
-				if url[len(url)-1] != '/' {
-					url += "/"
-				}
-				err := requests.URL(url).
-					Client(httpretry.Client).
-					Pathf("%s/validators/duty_counts/%d/%d", spec.Network, fromEpoch, toEpoch).
-					ToJSON(&resp).
-					Fetch(ctx)
-				if err != nil {
-					return fmt.Errorf("failed to get validator duties: %w", err)
-				}
-				if resp.Error != "" {
-					return fmt.Errorf("ssv api returned error: %s", resp.Error)
-				}
-
-				decideds = make(map[string]int, len(resp.Validators))
-				for pubKey, data := range resp.Validators {
-					decideds[pubKey] = data.Duties
-				}
-
-				newItem := &ssvCacheItem{
-					Time:       time.Now(),
-					Validators: decideds,
+			// Fetch fresh
+			logger.Info("Fetching fresh SSV data", zap.String("day", dayKey), zap.String("source", string(decidedsSourceType)))
+			fresh, err := decidedsSource.Decideds(ctx, logger, spec, day, fromEpoch, toEpoch)
+			if err != nil {
+				return fmt.Errorf("failed to get decided counts from %s: %w", decidedsSourceType, err)
+			}
+			if cached {
+				if differences := compareDecideds(decideds, fresh); differences > 0 {
+					logger.Warn("SSV data differs from cache, replacing it",
+						zap.Int("differences", differences),
+						zap.Int("cached", len(decideds)),
+						zap.Int("fresh", len(fresh)),
+					)
 				}
+				cached = false
+			}
+			decideds = fresh
+		}
 
-				inMemorySSVCacheMu.Lock()
-				inMemorySSVCache[dayKey] = newItem
-				inMemorySSVCacheMu.Unlock()
-
-				if err := saveSSVCache(cacheDir, dayKey, *newItem); err != nil {
+		// Check the coverage of the validators that were registered and active
+		// for the whole day before trusting the decided counts.
+		var expected []string
+		for pubKey, activeValidator := range activeValidators {
+			validator, ok := validatorsByPubKey[pubKey]
+			if !ok || activeValidator.Since >= fromEpoch {
+				continue
+			}
+			if phase0.Epoch(validator.BeaconActivationEpoch.Int) <= fromEpoch &&
+				phase0.Epoch(validator.BeaconExitEpoch.Int) > toEpoch {
+				expected = append(expected, hex.EncodeToString(pubKey[:]))
+			}
+		}
+		coverage := decidedsCheck.coverage(decideds, expected)
+		problems := decidedsCheck.problems(coverage, previousCoverage)
+		if len(problems) > 0 {
+			if !decidedsCheck.Quarantine {
+				return fmt.Errorf("untrusted SSV data for %s: %s", dayKey, strings.Join(problems, "; "))
+			}
+			logger.Warn("Quarantined untrusted SSV data, leaving the day's decided counts unset",
+				zap.Strings("problems", problems),
+				zap.Int("expected", coverage.Expected),
+				zap.Int("covered", coverage.Covered),
+			)
+			decideds = nil
+			previousCoverage = nil
+		} else {
+			previousCoverage = &coverage
+			if !cached && cacheDecideds {
+				if err := kvCache.Set(decidedsCacheProvider, 0, time.Now(), cache.Entry{Day: day, Value: decideds}); err != nil {
 					logger.Warn("Failed to save SSV cache", zap.Error(err))
 				}
 			}
@@ -290,13 +305,33 @@ func SyncValidatorPerformance(
 		dutyCountsDuration := time.Since(dutyCountsStart)
 
 		beaconchaStart := time.Now()
+
+		// Let batch providers fetch the day's validators at once.
+		if batchProvider, ok := provider.(performance.BatchProvider); ok {
+			var indices []phase0.ValidatorIndex
+			for pubKey := range activeValidators {
+				if validator, ok := validatorsByPubKey[pubKey]; ok && includes(pubKey) {
+					indices = append(indices, phase0.ValidatorIndex(validator.Index.Int))
+				}
+			}
+			if err := batchProvider.Prepare(ctx, logger, spec, day, fromEpoch, toEpoch, indices); err != nil {
+				return fmt.Errorf("failed to prepare validator performance: %w", err)
+			}
+		}
+
 		performancesPool := pool.New().WithContext(ctx).WithCancelOnError().WithFirstError().WithMaxGoroutines(4)
-		var performances []*models.ValidatorPerformance
-		var performancesMutex sync.Mutex
+		var (
+			performances      []*models.ValidatorPerformance
+			gaps              []performanceGap
+			performancesMutex sync.Mutex
+		)
 		for pubKey, activeValidator := range activeValidators {
+			if !includes(pubKey) {
+				continue
+			}
 			pubKey, activeValidator := pubKey, activeValidator
 			performancesPool.Go(func(ctx context.Context) error {
-				performance := models.ValidatorPerformance{
+				performance := &models.ValidatorPerformance{
 					Provider:        providerType,
 					Day:             day,
 					FromEpoch:       int(fromEpoch),
@@ -305,77 +340,61 @@ func SyncValidatorPerformance(
 					PublicKey:       hex.EncodeToString(pubKey[:]),
 					SolventWholeDay: activeValidator.Since < fromEpoch,
 				}
-				if validator, ok := validatorsByPubKey[pubKey]; ok {
-					performance.Index = null.IntFrom(validator.Index.Int)
-
-					phase0Validator := &phase0.Validator{
-						PublicKey:        pubKey,
-						EffectiveBalance: phase0.Gwei(validator.BeaconEffectiveBalance.Int64),
-						Slashed:          validator.BeaconSlashed.Bool,
-						ActivationEligibilityEpoch: phase0.Epoch(
-							validator.BeaconActivationEligibilityEpoch.Int,
-						),
-						ActivationEpoch:   phase0.Epoch(validator.BeaconActivationEpoch.Int),
-						ExitEpoch:         phase0.Epoch(validator.BeaconExitEpoch.Int),
-						WithdrawableEpoch: phase0.Epoch(validator.BeaconWithdrawableEpoch.Int),
-					}
-					startState := v1.ValidatorToState(
-						phase0Validator,
-						&phase0Validator.EffectiveBalance,
-						fromEpoch,
-						spec.FarFutureEpoch,
-					)
-					endState := v1.ValidatorToState(
-						phase0Validator,
-						&phase0Validator.EffectiveBalance,
-						toEpoch,
-						spec.FarFutureEpoch,
-					)
-					performance.StartBeaconStatus = null.StringFrom(startState.String())
-					performance.EndBeaconStatus = null.StringFrom(endState.String())
-
-					// Measure beaconcha API duration
-					data, err := provider.ValidatorPerformance(
-						ctx,
-						logger,
-						spec,
-						day,
-						fromEpoch,
-						toEpoch,
-						phase0.Epoch(validator.BeaconActivationEpoch.Int),
-						phase0.Epoch(validator.BeaconExitEpoch.Int),
-						phase0.ValidatorIndex(validator.Index.Int),
-					)
-					if err != nil {
-						return fmt.Errorf("failed to get validator performance: %w", err)
-					}
-
-					if data != nil {
-						performance.EndEffectiveBalance = null.Int64From(data.EndEffectiveBalance)
-						performance.Effectiveness = null.Float32FromPtr(data.Effectiveness)
-						performance.AttestationRate = null.Float32From(data.AttestationRate)
-						performance.ProposalsAssigned = null.Int16From(data.Proposals.Assigned)
-						performance.ProposalsExecuted = null.Int16From(data.Proposals.Executed)
-						performance.ProposalsMissed = null.Int16From(data.Proposals.Missed)
-						performance.AttestationsAssigned = null.Int16From(data.Attestations.Assigned)
-						performance.AttestationsExecuted = null.Int16From(data.Attestations.Executed)
-						performance.AttestationsMissed = null.Int16From(data.Attestations.Missed)
-						performance.SyncCommitteeAssigned = null.Int16From(data.SyncCommittee.Assigned)
-						performance.SyncCommitteeExecuted = null.Int16From(data.SyncCommittee.Executed)
-						performance.SyncCommitteeMissed = null.Int16From(data.SyncCommittee.Missed)
-					} else {
-						if startState.IsAttesting() || endState.IsAttesting() {
-							logger.Warn(
-								"missing validator performance",
-								zap.String("public_key", hex.EncodeToString(pubKey[:])),
-								zap.Int("index", validator.Index.Int),
-							)
-						}
-					}
-				}
 				performancesMutex.Lock()
-				performances = append(performances, &performance)
+				performances = append(performances, performance)
 				performancesMutex.Unlock()
+
+				validator, ok := validatorsByPubKey[pubKey]
+				if !ok {
+					return nil
+				}
+				performance.Index = null.IntFrom(validator.Index.Int)
+
+				phase0Validator := &phase0.Validator{
+					PublicKey:        pubKey,
+					EffectiveBalance: phase0.Gwei(validator.BeaconEffectiveBalance.Int64),
+					Slashed:          validator.BeaconSlashed.Bool,
+					ActivationEligibilityEpoch: phase0.Epoch(
+						validator.BeaconActivationEligibilityEpoch.Int,
+					),
+					ActivationEpoch:   phase0.Epoch(validator.BeaconActivationEpoch.Int),
+					ExitEpoch:         phase0.Epoch(validator.BeaconExitEpoch.Int),
+					WithdrawableEpoch: phase0.Epoch(validator.BeaconWithdrawableEpoch.Int),
+				}
+				startState := v1.ValidatorToState(
+					phase0Validator,
+					&phase0Validator.EffectiveBalance,
+					fromEpoch,
+					spec.FarFutureEpoch,
+				)
+				endState := v1.ValidatorToState(
+					phase0Validator,
+					&phase0Validator.EffectiveBalance,
+					toEpoch,
+					spec.FarFutureEpoch,
+				)
+				performance.StartBeaconStatus = null.StringFrom(startState.String())
+				performance.EndBeaconStatus = null.StringFrom(endState.String())
+
+				gap := performanceGap{
+					performance: performance,
+					validator:   validator,
+					attesting:   startState.IsAttesting() || endState.IsAttesting(),
+					reason:      GapReasonMissing,
+				}
+				data, err := fetchPrimaryPerformance(ctx, logger, spec, provider, len(fallbacks) > 0, day, fromEpoch, toEpoch, &gap)
+				if err != nil {
+					return fmt.Errorf("failed to get validator performance: %w", err)
+				}
+
+				performancesMutex.Lock()
+				defer performancesMutex.Unlock()
+				if data == nil {
+					gaps = append(gaps, gap)
+					return nil
+				}
+				applyValidatorPerformance(performance, data)
+				performance.Source = models.NullProviderTypeFrom(providerType)
 				return nil
 			})
 		}
@@ -385,6 +404,35 @@ func SyncValidatorPerformance(
 			return fmt.Errorf("failed waiting for validator performance: %w", err)
 		}
 
+		// Fill in the validators missing from the provider from the fallbacks, in order.
+		for _, fallback := range fallbacks {
+			if len(gaps) == 0 {
+				break
+			}
+			gaps = fillPerformanceGaps(ctx, logger, spec, fallback, day, fromEpoch, toEpoch, gaps)
+		}
+		for _, gap := range gaps {
+			if gap.attesting {
+				logger.Warn(
+					"missing validator performance",
+					zap.String("public_key", gap.performance.PublicKey),
+					zap.Int("index", gap.validator.Index.Int),
+					zap.String("reason", gap.reason),
+				)
+			}
+		}
+
+		// Count the rows by the provider they came from.
+		daySources := map[string]int{}
+		for _, performance := range performances {
+			source := "none"
+			if performance.Source.Valid {
+				source = string(performance.Source.Val)
+			}
+			daySources[source]++
+			totalSources[source]++
+		}
+
 		beaconchaDuration := time.Since(beaconchaStart)
 
 		// Insert ValidatorPerformance records.
@@ -395,9 +443,24 @@ func SyncValidatorPerformance(
 		}
 		defer tx.Rollback()
 
+		// Replace the redone or quarantined rows in the same transaction, so that
+		// they're kept if the day fails.
+		if replace {
+			var publicKeys []string
+			if partial {
+				publicKeys = redo.PublicKeys
+			}
+			deleted, err := deleteDay(ctx, tx, providerType, day, publicKeys)
+			if err != nil {
+				return err
+			}
+			logger.Info("Deleted validator performance to replace", zap.Int64("deleted", deleted))
+		}
+
 		for _, performance := range performances {
 			if decideds, ok := decideds[performance.PublicKey]; ok {
 				performance.Decideds = null.IntFrom(decideds)
+				performance.DecidedsSource = models.NullDecidedsSourceFrom(models.DecidedsSource(decidedsSourceType))
 			}
 		}
 
@@ -420,8 +483,21 @@ func SyncValidatorPerformance(
 		}
 		insertDuration := time.Since(insertStart)
 
-		// Update state consistently within same transaction:
-		_, err = tx.ExecContext(ctx, `UPDATE state SET latest_validator_performance = $1`, day)
+		// Record the validators that were attesting without performance, and resolve
+		// earlier gaps of the validators that now have performance.
+		if err := recordPerformanceGaps(ctx, tx, providerType, day, gaps); err != nil {
+			return err
+		}
+
+		// Record the day if its decided counts were quarantined, or release it if they
+		// now pass the checks.
+		if err := recordQuarantine(ctx, tx, providerType, day, decidedsSourceType, problems); err != nil {
+			return err
+		}
+
+		// Update state consistently within same transaction. Redone days may be
+		// earlier than the latest day.
+		_, err = tx.ExecContext(ctx, `UPDATE state SET latest_validator_performance = GREATEST(latest_validator_performance, $1)`, day)
 		if err != nil {
 			return err
 		}
@@ -441,6 +517,7 @@ func SyncValidatorPerformance(
 			zap.Duration("insert_duration", insertDuration),
 			zap.Duration("commit_duration", commitDuration),
 			zap.Duration("beaconcha_total_duration", beaconchaDuration),
+			zap.Any("sources", daySources),
 		)
 	}
 	bar.Clear()
@@ -449,11 +526,17 @@ func SyncValidatorPerformance(
 		zap.Time("to", toDay),
 		zap.Int("total_days", totalDays),
 		zap.Int("fetched_days", fetchedDays),
+		zap.Any("sources", totalSources),
 	)
 	return nil
 }
 
-func BulkInsertValidatorPerformances(ctx context.Context, tx *sqlx.Tx, performances []*models.ValidatorPerformance) error {
+// BulkInsertValidatorPerformances inserts validator performances.
+func BulkInsertValidatorPerformances(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	performances []*models.ValidatorPerformance,
+) error {
 	if len(performances) == 0 {
 		return nil
 	}
@@ -463,7 +546,8 @@ func BulkInsertValidatorPerformances(ctx context.Context, tx *sqlx.Tx, performan
 		start_beacon_status, end_beacon_status, end_effective_balance, effectiveness,
 		attestation_rate, proposals_assigned, proposals_executed, proposals_missed,
 		attestations_assigned, attestations_executed, attestations_missed,
-		sync_committee_assigned, sync_committee_executed, sync_committee_missed, decideds`
+		sync_committee_assigned, sync_committee_executed, sync_committee_missed, decideds, source,
+		decideds_source`
 
 	query := fmt.Sprintf("INSERT INTO validator_performances (%s) VALUES ", cols)
 
@@ -474,15 +558,16 @@ func BulkInsertValidatorPerformances(ctx context.Context, tx *sqlx.Tx, performan
 	)
 
 	for _, p := range performances {
-		valueParts = append(valueParts, fmt.Sprintf("(%s)", strings.Join(generatePlaceholders(paramIndex, 23), ", ")))
+		valueParts = append(valueParts, fmt.Sprintf("(%s)", strings.Join(generatePlaceholders(paramIndex, 25), ", ")))
 		args = append(args,
 			p.Provider, p.Day, p.FromEpoch, p.ToEpoch, p.OwnerAddress, p.PublicKey, p.SolventWholeDay, p.Index,
 			p.StartBeaconStatus, p.EndBeaconStatus, p.EndEffectiveBalance, p.Effectiveness,
 			p.AttestationRate, p.ProposalsAssigned, p.ProposalsExecuted, p.ProposalsMissed,
 			p.AttestationsAssigned, p.AttestationsExecuted, p.AttestationsMissed,
-			p.SyncCommitteeAssigned, p.SyncCommitteeExecuted, p.SyncCommitteeMissed, p.Decideds,
+			p.SyncCommitteeAssigned, p.SyncCommitteeExecuted, p.SyncCommitteeMissed, p.Decideds, p.Source,
+			p.DecidedsSource,
 		)
-		paramIndex += 23
+		paramIndex += 25
 	}
 
 	query += strings.Join(valueParts, ", ")
@@ -494,6 +579,157 @@ func BulkInsertValidatorPerformances(ctx context.Context, tx *sqlx.Tx, performan
 	return nil
 }
 
+// performanceGap is a validator that a provider has no performance for in a day.
+type performanceGap struct {
+	performance *models.ValidatorPerformance
+	validator   *models.Validator
+	attesting   bool
+
+	// reason is why the gap has no performance, one of the GapReason constants.
+	reason string
+
+	// providerFailed is whether the performance provider failed for the validator,
+	// which is the gap's reason unless a fallback fails as well.
+	providerFailed bool
+}
+
+func fetchValidatorPerformance(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	provider performance.Provider,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	validator *models.Validator,
+) (*performance.ValidatorPerformance, error) {
+	return provider.ValidatorPerformance(
+		ctx,
+		logger,
+		spec,
+		day,
+		fromEpoch,
+		toEpoch,
+		phase0.Epoch(validator.BeaconActivationEpoch.Int),
+		phase0.Epoch(validator.BeaconExitEpoch.Int),
+		phase0.ValidatorIndex(validator.Index.Int),
+	)
+}
+
+// fetchPrimaryPerformance fetches the performance of a gap's validator from the
+// performance provider. If there are fallbacks, errors of the provider are logged and
+// leave the validator to them with GapReasonProviderError, rather than failing the
+// sync, unless the sync was cancelled.
+func fetchPrimaryPerformance(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	provider performance.Provider,
+	hasFallbacks bool,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	gap *performanceGap,
+) (*performance.ValidatorPerformance, error) {
+	data, err := fetchValidatorPerformance(ctx, logger, spec, provider, day, fromEpoch, toEpoch, gap.validator)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !hasFallbacks {
+			return nil, err
+		}
+		logger.Warn("failed to get validator performance",
+			zap.String("public_key", gap.performance.PublicKey),
+			zap.Error(err),
+		)
+		gap.reason = GapReasonProviderError
+		gap.providerFailed = true
+		return nil, nil
+	}
+	return data, nil
+}
+
+// fillPerformanceGaps fetches the performance of the given gaps from a fallback provider,
+// and returns the gaps it has no performance for either. Errors of the fallback are
+// logged and leave the gaps to the next fallback, rather than failing the sync.
+func fillPerformanceGaps(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	fallback performance.Provider,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	gaps []performanceGap,
+) []performanceGap {
+	logger = logger.With(zap.String("fallback", string(fallback.Type())))
+	if batchProvider, ok := fallback.(performance.BatchProvider); ok {
+		indices := make([]phase0.ValidatorIndex, len(gaps))
+		for i, gap := range gaps {
+			indices[i] = phase0.ValidatorIndex(gap.validator.Index.Int)
+		}
+		if err := batchProvider.Prepare(ctx, logger, spec, day, fromEpoch, toEpoch, indices); err != nil {
+			logger.Warn("failed to prepare fallback validator performance", zap.Error(err))
+			for i := range gaps {
+				gaps[i].reason = GapReasonFallbackError
+			}
+			return gaps
+		}
+	}
+
+	var (
+		remaining []performanceGap
+		mu        sync.Mutex
+	)
+	p := pool.New().WithMaxGoroutines(4)
+	for _, gap := range gaps {
+		gap := gap
+		p.Go(func() {
+			data, err := fetchValidatorPerformance(ctx, logger, spec, fallback, day, fromEpoch, toEpoch, gap.validator)
+			gap.reason = GapReasonMissing
+			if gap.providerFailed {
+				gap.reason = GapReasonProviderError
+			}
+			if err != nil {
+				logger.Warn("failed to get fallback validator performance",
+					zap.String("public_key", gap.performance.PublicKey),
+					zap.Error(err),
+				)
+				gap.reason = GapReasonFallbackError
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if data == nil {
+				remaining = append(remaining, gap)
+				return
+			}
+			applyValidatorPerformance(gap.performance, data)
+			gap.performance.Source = models.NullProviderTypeFrom(models.ProviderType(fallback.Type()))
+		})
+	}
+	p.Wait()
+
+	logger.Info("Filled validator performance from fallback",
+		zap.Time("day", day),
+		zap.Int("filled", len(gaps)-len(remaining)),
+		zap.Int("remaining", len(remaining)),
+	)
+	return remaining
+}
+
+func applyValidatorPerformance(p *models.ValidatorPerformance, data *performance.ValidatorPerformance) {
+	p.EndEffectiveBalance = null.Int64From(data.EndEffectiveBalance)
+	p.Effectiveness = null.Float32FromPtr(data.Effectiveness)
+	p.AttestationRate = null.Float32From(data.AttestationRate)
+	p.ProposalsAssigned = null.Int16From(data.Proposals.Assigned)
+	p.ProposalsExecuted = null.Int16From(data.Proposals.Executed)
+	p.ProposalsMissed = null.Int16From(data.Proposals.Missed)
+	p.AttestationsAssigned = null.Int16From(data.Attestations.Assigned)
+	p.AttestationsExecuted = null.Int16From(data.Attestations.Executed)
+	p.AttestationsMissed = null.Int16From(data.Attestations.Missed)
+	p.SyncCommitteeAssigned = null.Int16From(data.SyncCommittee.Assigned)
+	p.SyncCommitteeExecuted = null.Int16From(data.SyncCommittee.Executed)
+	p.SyncCommitteeMissed = null.Int16From(data.SyncCommittee.Missed)
+}
+
 // generatePlaceholders generates PostgreSQL placeholders like $1, $2, ...
 func generatePlaceholders(start, count int) []string {
 	placeholders := make([]string, count)
@@ -514,49 +750,50 @@ func decodeValidatorPublicKey(hexEncoded string) (phase0.BLSPubKey, error) {
 	return phase0.BLSPubKey(pk), nil
 }
 
+// decidedsCacheProvider returns the cache provider of a source's decided counts, which
+// are cached unless the source is local.
+func decidedsCacheProvider(source decideds.Source) (string, bool) {
+	if _, ok := source.(decideds.LocalSource); ok {
+		return "", false
+	}
+	if source.Type() == ssvapi.SourceType {
+		return SSVCacheProvider, true
+	}
+	return string(source.Type()), true
+}
+
+// SSVCacheProvider is the cache provider of the SSV API's duty counts, which are
+// cached per day.
+const SSVCacheProvider = "ssv"
+
 type ssvCacheItem struct {
 	Time       time.Time      `json:"time"`
 	Validators map[string]int `json:"validators"`
 }
 
-func loadSSVCache(cacheDir, date string) (*ssvCacheItem, error) {
-	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%s.json", date))
-	data, err := os.ReadFile(cachePath)
+// ImportSSVCacheDir imports the JSON files of days that were cached before the cache
+// was a key-value store, and returns the number of imported days.
+func ImportSSVCacheDir(kvCache *cache.Cache, dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
 	if err != nil {
-		return nil, err
-	}
-
-	var item ssvCacheItem
-	if err := json.Unmarshal(data, &item); err != nil {
-		return nil, err
-	}
-	return &item, nil
-}
-
-func saveSSVCache(cacheDir, date string, item ssvCacheItem) error {
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return err
+		return 0, err
 	}
-
-	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%s.json", date))
-	data, err := json.MarshalIndent(item, "", "  ")
-	if err != nil {
-		return err
+	for _, file := range files {
+		day, err := time.Parse("2006-01-02", strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid cache file name %q: %w", file, err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		var item ssvCacheItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return 0, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		if err := kvCache.Set(SSVCacheProvider, 0, item.Time, cache.Entry{Day: day, Value: item.Validators}); err != nil {
+			return 0, err
+		}
 	}
-	return os.WriteFile(cachePath, data, 0644)
-}
-
-// beaconDay returns the time of the first slot of the given day,
-// counting from the Beacon genesis time.
-func beaconDay(spec beacon.Spec, year int, month time.Month, day int) time.Time {
-	return time.Date(
-		year,
-		month,
-		day,
-		spec.GenesisTime.Hour(),
-		spec.GenesisTime.Minute(),
-		spec.GenesisTime.Second(),
-		spec.GenesisTime.Nanosecond(),
-		time.UTC,
-	)
+	return len(files), nil
 }
@@ -0,0 +1,148 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv-rewards/pkg/beacon"
+	"github.com/bloxapp/ssv-rewards/pkg/models"
+	"github.com/bloxapp/ssv-rewards/pkg/sync/performance"
+)
+
+// mockProvider has performance for the given validator indices, with the index as
+// the number of executed attestations.
+type mockProvider struct {
+	providerType performance.ProviderType
+	indices      map[phase0.ValidatorIndex]bool
+	err          error
+	prepared     []phase0.ValidatorIndex
+}
+
+func (p *mockProvider) Type() performance.ProviderType {
+	return p.providerType
+}
+
+func (p *mockProvider) Prepare(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) error {
+	p.prepared = append(p.prepared, indices...)
+	return nil
+}
+
+func (p *mockProvider) ValidatorPerformance(
+	ctx context.Context,
+	logger *zap.Logger,
+	spec beacon.Spec,
+	day time.Time,
+	fromEpoch, toEpoch, activationEpoch, exitEpoch phase0.Epoch,
+	index phase0.ValidatorIndex,
+) (*performance.ValidatorPerformance, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if !p.indices[index] {
+		return nil, nil
+	}
+	return &performance.ValidatorPerformance{
+		Attestations: performance.DutyPerformance{Executed: int16(index)},
+	}, nil
+}
+
+func TestFetchPrimaryPerformance(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gap := performanceGap{
+		performance: &models.ValidatorPerformance{PublicKey: "a"},
+		validator:   &models.Validator{Index: null.IntFrom(1)},
+		reason:      GapReasonMissing,
+	}
+
+	// Without fallbacks, a failing provider fails the sync.
+	failing := &mockProvider{providerType: "file", err: errors.New("no performance for validator 1")}
+	_, err := fetchPrimaryPerformance(ctx, logger, spec, failing, false, day, 0, 224, &gap)
+	require.ErrorContains(t, err, "no performance for validator 1")
+	require.Equal(t, GapReasonMissing, gap.reason)
+
+	// With fallbacks, it leaves the validator to them.
+	data, err := fetchPrimaryPerformance(ctx, logger, spec, failing, true, day, 0, 224, &gap)
+	require.NoError(t, err)
+	require.Nil(t, data)
+	require.Equal(t, GapReasonProviderError, gap.reason)
+
+	// Unless the sync was cancelled.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = fetchPrimaryPerformance(cancelled, logger, spec, failing, true, day, 0, 224, &gap)
+	require.ErrorIs(t, err, context.Canceled)
+
+	provider := &mockProvider{providerType: "beaconnode", indices: map[phase0.ValidatorIndex]bool{1: true}}
+	data, err = fetchPrimaryPerformance(ctx, logger, spec, provider, false, day, 0, 224, &gap)
+	require.NoError(t, err)
+	require.Equal(t, int16(1), data.Attestations.Executed)
+}
+
+func TestFillPerformanceGaps(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	spec := beacon.Spec{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second, FarFutureEpoch: math.MaxUint64}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var gaps []performanceGap
+	for _, index := range []int{1, 2, 3} {
+		gaps = append(gaps, performanceGap{
+			performance: &models.ValidatorPerformance{PublicKey: string(rune('a' + index - 1))},
+			validator:   &models.Validator{Index: null.IntFrom(index)},
+		})
+	}
+
+	// A failing fallback leaves all gaps to the next one.
+	failing := &mockProvider{providerType: "e2m", err: errors.New("unavailable")}
+	remaining := fillPerformanceGaps(ctx, logger, spec, failing, day, 0, 224, gaps)
+	require.Len(t, remaining, 3)
+	for _, gap := range remaining {
+		require.Equal(t, GapReasonFallbackError, gap.reason)
+		require.False(t, gap.performance.Source.Valid)
+	}
+
+	beaconNode := &mockProvider{providerType: "beaconnode", indices: map[phase0.ValidatorIndex]bool{1: true, 3: true}}
+	remaining = fillPerformanceGaps(ctx, logger, spec, beaconNode, day, 0, 224, remaining)
+	require.ElementsMatch(t, []phase0.ValidatorIndex{1, 2, 3}, beaconNode.prepared)
+	require.Len(t, remaining, 1)
+	require.Equal(t, "b", remaining[0].performance.PublicKey)
+	require.Equal(t, GapReasonMissing, remaining[0].reason)
+	require.Equal(t, models.NullProviderTypeFrom(models.ProviderTypeBeaconnode), gaps[0].performance.Source)
+	require.False(t, gaps[1].performance.Source.Valid)
+	require.Equal(t, models.NullProviderTypeFrom(models.ProviderTypeBeaconnode), gaps[2].performance.Source)
+	require.Equal(t, null.Int16From(1), gaps[0].performance.AttestationsExecuted)
+	require.Equal(t, null.Int16From(3), gaps[2].performance.AttestationsExecuted)
+	require.False(t, gaps[1].performance.AttestationsExecuted.Valid)
+
+	// Gaps the provider failed for keep their reason if the fallbacks have no
+	// performance for them.
+	failed := []performanceGap{{
+		performance:    &models.ValidatorPerformance{PublicKey: "d"},
+		validator:      &models.Validator{Index: null.IntFrom(4)},
+		reason:         GapReasonProviderError,
+		providerFailed: true,
+	}}
+	remaining = fillPerformanceGaps(ctx, logger, spec, failing, day, 0, 224, failed)
+	require.Equal(t, GapReasonFallbackError, remaining[0].reason)
+	remaining = fillPerformanceGaps(ctx, logger, spec, beaconNode, day, 0, 224, remaining)
+	require.Len(t, remaining, 1)
+	require.Equal(t, GapReasonProviderError, remaining[0].reason)
+}
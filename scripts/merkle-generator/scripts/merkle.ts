@@ -12,8 +12,13 @@ async function makeDrop() {
     // Read the JSON file
     const data = fs.readFileSync(path.join(__dirname, 'input_1.json'), 'utf-8');
 
-    // Parse the JSON, converting large integers to BigInt
-    const leavesJson = JSON.parse(data);
+    // Parse the JSON, converting large integers to BigInt. cumulative-eth.json wraps
+    // the rewards along with their denomination.
+    const parsed = JSON.parse(data);
+    const leavesJson = parsed.rewards ?? parsed;
+    if (parsed.denomination) {
+        console.log("denomination", parsed.denomination);
+    }
 
     // Convert the JSON object to an array of [key, value] pairs
     const leavesArray = Object.entries(leavesJson);